@@ -0,0 +1,107 @@
+package hilink
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// configCache is a TTL cache of the static configuration responses (ie,
+// config/global/config.xml), which do not change while the device is
+// running.
+type configCache struct {
+	ttl     time.Duration
+	entries map[string]configCacheEntry
+	sync.Mutex
+}
+
+// configCacheEntry is a cached response.
+type configCacheEntry struct {
+	res     XMLData
+	expires time.Time
+}
+
+// newConfigCache creates a config cache with the ttl.
+func newConfigCache(ttl time.Duration) *configCache {
+	return &configCache{
+		ttl:     ttl,
+		entries: make(map[string]configCacheEntry),
+	}
+}
+
+// get returns a copy of the cached response for the path.
+func (c *configCache) get(path string) (XMLData, bool) {
+	c.Lock()
+	defer c.Unlock()
+	e, ok := c.entries[path]
+	switch {
+	case !ok:
+		return nil, false
+	case time.Now().After(e.expires):
+		delete(c.entries, path)
+		return nil, false
+	}
+	return copyXML(map[string]interface{}(e.res)).(map[string]interface{}), true
+}
+
+// set caches a copy of the response for the path.
+func (c *configCache) set(path string, res XMLData) {
+	c.Lock()
+	defer c.Unlock()
+	c.entries[path] = configCacheEntry{
+		res:     copyXML(map[string]interface{}(res)).(map[string]interface{}),
+		expires: time.Now().Add(c.ttl),
+	}
+}
+
+// clear removes all cached responses.
+func (c *configCache) clear() {
+	c.Lock()
+	defer c.Unlock()
+	c.entries = make(map[string]configCacheEntry)
+}
+
+// copyXML returns a deep copy of a decoded XML value, so that callers
+// modifying a cached response do not modify the cache.
+func copyXML(v interface{}) interface{} {
+	switch x := v.(type) {
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(x))
+		for k, z := range x {
+			m[k] = copyXML(z)
+		}
+		return m
+	case []interface{}:
+		s := make([]interface{}, len(x))
+		for i, z := range x {
+			s[i] = copyXML(z)
+		}
+		return s
+	}
+	return v
+}
+
+// doConfig retrieves the static configuration at the path, using the config
+// cache when enabled with WithConfigCache.
+func (cl *Client) doConfig(ctx context.Context, path string) (XMLData, error) {
+	if cl.cache == nil {
+		return cl.Do(ctx, path, nil)
+	}
+	if res, ok := cl.cache.get(path); ok {
+		return res, nil
+	}
+	res, err := cl.Do(ctx, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	cl.cache.set(path, res)
+	return res, nil
+}
+
+// ClearConfigCache clears the responses cached by the config cache (ie,
+// after a firmware update or device reset).
+func (cl *Client) ClearConfigCache() {
+	if cl.cache != nil {
+		cl.cache.clear()
+	}
+}
@@ -0,0 +1,85 @@
+package hilink
+
+import "context"
+
+// Capabilities are the capabilities of a device, as detected from the
+// device's feature switches, network mode list, and device information.
+type Capabilities struct {
+	Model           string
+	HardwareVersion string
+	SoftwareVersion string
+	HasWifi         bool
+	HasWifi5GHz     bool
+	HasVoice        bool
+	HasSDCard       bool
+	HasUSSD         bool
+	HasSMS          bool
+	HasPhonebook    bool
+	HasIPv6         bool
+	HasStaticRoute  bool
+	// Bands are the supported LTE bands.
+	Bands Band
+	// NetworkModes are the supported network modes.
+	NetworkModes []NetworkMode
+}
+
+// Capabilities detects the capabilities of the device. Feature information
+// not available on the device (ie, when an endpoint is not supported by the
+// firmware) is treated as the feature not being present.
+func (cl *Client) Capabilities(ctx context.Context) (*Capabilities, error) {
+	info, err := cl.DeviceInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	c := &Capabilities{
+		Model:           xmlStr(info, "DeviceName"),
+		HardwareVersion: xmlStr(info, "HardwareVersion"),
+		SoftwareVersion: xmlStr(info, "SoftwareVersion"),
+	}
+	enabled := func(m XMLData, keys ...string) bool {
+		for _, k := range keys {
+			if xmlStr(m, k) == "1" {
+				return true
+			}
+		}
+		return false
+	}
+	if g, err := cl.GlobalFeatures(ctx); err == nil {
+		c.HasWifi = enabled(g, "wifi_enabled")
+		c.HasVoice = enabled(g, "voip_enabled", "cs_enable")
+		c.HasSDCard = enabled(g, "sdcard_enabled")
+		c.HasUSSD = enabled(g, "ussd_enabled")
+		c.HasSMS = enabled(g, "sms_enabled")
+		c.HasPhonebook = enabled(g, "pb_enabled")
+		c.HasIPv6 = enabled(g, "ipv6_enabled")
+		c.HasStaticRoute = enabled(g, "static_route_enabled")
+	}
+	if d, err := cl.DeviceFeatures(ctx); err == nil {
+		c.HasVoice = c.HasVoice || enabled(d, "voip_enabled", "voice_enabled")
+	}
+	if w, err := cl.WifiFeatures(ctx); err == nil {
+		c.HasWifi5GHz = enabled(w, "wifi5g_enabled")
+	}
+	if l, err := cl.ModeList(ctx); err == nil {
+		if access, ok := l["AccessList"].(map[string]interface{}); ok {
+			switch x := access["Access"].(type) {
+			case string:
+				c.NetworkModes = append(c.NetworkModes, NetworkMode(x))
+			case []interface{}:
+				for _, z := range x {
+					if s, ok := z.(string); ok {
+						c.NetworkModes = append(c.NetworkModes, NetworkMode(s))
+					}
+				}
+			}
+		}
+		if bands, ok := l["LTEBandList"].(map[string]interface{}); ok {
+			for _, b := range xmlList(bands["LTEBand"]) {
+				if v, err := ParseBand(xmlStr(b, "Value")); err == nil && v != BandAll {
+					c.Bands |= v
+				}
+			}
+		}
+	}
+	return c, nil
+}
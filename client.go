@@ -3,11 +3,14 @@ package hilink
 import (
 	"context"
 	"crypto/sha256"
+	"crypto/tls"
 	"encoding/base64"
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
+	"net"
 	"net/http"
 	"net/http/cookiejar"
 	"net/url"
@@ -16,7 +19,7 @@ import (
 	"sync"
 	"time"
 
-	"github.com/clbanning/mxj/v2"
+	"github.com/kenshaw/hilink/pdu"
 	"github.com/kenshaw/httplog"
 )
 
@@ -29,31 +32,82 @@ const (
 	DefaultURL = "http://192.168.8.1/"
 	// DefaultTimeout is the default timeout.
 	DefaultTimeout = 10 * time.Second
+	// NetworkScanTimeout is the timeout used for network scans.
+	NetworkScanTimeout = 2 * time.Minute
+	// RebootTimeout is the default overall timeout for RebootAndWait.
+	RebootTimeout = 3 * time.Minute
 	// TokenHeader is the header used by the WebUI for CSRF tokens.
 	TokenHeader = "__RequestVerificationToken"
 )
 
 // Client represents a Hilink client connection.
 type Client struct {
-	endpoint  string
-	nostart   bool
-	started   bool
-	authID    string
-	authPW    string
-	cl        *http.Client
-	token     string
-	transport http.RoundTripper
+	endpoint      string
+	nostart       bool
+	noRedact      bool
+	authID        string
+	authPW        string
+	authB64       string
+	cl            *http.Client
+	transport     http.RoundTripper
+	tlsConfig     *tls.Config
+	limiter       *limiter
+	cache         *configCache
+	tracer        Tracer
+	profile       *DeviceProfile
+	autoProfile   bool
+	headers       http.Header
+	requestHooks  []func(*http.Request)
+	responseHooks []func(*http.Response, []byte)
+	// optErr is the first invalid option error, returned by NewClientE.
+	optErr error
+	// sess is the session state, shared with clients derived using With.
+	sess *session
+	// the embedded mutex only guards the client state (ie, the http client),
+	// and is not held during requests.
+	sync.Mutex
+}
+
+// session is the device session state.
+type session struct {
+	token   string
+	started bool
+	// startMu serializes starting the session, and postMu serializes POST
+	// requests, as each consumes the current token. The embedded mutex only
+	// guards the session state (ie, token), and is not held during requests.
+	startMu sync.Mutex
+	postMu  sync.Mutex
+	// profile is the detected device profile, guarded by profileMu.
+	profile   *DeviceProfile
+	profileMu sync.Mutex
 	sync.Mutex
 }
 
+// tok returns the current CSRF token.
+func (s *session) tok() string {
+	s.Lock()
+	defer s.Unlock()
+	return s.token
+}
+
+// setTok sets the current CSRF token.
+func (s *session) setTok(token string) {
+	s.Lock()
+	defer s.Unlock()
+	s.token = token
+}
+
 // NewClient creates a new client a Hilink device.
 func NewClient(opts ...ClientOption) *Client {
 	// create client
+	jar, _ := cookiejar.New(nil)
 	c := &Client{
 		endpoint: DefaultURL,
 		cl: &http.Client{
+			Jar:     jar,
 			Timeout: DefaultTimeout,
 		},
+		sess: new(session),
 	}
 	// process options
 	for _, o := range opts {
@@ -62,10 +116,74 @@ func NewClient(opts ...ClientOption) *Client {
 	return c
 }
 
+// NewClientE creates a new client for a Hilink device, validating the options
+// (ie, the URL endpoint, auth, and timeout) up front, instead of failing on
+// the first request.
+func NewClientE(opts ...ClientOption) (*Client, error) {
+	c := NewClient(opts...)
+	if c.optErr != nil {
+		return nil, c.optErr
+	}
+	return c, nil
+}
+
+// With returns a new client derived from the client, with the options
+// applied, for calls needing different settings (ie, a longer timeout, or a
+// different logger) in an otherwise differently configured program:
+//
+//	res, err := cl.With(hilink.WithTimeout(hilink.NetworkScanTimeout)).NetworkScan(ctx)
+//
+// The derived client shares the session (ie, the session cookie, CSRF token,
+// and rate limit) with the client, and a copy of the client's http client,
+// to which the options are applied. As such, the derived client should only
+// be used with the same URL endpoint, and transport options (ie, WithLogf)
+// wrap the client's transport. Invalid options are ignored (see NewClientE).
+func (cl *Client) With(opts ...ClientOption) *Client {
+	cl.Lock()
+	httpClient := *cl.cl
+	c := &Client{
+		endpoint:      cl.endpoint,
+		nostart:       cl.nostart,
+		noRedact:      cl.noRedact,
+		authID:        cl.authID,
+		authPW:        cl.authPW,
+		authB64:       cl.authB64,
+		cl:            &httpClient,
+		transport:     cl.transport,
+		tlsConfig:     cl.tlsConfig,
+		limiter:       cl.limiter,
+		cache:         cl.cache,
+		tracer:        cl.tracer,
+		profile:       cl.profile,
+		autoProfile:   cl.autoProfile,
+		headers:       cl.headers.Clone(),
+		requestHooks:  append([]func(*http.Request){}, cl.requestHooks...),
+		responseHooks: append([]func(*http.Response, []byte){}, cl.responseHooks...),
+		sess:          cl.sess,
+	}
+	cl.Unlock()
+	for _, o := range opts {
+		o(c)
+	}
+	return c
+}
+
+// invalidOption records the first invalid option error.
+func (cl *Client) invalidOption(err error) {
+	if cl.optErr == nil {
+		cl.optErr = err
+	}
+}
+
 // buildRequest creates a request for use with the Client.
 func (cl *Client) buildRequest(urlstr string, v interface{}) (*http.Request, error) {
 	if v == nil {
-		return http.NewRequest("GET", urlstr, nil)
+		req, err := http.NewRequest("GET", urlstr, nil)
+		if err != nil {
+			return nil, err
+		}
+		cl.setHeaders(req)
+		return req, nil
 	}
 	// encode xml
 	body, err := xmlEncode(v)
@@ -77,21 +195,35 @@ func (cl *Client) buildRequest(urlstr string, v interface{}) (*http.Request, err
 	if err != nil {
 		return nil, err
 	}
+	cl.setHeaders(req)
 	// set content type and CSRF token
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded; charset=UTF-8")
-	req.Header.Set(TokenHeader, cl.token)
+	req.Header.Set(TokenHeader, cl.sess.tok())
 	return req, nil
 }
 
+// setHeaders sets the headers given with WithUserAgent and WithHeader on the
+// request.
+func (cl *Client) setHeaders(req *http.Request) {
+	for k, v := range cl.headers {
+		req.Header[k] = append([]string(nil), v...)
+	}
+}
+
 func (cl *Client) start(ctx context.Context) error {
-	cl.Lock()
-	defer cl.Unlock()
-	if !cl.nostart {
+	// skip requests made while starting
+	if ctx.Value(startKey{}) != nil {
 		return nil
 	}
-	if cl.started {
+	cl.sess.startMu.Lock()
+	defer cl.sess.startMu.Unlock()
+	cl.sess.Lock()
+	skip := !cl.nostart || cl.sess.started
+	cl.sess.Unlock()
+	if skip {
 		return nil
 	}
+	ctx = context.WithValue(ctx, startKey{}, true)
 	// retrieve session id
 	sessID, tokID, err := cl.NewSessionAndTokenID(ctx)
 	if err != nil {
@@ -105,10 +237,29 @@ func (cl *Client) start(ctx context.Context) error {
 	if _, err := cl.login(ctx); err != nil {
 		return err
 	}
-	cl.started = true
+	cl.sess.Lock()
+	cl.sess.started = true
+	cl.sess.Unlock()
 	return nil
 }
 
+// startKey is the context key for requests made while starting.
+type startKey struct{}
+
+// Relogin starts a new session, logging in again when the Auth option was
+// given (ie, after the device has expired the session or rebooted).
+func (cl *Client) Relogin(ctx context.Context) error {
+	sessID, tokID, err := cl.NewSessionAndTokenID(ctx)
+	if err != nil {
+		return err
+	}
+	if err := cl.SetSessionAndTokenID(sessID, tokID); err != nil {
+		return err
+	}
+	_, err = cl.login(ctx)
+	return err
+}
+
 // login authentifies the user using the user identifier and password given
 // with the Auth option. Return nil if succeeded, or no Auth option
 // was given, or the identifier is an empty string.
@@ -116,50 +267,130 @@ func (cl *Client) login(ctx context.Context) (bool, error) {
 	if cl.authID == "" {
 		return false, nil
 	}
-	// encode hashed password
-	h := sha256.Sum256([]byte(cl.authPW + cl.token))
-	tokenizedPW := base64.RawStdEncoding.EncodeToString([]byte(hex.EncodeToString(h[:])))
+	typ, pw := cl.passwordType(ctx), cl.authB64
+	if typ == PasswordTypeSHA256 {
+		// encode hashed password
+		h := sha256.Sum256([]byte(cl.authPW + cl.sess.tok()))
+		pw = base64.RawStdEncoding.EncodeToString([]byte(hex.EncodeToString(h[:])))
+	}
 	return cl.doReqCheckOK(ctx, "api/user/login", XMLData{
 		"Username":      cl.authID,
-		"Password":      tokenizedPW,
-		"password_type": 4,
+		"Password":      pw,
+		"password_type": int(typ),
 	})
 }
 
 // doReq sends a request to the server with the provided path. If data is nil,
 // then GET will be used as the HTTP method, otherwise POST will be used.
-func (cl *Client) doReq(ctx context.Context, path string, v interface{}, takeFirstEl bool) (interface{}, error) {
-	if err := cl.start(ctx); err != nil {
+func (cl *Client) doReq(ctx context.Context, path string, v interface{}, takeFirstEl bool) (_ interface{}, err error) {
+	ctx, end := cl.startSpan(ctx, path, v)
+	defer func() { end(err) }()
+	body, err := cl.doRaw(ctx, path, v)
+	if err != nil {
 		return nil, err
 	}
-	cl.Lock()
-	defer cl.Unlock()
+	// decode, retrying with the path used by the device model when the path
+	// is not supported (see WithAutoProfile)
+	res, err := xmlDecode(body, takeFirstEl)
+	if errors.Is(err, ErrNotSupported) && cl.detectProfile(ctx, path) {
+		if body, err = cl.doRaw(ctx, path, v); err != nil {
+			return nil, err
+		}
+		return xmlDecode(body, takeFirstEl)
+	}
+	return res, err
+}
+
+// doRaw sends a request to the server with the provided path, returning the
+// undecoded response body.
+func (cl *Client) doRaw(ctx context.Context, path string, v interface{}) ([]byte, error) {
+	res, done, err := cl.doStream(ctx, path, v)
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+	// read body
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	return body, cl.checkResponse(res, body)
+}
+
+// doStream sends a request to the server with the provided path, returning
+// the response with its body unread, and a func to close the body, which must
+// be called when done with the response.
+func (cl *Client) doStream(ctx context.Context, path string, v interface{}) (*http.Response, func(), error) {
+	path, err := cl.resolvePath(ctx, path)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := cl.start(ctx); err != nil {
+		return nil, nil, err
+	}
+	// wait for rate limit
+	if cl.limiter != nil {
+		if err := cl.limiter.wait(ctx); err != nil {
+			return nil, nil, err
+		}
+	}
+	// serialize requests consuming the token (POST), while allowing
+	// concurrent GET requests
+	unlock := func() {}
+	if v != nil {
+		cl.sess.postMu.Lock()
+		unlock = cl.sess.postMu.Unlock
+	}
 	// build request
+	cl.Lock()
 	req, err := cl.buildRequest(cl.endpoint+path, v)
+	httpClient := cl.cl
+	cl.Unlock()
 	if err != nil {
-		return nil, err
+		unlock()
+		return nil, nil, err
+	}
+	// override timeout (see WithCallTimeout)
+	if d, ok := ctx.Value(timeoutKey{}).(time.Duration); ok {
+		c := *httpClient
+		c.Timeout = d
+		httpClient = &c
 	}
+	// request hooks
+	for _, f := range cl.requestHooks {
+		f(req)
+	}
+	traceRequest(ctx, req)
 	// do request
-	res, err := cl.cl.Do(req.WithContext(ctx))
+	res, err := httpClient.Do(req.WithContext(cl.logContext(ctx)))
 	if err != nil {
-		return nil, err
+		unlock()
+		return nil, nil, err
+	}
+	traceResponse(ctx, res)
+	return res, func() {
+		res.Body.Close()
+		unlock()
+	}, nil
+}
+
+// checkResponse calls the response hooks, checks the status code of the
+// response, and saves the csrf token header. The body is nil for streamed
+// responses.
+func (cl *Client) checkResponse(res *http.Response, body []byte) error {
+	// response hooks
+	for _, f := range cl.responseHooks {
+		f(res, body)
 	}
-	defer res.Body.Close()
 	// check status code
 	if res.StatusCode != http.StatusOK {
-		return nil, ErrBadStatusCode
+		return ErrBadStatusCode
 	}
 	// retrieve and save csrf token header
 	if tok := res.Header.Get(TokenHeader); tok != "" {
-		cl.token = tok
-	}
-	// read body
-	body, err := ioutil.ReadAll(res.Body)
-	if err != nil {
-		return nil, err
+		cl.sess.setTok(tok)
 	}
-	// decode
-	return xmlDecode(body, takeFirstEl)
+	return nil
 }
 
 // doReqString wraps a request operation, returning the data of the specified
@@ -186,6 +417,15 @@ func (cl *Client) doReqString(ctx context.Context, path string, v interface{}, e
 	return s, nil
 }
 
+// doReqList wraps a request operation setting a list of elements (ie,
+// <Servers><Server>...</Server></Servers>), where each item is a list of
+// ordered name/value pairs.
+func (cl *Client) doReqList(ctx context.Context, path, listEl, itemEl string, items ...[]string) (bool, error) {
+	return cl.doReqCheckOK(ctx, path, NewRequest().
+		AddNested(listEl, requestItems(itemEl, items...)),
+	)
+}
+
 // doReqCheckOK wraps a request operation (ie, connect, disconnect, etc),
 // checking success via the presence of 'OK' in the XML <response/>.
 func (cl *Client) doReqCheckOK(ctx context.Context, path string, v interface{}) (bool, error) {
@@ -193,14 +433,13 @@ func (cl *Client) doReqCheckOK(ctx context.Context, path string, v interface{})
 	if err != nil {
 		return false, err
 	}
-	// expect mxj.Map
-	m, ok := res.(mxj.Map)
+	// expect map
+	m, ok := res.(map[string]interface{})
 	if !ok {
 		return false, ErrInvalidResponse
 	}
 	// check response present
-	o := map[string]interface{}(m)
-	r, ok := o["response"]
+	r, ok := m["response"]
 	if !ok {
 		return false, ErrInvalidResponse
 	}
@@ -228,6 +467,32 @@ func (cl *Client) Do(ctx context.Context, path string, v interface{}) (XMLData,
 	return d, nil
 }
 
+// DoInto sends a request to the server with the provided path, decoding the
+// response into v with UnmarshalXML. If data is nil, then GET will be used as
+// the HTTP method, otherwise POST will be used.
+func (cl *Client) DoInto(ctx context.Context, path string, data, v interface{}) (err error) {
+	ctx, end := cl.startSpan(ctx, path, data)
+	defer func() { end(err) }()
+	body, err := cl.doRaw(ctx, path, data)
+	if err != nil {
+		return err
+	}
+	return UnmarshalXML(body, v)
+}
+
+// DoRaw sends a request to the server with the provided path, returning the
+// undecoded response body. If body is nil, then GET will be used as the HTTP
+// method, otherwise POST will be used.
+func (cl *Client) DoRaw(ctx context.Context, path string, body []byte) (_ []byte, err error) {
+	var v interface{}
+	if body != nil {
+		v = body
+	}
+	ctx, end := cl.startSpan(ctx, path, v)
+	defer func() { end(err) }()
+	return cl.doRaw(ctx, path, v)
+}
+
 // NewSessionAndTokenID starts a session with the server, and returns the
 // session and token.
 func (cl *Client) NewSessionAndTokenID(ctx context.Context) (string, string, error) {
@@ -261,52 +526,62 @@ func (cl *Client) NewSessionAndTokenID(ctx context.Context) (string, string, err
 	return strings.TrimPrefix(s, "SessionID="), t, nil
 }
 
-// SetSessionAndTokenID sets the sessionID and tokenID for the Client.
+// SetSessionAndTokenID sets the sessionID and tokenID for the Client. The
+// session cookie is set in the http client's cookie jar (creating one if the
+// http client has none), replacing any previous session cookie, while other
+// cookies set by the device are preserved.
 func (cl *Client) SetSessionAndTokenID(sessionID, tokenID string) error {
 	cl.Lock()
 	defer cl.Unlock()
-	var err error
 	// create cookie jar
-	cl.cl.Jar, err = cookiejar.New(nil)
-	if err != nil {
-		return err
+	if cl.cl.Jar == nil {
+		jar, err := cookiejar.New(nil)
+		if err != nil {
+			return err
+		}
+		cl.cl.Jar = jar
 	}
 	// set values on client
 	u, err := url.Parse(cl.endpoint)
 	if err != nil {
 		return err
 	}
+	// only send the session cookie over https for https endpoints, as the
+	// device does
 	cl.cl.Jar.SetCookies(u, []*http.Cookie{&http.Cookie{
-		Name:  "SessionID",
-		Value: sessionID,
+		Name:     "SessionID",
+		Value:    sessionID,
+		Path:     "/",
+		Secure:   u.Scheme == "https",
+		HttpOnly: true,
 	}})
-	cl.token = tokenID
+	cl.sess.setTok(tokenID)
 	return nil
 }
 
 // GlobalConfig retrieves global Hilink configuration.
 func (cl *Client) GlobalConfig(ctx context.Context) (XMLData, error) {
-	return cl.Do(ctx, "config/global/config.xml", nil)
+	return cl.doConfig(ctx, "config/global/config.xml")
 }
 
 // NetworkTypes retrieves available network types.
 func (cl *Client) NetworkTypes(ctx context.Context) (XMLData, error) {
-	return cl.Do(ctx, "config/global/net-type.xml", nil)
+	return cl.doConfig(ctx, "config/global/net-type.xml")
 }
 
 // PCAssistantConfig retrieves PC Assistant configuration.
 func (cl *Client) PCAssistantConfig(ctx context.Context) (XMLData, error) {
-	return cl.Do(ctx, "config/pcassistant/config.xml", nil)
+	return cl.doConfig(ctx, "config/pcassistant/config.xml")
 }
 
 // DeviceConfig retrieves device configuration.
 func (cl *Client) DeviceConfig(ctx context.Context) (XMLData, error) {
-	return cl.Do(ctx, "config/deviceinformation/config.xml", nil)
+	return cl.doConfig(ctx, "config/deviceinformation/config.xml")
 }
 
 // WebUIConfig retrieves WebUI configuration.
 func (cl *Client) WebUIConfig(ctx context.Context) (XMLData, error) {
-	return cl.Do(ctx, "config/webuicfg/config.xml", nil)
+	return cl.doConfig(ctx, "config/webuicfg/config.xml")
 }
 
 // SmsConfig retrieves device SMS configuration.
@@ -324,6 +599,235 @@ func (cl *Client) DhcpConfig(ctx context.Context) (XMLData, error) {
 	return cl.Do(ctx, "api/dhcp/settings", nil)
 }
 
+// LanSettings retrieves the router LAN (DHCP) settings.
+func (cl *Client) LanSettings(ctx context.Context) (*LanSettings, error) {
+	res, err := cl.DhcpConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &LanSettings{
+		IPAddress:    xmlStr(res, "DhcpIPAddress"),
+		Netmask:      xmlStr(res, "DhcpLanNetmask"),
+		DhcpEnabled:  xmlStr(res, "DhcpStatus") == "1",
+		DhcpStart:    xmlStr(res, "DhcpStartIPAddress"),
+		DhcpEnd:      xmlStr(res, "DhcpEndIPAddress"),
+		LeaseTime:    time.Duration(xmlInt(res, "DhcpLeaseTime")) * time.Second,
+		DnsEnabled:   xmlStr(res, "DnsStatus") == "1",
+		PrimaryDns:   xmlStr(res, "PrimaryDns"),
+		SecondaryDns: xmlStr(res, "SecondaryDns"),
+	}, nil
+}
+
+// LanSettingsSet sets the router LAN (DHCP) settings, after validating them.
+//
+// Note: changing the router's IP address or subnet will cause the device to
+// restart its LAN interface, and the client will need to be recreated with the
+// new address.
+func (cl *Client) LanSettingsSet(ctx context.Context, settings LanSettings) (bool, error) {
+	if err := settings.Validate(); err != nil {
+		return false, err
+	}
+	// order matters below!
+	return cl.doReqCheckOK(ctx, "api/dhcp/settings", NewRequest(
+		"DhcpIPAddress", settings.IPAddress,
+		"DhcpLanNetmask", settings.Netmask,
+		"DhcpStatus", boolToString(settings.DhcpEnabled),
+		"DhcpStartIPAddress", settings.DhcpStart,
+		"DhcpEndIPAddress", settings.DhcpEnd,
+		"DhcpLeaseTime", strconv.FormatInt(int64(settings.LeaseTime/time.Second), 10),
+		"DnsStatus", boolToString(settings.DnsEnabled),
+		"PrimaryDns", settings.PrimaryDns,
+		"SecondaryDns", settings.SecondaryDns,
+	))
+}
+
+// StaticLeases retrieves the static DHCP bindings.
+func (cl *Client) StaticLeases(ctx context.Context) ([]StaticLease, error) {
+	res, err := cl.Do(ctx, "api/dhcp/static-addr-info", nil)
+	if err != nil {
+		return nil, err
+	}
+	hosts, ok := res["Hosts"].(map[string]interface{})
+	if !ok {
+		return nil, nil
+	}
+	var l []StaticLease
+	for _, z := range xmlList(hosts["Host"]) {
+		l = append(l, StaticLease{
+			MacAddress: xmlStr(z, "HostHw"),
+			IPAddress:  xmlStr(z, "HostIp"),
+		})
+	}
+	return l, nil
+}
+
+// doReqStaticLeases wraps setting the complete list of static DHCP bindings.
+func (cl *Client) doReqStaticLeases(ctx context.Context, leases []StaticLease) (bool, error) {
+	var items [][]string
+	for i, l := range leases {
+		// order matters below!
+		items = append(items, []string{
+			"HostIndex", strconv.Itoa(i + 1),
+			"HostHw", l.MacAddress,
+			"HostIp", l.IPAddress,
+			"HostEnabled", "1",
+		})
+	}
+	return cl.doReqList(ctx, "api/dhcp/static-addr-info", "Hosts", "Host", items...)
+}
+
+// StaticLeaseAdd adds a static DHCP binding of the MAC address to the IP
+// address, replacing any existing binding for the MAC address.
+func (cl *Client) StaticLeaseAdd(ctx context.Context, mac, ip string) (bool, error) {
+	if net.ParseIP(ip).To4() == nil {
+		return false, ErrInvalidAddress
+	}
+	if _, err := net.ParseMAC(mac); err != nil {
+		return false, ErrInvalidAddress
+	}
+	l, err := cl.StaticLeases(ctx)
+	if err != nil {
+		return false, err
+	}
+	var leases []StaticLease
+	for _, z := range l {
+		if strings.EqualFold(z.MacAddress, mac) {
+			continue
+		}
+		if z.IPAddress == ip {
+			return false, ErrInvalidValue
+		}
+		leases = append(leases, z)
+	}
+	return cl.doReqStaticLeases(ctx, append(leases, StaticLease{MacAddress: mac, IPAddress: ip}))
+}
+
+// StaticLeaseDelete deletes the static DHCP binding for the MAC address.
+func (cl *Client) StaticLeaseDelete(ctx context.Context, mac string) (bool, error) {
+	l, err := cl.StaticLeases(ctx)
+	if err != nil {
+		return false, err
+	}
+	var leases []StaticLease
+	for _, z := range l {
+		if !strings.EqualFold(z.MacAddress, mac) {
+			leases = append(leases, z)
+		}
+	}
+	return cl.doReqStaticLeases(ctx, leases)
+}
+
+// IPv6Settings retrieves the IPv6 configuration.
+func (cl *Client) IPv6Settings(ctx context.Context) (*IPv6Settings, error) {
+	res, err := cl.Do(ctx, "api/ipv6/settings", nil)
+	if err != nil {
+		return nil, err
+	}
+	return &IPv6Settings{
+		Enabled:          xmlStr(res, "Ipv6Enable") == "1",
+		ConnectionType:   IPv6ConnectionType(xmlInt(res, "Ipv6ConnectionType")),
+		PrefixDelegation: xmlStr(res, "Ipv6PrefixDelegation") == "1",
+		DnsManual:        xmlStr(res, "Ipv6DnsStatus") == "1",
+		PrimaryDns:       xmlStr(res, "Ipv6PrimaryDns"),
+		SecondaryDns:     xmlStr(res, "Ipv6SecondaryDns"),
+	}, nil
+}
+
+// IPv6SettingsSet sets the IPv6 configuration.
+func (cl *Client) IPv6SettingsSet(ctx context.Context, settings IPv6Settings) (bool, error) {
+	for _, dns := range []string{settings.PrimaryDns, settings.SecondaryDns} {
+		if ip := net.ParseIP(dns); dns != "" && (ip == nil || ip.To4() != nil) {
+			return false, ErrInvalidAddress
+		}
+	}
+	// order matters below!
+	return cl.doReqCheckOK(ctx, "api/ipv6/settings", NewRequest(
+		"Ipv6Enable", boolToString(settings.Enabled),
+		"Ipv6ConnectionType", strconv.Itoa(int(settings.ConnectionType)),
+		"Ipv6PrefixDelegation", boolToString(settings.PrefixDelegation),
+		"Ipv6DnsStatus", boolToString(settings.DnsManual),
+		"Ipv6PrimaryDns", settings.PrimaryDns,
+		"Ipv6SecondaryDns", settings.SecondaryDns,
+	))
+}
+
+// Routes retrieves the static routes.
+func (cl *Client) Routes(ctx context.Context) ([]Route, error) {
+	res, err := cl.Do(ctx, "api/staticroute/staticroute", nil)
+	if err != nil {
+		return nil, err
+	}
+	routes, ok := res["Routes"].(map[string]interface{})
+	if !ok {
+		return nil, nil
+	}
+	var l []Route
+	for _, z := range xmlList(routes["Route"]) {
+		l = append(l, Route{
+			Destination: xmlStr(z, "DestIPAddress"),
+			Mask:        xmlStr(z, "DestNetmask"),
+			Gateway:     xmlStr(z, "Gateway"),
+			Interface:   xmlStr(z, "Interface"),
+		})
+	}
+	return l, nil
+}
+
+// doReqRoutes wraps setting the complete list of static routes.
+func (cl *Client) doReqRoutes(ctx context.Context, routes []Route) (bool, error) {
+	var items [][]string
+	for _, r := range routes {
+		// order matters below!
+		items = append(items, []string{
+			"DestIPAddress", r.Destination,
+			"DestNetmask", r.Mask,
+			"Gateway", r.Gateway,
+			"Interface", r.Interface,
+		})
+	}
+	return cl.doReqList(ctx, "api/staticroute/staticroute", "Routes", "Route", items...)
+}
+
+// RouteAdd adds a static route, replacing any existing route with the same
+// destination and mask.
+func (cl *Client) RouteAdd(ctx context.Context, route Route) (bool, error) {
+	for _, addr := range []string{route.Destination, route.Mask, route.Gateway} {
+		if net.ParseIP(addr).To4() == nil {
+			return false, ErrInvalidAddress
+		}
+	}
+	if route.Interface == "" {
+		route.Interface = "WAN"
+	}
+	l, err := cl.Routes(ctx)
+	if err != nil {
+		return false, err
+	}
+	var routes []Route
+	for _, r := range l {
+		if r.Destination != route.Destination || r.Mask != route.Mask {
+			routes = append(routes, r)
+		}
+	}
+	return cl.doReqRoutes(ctx, append(routes, route))
+}
+
+// RouteDelete deletes the static route with the specified destination and
+// mask.
+func (cl *Client) RouteDelete(ctx context.Context, destination, mask string) (bool, error) {
+	l, err := cl.Routes(ctx)
+	if err != nil {
+		return false, err
+	}
+	var routes []Route
+	for _, r := range l {
+		if r.Destination != destination || r.Mask != mask {
+			routes = append(routes, r)
+		}
+	}
+	return cl.doReqRoutes(ctx, routes)
+}
+
 // CradleStatusInfo retrieves cradle status information.
 func (cl *Client) CradleStatusInfo(ctx context.Context) (XMLData, error) {
 	return cl.Do(ctx, "api/cradle/status-info", nil)
@@ -368,6 +872,94 @@ func (cl *Client) DeviceReboot(ctx context.Context) (bool, error) {
 	return cl.DeviceControl(ctx, 1)
 }
 
+// RebootAndWait restarts the device, and waits until the WebUI answers again,
+// establishing a new session (logging in again when the Auth option was
+// given). The overall deadline is the deadline of ctx, or RebootTimeout when
+// ctx has no deadline.
+func (cl *Client) RebootAndWait(ctx context.Context) (bool, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, RebootTimeout)
+		defer cancel()
+	}
+	ok, err := cl.DeviceReboot(ctx)
+	if err != nil || !ok {
+		return ok, err
+	}
+	// poll polls the session endpoint, with a short per request timeout
+	poll := func() error {
+		pollCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		defer cancel()
+		_, _, err := cl.NewSessionAndTokenID(pollCtx)
+		return err
+	}
+	// wait for the device to go down, as the reboot is not immediate,
+	// continuing regardless after a while
+	down := time.Now().Add(30 * time.Second)
+	for time.Now().Before(down) && poll() == nil {
+		select {
+		case <-ctx.Done():
+			return false, ctx.Err()
+		case <-time.After(time.Second):
+		}
+	}
+	// wait for the device to come back up
+	for {
+		if poll() == nil {
+			if err := cl.Relogin(ctx); err == nil {
+				return true, nil
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return false, ctx.Err()
+		case <-time.After(2 * time.Second):
+		}
+	}
+}
+
+// WaitUntilReady waits until the device has finished booting and the SIM is
+// ready (ie, after power-on, or after the SIM PIN has been entered), polling
+// every 2 seconds. Until then, the device may refuse connections, or respond
+// with errors (ie, system not available), which are ignored. Each time the
+// device responds, a new session is started (logging in again when the Auth
+// option was given), and the status is checked. The overall deadline is the
+// deadline of ctx, or RebootTimeout when ctx has no deadline.
+func (cl *Client) WaitUntilReady(ctx context.Context) error {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, RebootTimeout)
+		defer cancel()
+	}
+	// ready checks the device status, with a short per request timeout
+	ready := func() error {
+		pollCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		defer cancel()
+		if err := cl.Relogin(pollCtx); err != nil {
+			return err
+		}
+		status, err := cl.Status(pollCtx)
+		switch {
+		case err != nil:
+			return err
+		case status.SimStatus != 1:
+			return fmt.Errorf("sim not ready (status %d)", status.SimStatus)
+		}
+		return nil
+	}
+	for {
+		err := ready()
+		if err == nil {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%w: %v", ctx.Err(), err)
+		case <-time.After(2 * time.Second):
+		}
+	}
+}
+
 // DeviceReset resets the device configuration.
 func (cl *Client) DeviceReset(ctx context.Context) (bool, error) {
 	return cl.DeviceControl(ctx, 2)
@@ -416,21 +1008,121 @@ func (cl *Client) FastbootFeatures(ctx context.Context) (XMLData, error) {
 	return cl.Do(ctx, "api/device/fastbootswitch", nil)
 }
 
+// FastbootFeaturesSet enables or disables fastboot.
+func (cl *Client) FastbootFeaturesSet(ctx context.Context, enabled bool) (bool, error) {
+	return cl.doReqCheckOK(ctx, "api/device/fastbootswitch", NewRequest(
+		"fastbootswitch", boolToString(enabled),
+	))
+}
+
 // PowerFeatures retrieves power feature information.
 func (cl *Client) PowerFeatures(ctx context.Context) (XMLData, error) {
 	return cl.Do(ctx, "api/device/powersaveswitch", nil)
 }
 
+// PowerFeaturesSet enables or disables power saving.
+func (cl *Client) PowerFeaturesSet(ctx context.Context, enabled bool) (bool, error) {
+	return cl.doReqCheckOK(ctx, "api/device/powersaveswitch", NewRequest(
+		"powersaveswitch", boolToString(enabled),
+	))
+}
+
 // TetheringFeatures retrieves USB tethering feature information.
 func (cl *Client) TetheringFeatures(ctx context.Context) (XMLData, error) {
 	return cl.Do(ctx, "api/device/usb-tethering-switch", nil)
 }
 
+// TetheringFeaturesSet enables or disables USB tethering.
+func (cl *Client) TetheringFeaturesSet(ctx context.Context, enabled bool) (bool, error) {
+	return cl.doReqCheckOK(ctx, "api/device/usb-tethering-switch", NewRequest(
+		"usbtethering", boolToString(enabled),
+	))
+}
+
 // SignalInfo retrieves network signal information.
 func (cl *Client) SignalInfo(ctx context.Context) (XMLData, error) {
 	return cl.Do(ctx, "api/device/signal", nil)
 }
 
+// ServingCell retrieves the extended serving cell information, including any
+// carrier aggregation secondary cells reported by the device (as scc1_band,
+// scc1_pci, ...).
+func (cl *Client) ServingCell(ctx context.Context) (*ServingCell, error) {
+	res, err := cl.SignalInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sc := &ServingCell{
+		Carrier: Carrier{
+			Band:        xmlInt(res, "band"),
+			EARFCN:      xmlEarfcn(res, "earfcn"),
+			PCI:         xmlInt(res, "pci"),
+			DLBandwidth: xmlFloat(res, "dlbandwidth"),
+			ULBandwidth: xmlFloat(res, "ulbandwidth"),
+			RSRP:        xmlFloat(res, "rsrp"),
+			RSRQ:        xmlFloat(res, "rsrq"),
+			SINR:        xmlFloat(res, "sinr"),
+		},
+		Mode: xmlStr(res, "mode"),
+		TAC:  xmlStr(res, "tac"),
+		PLMN: xmlStr(res, "plmn"),
+		RSSI: xmlFloat(res, "rssi"),
+	}
+	// decode cell id (decimal, or hex on some firmware) into eNB id and sector
+	if s := xmlStr(res, "cell_id"); s != "" {
+		if sc.CellID, err = strconv.ParseUint(s, 10, 64); err != nil {
+			sc.CellID, _ = strconv.ParseUint(strings.TrimPrefix(s, "0x"), 16, 64)
+		}
+		sc.ENodeBID, sc.Sector = sc.CellID>>8, int(sc.CellID&0xff)
+	}
+	if s := xmlStr(res, "enodeb_id"); s != "" {
+		sc.ENodeBID, _ = strconv.ParseUint(s, 10, 64)
+	}
+	// secondary cells
+	for i := 1; ; i++ {
+		p := fmt.Sprintf("scc%d_", i)
+		if _, ok := res[p+"band"]; !ok {
+			break
+		}
+		sc.SecondaryCells = append(sc.SecondaryCells, Carrier{
+			Band:        xmlInt(res, p+"band"),
+			EARFCN:      xmlEarfcn(res, p+"earfcn"),
+			PCI:         xmlInt(res, p+"pci"),
+			DLBandwidth: xmlFloat(res, p+"dlbandwidth"),
+			ULBandwidth: xmlFloat(res, p+"ulbandwidth"),
+			RSRP:        xmlFloat(res, p+"rsrp"),
+			RSRQ:        xmlFloat(res, p+"rsrq"),
+			SINR:        xmlFloat(res, p+"sinr"),
+		})
+	}
+	return sc, nil
+}
+
+// NeighborCells retrieves the list of neighbor cells, where supported by the
+// device.
+func (cl *Client) NeighborCells(ctx context.Context) ([]NeighborCell, error) {
+	res, err := cl.Do(ctx, "api/net/cell-info", nil)
+	if err != nil {
+		return nil, err
+	}
+	cells, ok := res["Cells"].(map[string]interface{})
+	if !ok {
+		if cells, ok = res["cells"].(map[string]interface{}); !ok {
+			return nil, nil
+		}
+	}
+	var l []NeighborCell
+	for _, c := range append(xmlList(cells["Cell"]), xmlList(cells["cell"])...) {
+		l = append(l, NeighborCell{
+			EARFCN: xmlInt(c, "earfcn", "Earfcn", "EARFCN"),
+			PCI:    xmlInt(c, "pci", "Pci", "PCI"),
+			RSRP:   xmlFloat(c, "rsrp", "Rsrp", "RSRP"),
+			RSRQ:   xmlFloat(c, "rsrq", "Rsrq", "RSRQ"),
+		})
+	}
+	return l, nil
+}
+
 // ConnectionInfo retrieves connection (dialup) information.
 func (cl *Client) ConnectionInfo(ctx context.Context) (XMLData, error) {
 	return cl.Do(ctx, "api/dialup/connection", nil)
@@ -454,8 +1146,23 @@ func (cl *Client) LanguageSet(ctx context.Context, lang string) (bool, error) {
 }
 
 // NotificationInfo retrieves notification information.
-func (cl *Client) NotificationInfo(ctx context.Context) (XMLData, error) {
-	return cl.Do(ctx, "api/monitoring/check-notifications", nil)
+func (cl *Client) NotificationInfo(ctx context.Context) (*Notifications, error) {
+	res, err := cl.Do(ctx, "api/monitoring/check-notifications", nil)
+	if err != nil {
+		return nil, err
+	}
+	return &Notifications{
+		UnreadMessage:      xmlInt(res, "UnreadMessage"),
+		SmsStorageFull:     xmlStr(res, "SmsStorageFull") == "1",
+		OnlineUpdateStatus: xmlInt(res, "OnlineUpdateStatus"),
+	}, nil
+}
+
+// ClearNotifications clears the device notifications.
+func (cl *Client) ClearNotifications(ctx context.Context) (bool, error) {
+	return cl.doReqCheckOK(ctx, "api/monitoring/clear-notifications", XMLData{
+		"ClearNotifications": "1",
+	})
 }
 
 // SimInfo retrieves SIM card information.
@@ -468,11 +1175,49 @@ func (cl *Client) StatusInfo(ctx context.Context) (XMLData, error) {
 	return cl.Do(ctx, "api/monitoring/status", nil)
 }
 
+// Status retrieves the general device status.
+func (cl *Client) Status(ctx context.Context) (*Status, error) {
+	res, err := cl.StatusInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &Status{
+		ConnectionStatus: ConnectionStatus(xmlInt(res, "ConnectionStatus")),
+		NetworkType:      xmlInt(res, "CurrentNetworkTypeEx", "CurrentNetworkType"),
+		SignalIcon:       xmlInt(res, "SignalIcon"),
+		Roaming:          xmlStr(res, "RoamingStatus") == "1",
+		SimStatus:        xmlInt(res, "SimStatus"),
+		WanIPAddress:     xmlStr(res, "WanIPAddress"),
+		PrimaryDns:       xmlStr(res, "PrimaryDns"),
+		SecondaryDns:     xmlStr(res, "SecondaryDns"),
+		WifiEnabled:      xmlStr(res, "WifiStatus") == "1",
+		WifiUsers:        xmlInt(res, "CurrentWifiUser"),
+	}, nil
+}
+
 // TrafficInfo retrieves traffic statistic information.
 func (cl *Client) TrafficInfo(ctx context.Context) (XMLData, error) {
 	return cl.Do(ctx, "api/monitoring/traffic-statistics", nil)
 }
 
+// Traffic retrieves the traffic statistics.
+func (cl *Client) Traffic(ctx context.Context) (*Traffic, error) {
+	res, err := cl.TrafficInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &Traffic{
+		ConnectTime:      time.Duration(xmlUint(res, "CurrentConnectTime")) * time.Second,
+		Upload:           xmlUint(res, "CurrentUpload"),
+		Download:         xmlUint(res, "CurrentDownload"),
+		UploadRate:       xmlUint(res, "CurrentUploadRate"),
+		DownloadRate:     xmlUint(res, "CurrentDownloadRate"),
+		TotalConnectTime: time.Duration(xmlUint(res, "TotalConnectTime")) * time.Second,
+		TotalUpload:      xmlUint(res, "TotalUpload"),
+		TotalDownload:    xmlUint(res, "TotalDownload"),
+	}, nil
+}
+
 // TrafficClear clears the current traffic statistics.
 func (cl *Client) TrafficClear(ctx context.Context) (bool, error) {
 	return cl.doReqCheckOK(ctx, "api/monitoring/clear-traffic", XMLData{
@@ -485,24 +1230,506 @@ func (cl *Client) MonthInfo(ctx context.Context) (XMLData, error) {
 	return cl.Do(ctx, "api/monitoring/month_statistics", nil)
 }
 
-// WlanMonthInfo retrieves the WLAN month download statistic information.
-func (cl *Client) WlanMonthInfo(ctx context.Context) (XMLData, error) {
-	return cl.Do(ctx, "api/monitoring/month_statistics_wlan", nil)
-}
-
-// NetworkInfo retrieves network provider information.
-func (cl *Client) NetworkInfo(ctx context.Context) (XMLData, error) {
-	return cl.Do(ctx, "api/net/current-plmn", nil)
+// MonthTraffic retrieves the current month traffic statistics.
+func (cl *Client) MonthTraffic(ctx context.Context) (*MonthTraffic, error) {
+	res, err := cl.MonthInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &MonthTraffic{
+		Upload:    xmlUint(res, "CurrentMonthUpload"),
+		Download:  xmlUint(res, "CurrentMonthDownload"),
+		Duration:  time.Duration(xmlUint(res, "MonthDuration")) * time.Second,
+		LastClear: xmlStr(res, "MonthLastClearTime"),
+	}, nil
 }
 
-// WifiFeatures retrieves wifi feature information.
-func (cl *Client) WifiFeatures(ctx context.Context) (XMLData, error) {
-	return cl.Do(ctx, "api/wlan/wifi-feature-switch", nil)
+// DataPlan retrieves the monthly data plan settings.
+func (cl *Client) DataPlan(ctx context.Context) (*DataPlan, error) {
+	res, err := cl.Do(ctx, "api/monitoring/start_date", nil)
+	if err != nil {
+		return nil, err
+	}
+	limit, err := parseDataLimit(xmlStr(res, "DataLimit"))
+	if err != nil {
+		return nil, err
+	}
+	return &DataPlan{
+		Enabled:   xmlStr(res, "SetMonthData") == "1",
+		StartDay:  xmlInt(res, "StartDay"),
+		DataLimit: limit,
+		Threshold: xmlInt(res, "MonthThreshold"),
+	}, nil
+}
+
+// DataPlanSet sets the monthly data plan settings, keeping the device's data
+// limit alert state.
+func (cl *Client) DataPlanSet(ctx context.Context, plan DataPlan) (bool, error) {
+	if plan.StartDay < 1 || plan.StartDay > 31 || plan.Threshold < 0 || plan.Threshold > 100 {
+		return false, ErrInvalidValue
+	}
+	// retrieve the current settings, to keep the limit alert state
+	res, err := cl.Do(ctx, "api/monitoring/start_date", nil)
+	if err != nil {
+		return false, err
+	}
+	return cl.doReqCheckOK(ctx, "api/monitoring/start_date", dataPlanRequest{
+		StartDay:       plan.StartDay,
+		DataLimit:      dataLimitString(plan.DataLimit),
+		DataLimitAwoke: xmlStr(res, "DataLimitAwoke"),
+		MonthThreshold: plan.Threshold,
+		SetMonthData:   plan.Enabled,
+	})
 }
 
-// ModeList retrieves available network modes.
-func (cl *Client) ModeList(ctx context.Context) (XMLData, error) {
-	return cl.Do(ctx, "api/net/net-mode-list", nil)
+// dataPlanRequest is a monthly data plan settings request (order matters
+// below!).
+type dataPlanRequest struct {
+	StartDay       int
+	DataLimit      string
+	DataLimitAwoke string
+	MonthThreshold int
+	SetMonthData   bool
+}
+
+// WlanMonthInfo retrieves the WLAN month download statistic information.
+func (cl *Client) WlanMonthInfo(ctx context.Context) (XMLData, error) {
+	return cl.Do(ctx, "api/monitoring/month_statistics_wlan", nil)
+}
+
+// NetworkInfo retrieves network provider information. When the device only
+// reports the numeric PLMN, the operator name is looked up from a built-in
+// table.
+func (cl *Client) NetworkInfo(ctx context.Context) (*NetworkOperator, error) {
+	res, err := cl.Do(ctx, "api/net/current-plmn", nil)
+	if err != nil {
+		return nil, err
+	}
+	op := &NetworkOperator{
+		State:     xmlInt(res, "State"),
+		FullName:  xmlStr(res, "FullName"),
+		ShortName: xmlStr(res, "ShortName"),
+		Spn:       xmlStr(res, "Spn"),
+		Numeric:   xmlStr(res, "Numeric"),
+		Rat:       Rat(xmlInt(res, "Rat")),
+	}
+	if op.Plmn, err = ParsePlmn(op.Numeric); err == nil {
+		name := op.Plmn.OperatorName()
+		if op.FullName == "" {
+			op.FullName = name
+		}
+		if op.ShortName == "" {
+			op.ShortName = name
+		}
+	}
+	return op, nil
+}
+
+// WifiFeatures retrieves wifi feature information.
+func (cl *Client) WifiFeatures(ctx context.Context) (XMLData, error) {
+	return cl.Do(ctx, "api/wlan/wifi-feature-switch", nil)
+}
+
+// WifiSwitchInfo retrieves the on/off status of the Wi-Fi radios.
+func (cl *Client) WifiSwitchInfo(ctx context.Context) (XMLData, error) {
+	return cl.Do(ctx, "api/wlan/status-switch-settings", nil)
+}
+
+// doReqWifiSwitch wraps a Wi-Fi radio on/off request.
+func (cl *Client) doReqWifiSwitch(ctx context.Context, enabled bool, radios ...WifiRadio) (bool, error) {
+	// build radios
+	radiosReq := NewRequest()
+	for _, r := range radios {
+		radiosReq.AddNested("radio", NewRequest(
+			"wifienable", boolToString(enabled),
+			"index", fmt.Sprintf("%d", r),
+			"ID", r.ID(),
+		))
+	}
+	// send request (order matters below!)
+	return cl.doReqCheckOK(ctx, "api/wlan/status-switch-settings", NewRequest().
+		AddNested("radios", radiosReq).
+		Add("WifiRestart", "1"),
+	)
+}
+
+// WifiSwitch turns all of the Wi-Fi radios reported by the device on or off.
+func (cl *Client) WifiSwitch(ctx context.Context, enabled bool) (bool, error) {
+	res, err := cl.WifiSwitchInfo(ctx)
+	if err != nil {
+		return false, err
+	}
+	// collect radios
+	var radios []WifiRadio
+	if r, ok := res["radios"].(map[string]interface{}); ok {
+		for _, z := range xmlList(r["radio"]) {
+			s, _ := z["index"].(string)
+			i, err := strconv.Atoi(s)
+			if err != nil {
+				return false, ErrInvalidValue
+			}
+			radios = append(radios, WifiRadio(i))
+		}
+	}
+	if len(radios) == 0 {
+		radios = append(radios, WifiRadio24GHz)
+	}
+	return cl.doReqWifiSwitch(ctx, enabled, radios...)
+}
+
+// WifiSwitch24GHz turns the 2.4 GHz Wi-Fi radio on or off.
+func (cl *Client) WifiSwitch24GHz(ctx context.Context, enabled bool) (bool, error) {
+	return cl.doReqWifiSwitch(ctx, enabled, WifiRadio24GHz)
+}
+
+// WifiSwitch5GHz turns the 5 GHz Wi-Fi radio on or off.
+func (cl *Client) WifiSwitch5GHz(ctx context.Context, enabled bool) (bool, error) {
+	return cl.doReqWifiSwitch(ctx, enabled, WifiRadio5GHz)
+}
+
+// WifiHostList retrieves the list of hosts connected to the Wi-Fi.
+func (cl *Client) WifiHostList(ctx context.Context) (XMLData, error) {
+	return cl.Do(ctx, "api/wlan/host-list", nil)
+}
+
+// WifiHosts retrieves the hosts connected to the Wi-Fi along with their
+// per-station statistics.
+func (cl *Client) WifiHosts(ctx context.Context) ([]WifiHost, error) {
+	res, err := cl.WifiHostList(ctx)
+	if err != nil {
+		return nil, err
+	}
+	hosts, ok := res["Hosts"].(map[string]interface{})
+	if !ok {
+		return nil, nil
+	}
+	var l []WifiHost
+	for _, h := range xmlList(hosts["Host"]) {
+		l = append(l, WifiHost{
+			MacAddress:     xmlStr(h, "MacAddress"),
+			IPAddress:      xmlStr(h, "IpAddress"),
+			HostName:       xmlStr(h, "HostName", "ActualName"),
+			AssociatedSsid: xmlStr(h, "AssociatedSsid"),
+			AssociatedTime: time.Duration(xmlUint(h, "AssociatedTime")) * time.Second,
+			Frequency:      xmlStr(h, "Frequency"),
+			TxBytes:        xmlUint(h, "TxBytes", "TotalTxBytes"),
+			RxBytes:        xmlUint(h, "RxBytes", "TotalRxBytes"),
+			TxRate:         xmlUint(h, "TxRate", "TxKbps"),
+			RxRate:         xmlUint(h, "RxRate", "RxKbps"),
+			Signal:         xmlInt(h, "Rssi", "SignalStrength"),
+		})
+	}
+	return l, nil
+}
+
+// LanHostInfo retrieves the list of hosts connected to the LAN (ie, by
+// Ethernet), as reported by B-series routers. Depending on the firmware, Wi-Fi
+// hosts are also included.
+func (cl *Client) LanHostInfo(ctx context.Context) (XMLData, error) {
+	return cl.Do(ctx, "api/lan/HostInfo", nil)
+}
+
+// LanHosts retrieves the active hosts connected to the LAN.
+func (cl *Client) LanHosts(ctx context.Context) ([]Host, error) {
+	res, err := cl.LanHostInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	hosts, ok := res["Hosts"].(map[string]interface{})
+	if !ok {
+		return nil, nil
+	}
+	var l []Host
+	for _, h := range xmlList(hosts["Host"]) {
+		// inactive hosts are hosts that recently disconnected
+		if xmlStr(h, "Active") == "0" {
+			continue
+		}
+		typ := HostInterface(xmlStr(h, "InterfaceType"))
+		if typ == "" {
+			typ = HostInterfaceEthernet
+		}
+		l = append(l, Host{
+			MacAddress:     xmlStr(h, "MacAddress"),
+			IPAddress:      xmlStr(h, "IpAddress"),
+			HostName:       xmlStr(h, "HostName", "ActualName"),
+			Interface:      typ,
+			AssociatedSsid: xmlStr(h, "AssociatedSsid"),
+			Frequency:      xmlStr(h, "Frequency"),
+			AssociatedTime: time.Duration(xmlUint(h, "AssociatedTime")) * time.Second,
+		})
+	}
+	return l, nil
+}
+
+// AllHosts retrieves the hosts connected to the LAN and the Wi-Fi, merging
+// hosts reported by both by MAC address. Hosts from a list not supported by
+// the device firmware (ie, the LAN list on devices without Ethernet) are
+// omitted.
+func (cl *Client) AllHosts(ctx context.Context) ([]Host, error) {
+	lan, err := cl.LanHosts(ctx)
+	if err != nil && !isNotSupported(err) {
+		return nil, err
+	}
+	wifi, err := cl.WifiHosts(ctx)
+	if err != nil && !isNotSupported(err) {
+		return nil, err
+	}
+	l := append([]Host(nil), lan...)
+	index := make(map[string]int, len(l))
+	for i, h := range l {
+		index[strings.ToUpper(h.MacAddress)] = i
+	}
+	for _, h := range wifi {
+		i, ok := index[strings.ToUpper(h.MacAddress)]
+		if !ok {
+			index[strings.ToUpper(h.MacAddress)], i = len(l), len(l)
+			l = append(l, Host{
+				MacAddress: h.MacAddress,
+				IPAddress:  h.IPAddress,
+				HostName:   h.HostName,
+			})
+		}
+		l[i].Interface = HostInterfaceWifi
+		if l[i].AssociatedSsid == "" {
+			l[i].AssociatedSsid = h.AssociatedSsid
+		}
+		if l[i].Frequency == "" {
+			l[i].Frequency = h.Frequency
+		}
+		if l[i].AssociatedTime == 0 {
+			l[i].AssociatedTime = h.AssociatedTime
+		}
+	}
+	return l, nil
+}
+
+// WakeOnLan sends a Wake-on-LAN magic packet to the LAN host with the MAC
+// address, using the Wake-on-LAN function of routers whose firmware supports
+// it (ie, to wake a host behind the router remotely).
+func (cl *Client) WakeOnLan(ctx context.Context, mac string) (bool, error) {
+	hw, err := net.ParseMAC(mac)
+	if err != nil || len(hw) != 6 {
+		return false, ErrInvalidAddress
+	}
+	return cl.doReqCheckOK(ctx, "api/lan/wol", NewRequest(
+		"MacAddress", strings.ToUpper(hw.String()),
+	))
+}
+
+// BandwidthLimits retrieves the per-device bandwidth limits, on routers whose
+// firmware supports bandwidth control.
+func (cl *Client) BandwidthLimits(ctx context.Context) ([]BandwidthLimit, error) {
+	res, err := cl.Do(ctx, "api/qos/bandwidth-control", nil)
+	if err != nil {
+		return nil, err
+	}
+	hosts, ok := res["Hosts"].(map[string]interface{})
+	if !ok {
+		return nil, nil
+	}
+	var l []BandwidthLimit
+	for _, z := range xmlList(hosts["Host"]) {
+		l = append(l, BandwidthLimit{
+			MacAddress: xmlStr(z, "MacAddress"),
+			Upload:     uint(xmlUint(z, "UpRate")),
+			Download:   uint(xmlUint(z, "DownRate")),
+		})
+	}
+	return l, nil
+}
+
+// doReqBandwidthLimits wraps setting the complete list of per-device
+// bandwidth limits.
+func (cl *Client) doReqBandwidthLimits(ctx context.Context, limits []BandwidthLimit) (bool, error) {
+	var items [][]string
+	for i, l := range limits {
+		// order matters below!
+		items = append(items, []string{
+			"Index", strconv.Itoa(i + 1),
+			"MacAddress", l.MacAddress,
+			"UpRate", strconv.FormatUint(uint64(l.Upload), 10),
+			"DownRate", strconv.FormatUint(uint64(l.Download), 10),
+			"Enable", "1",
+		})
+	}
+	return cl.doReqList(ctx, "api/qos/bandwidth-control", "Hosts", "Host", items...)
+}
+
+// BandwidthLimitSet sets the upload and download limits (in kbit/s, 0 being
+// unlimited) of the host with the MAC address, replacing any existing limit
+// for the MAC address. Setting both limits to 0 removes the limit.
+func (cl *Client) BandwidthLimitSet(ctx context.Context, mac string, upload, download uint) (bool, error) {
+	if _, err := net.ParseMAC(mac); err != nil {
+		return false, ErrInvalidAddress
+	}
+	l, err := cl.BandwidthLimits(ctx)
+	if err != nil {
+		return false, err
+	}
+	var limits []BandwidthLimit
+	for _, z := range l {
+		if !strings.EqualFold(z.MacAddress, mac) {
+			limits = append(limits, z)
+		}
+	}
+	if upload != 0 || download != 0 {
+		limits = append(limits, BandwidthLimit{MacAddress: mac, Upload: upload, Download: download})
+	}
+	return cl.doReqBandwidthLimits(ctx, limits)
+}
+
+// BandwidthLimitDelete removes the bandwidth limit of the host with the MAC
+// address.
+func (cl *Client) BandwidthLimitDelete(ctx context.Context, mac string) (bool, error) {
+	return cl.BandwidthLimitSet(ctx, mac, 0, 0)
+}
+
+// WifiMacFilter retrieves the Wi-Fi MAC filter settings.
+func (cl *Client) WifiMacFilter(ctx context.Context) (XMLData, error) {
+	return cl.Do(ctx, "api/wlan/multi-macfilter-settings", nil)
+}
+
+// doReqWifiMacFilter wraps a Wi-Fi MAC filter manipulation request. The
+// change func is called for each of the SSIDs with the current filter status
+// and MAC/hostname slots, and returns the new filter status and slots.
+func (cl *Client) doReqWifiMacFilter(ctx context.Context, change func(MacFilterStatus, [][2]string) (MacFilterStatus, [][2]string, error)) (bool, error) {
+	res, err := cl.WifiMacFilter(ctx)
+	if err != nil {
+		return false, err
+	}
+	ssids, ok := res["Ssids"].(map[string]interface{})
+	if !ok {
+		return false, ErrInvalidResponse
+	}
+	ssidsReq := NewRequest()
+	for _, z := range xmlList(ssids["Ssid"]) {
+		// read current values
+		index, _ := z["Index"].(string)
+		s, _ := z["WifiMacFilterStatus"].(string)
+		i, err := strconv.Atoi(s)
+		if err != nil {
+			return false, ErrInvalidValue
+		}
+		var slots [][2]string
+		for n := 0; ; n++ {
+			mac, ok := z[fmt.Sprintf("WifiMacFilterMac%d", n)]
+			if !ok {
+				break
+			}
+			m, _ := mac.(string)
+			h, _ := z[fmt.Sprintf("wifihostname%d", n)].(string)
+			slots = append(slots, [2]string{m, h})
+		}
+		// apply change
+		status, slots, err := change(MacFilterStatus(i), slots)
+		if err != nil {
+			return false, err
+		}
+		// build ssid (order matters below!)
+		vals := []string{
+			"Index", index,
+			"WifiMacFilterStatus", fmt.Sprintf("%d", status),
+		}
+		for n, slot := range slots {
+			vals = append(vals,
+				fmt.Sprintf("WifiMacFilterMac%d", n), slot[0],
+				fmt.Sprintf("wifihostname%d", n), slot[1],
+			)
+		}
+		ssidsReq.AddNested("Ssid", NewRequest(vals...))
+	}
+	return cl.doReqCheckOK(ctx, "api/wlan/multi-macfilter-settings", NewRequest().
+		AddNested("Ssids", ssidsReq),
+	)
+}
+
+// WifiHostBlock evicts and blocks the Wi-Fi host with the specified MAC
+// address by adding it to the MAC filter deny list (or removing it from the
+// allow list, when the MAC filter is in allow mode).
+func (cl *Client) WifiHostBlock(ctx context.Context, mac string) (bool, error) {
+	// lookup hostname
+	var hostname string
+	res, err := cl.WifiHostList(ctx)
+	if err != nil {
+		return false, err
+	}
+	if hosts, ok := res["Hosts"].(map[string]interface{}); ok {
+		for _, h := range xmlList(hosts["Host"]) {
+			if m, _ := h["MacAddress"].(string); strings.EqualFold(m, mac) {
+				hostname, _ = h["HostName"].(string)
+				break
+			}
+		}
+	}
+	return cl.doReqWifiMacFilter(ctx, func(status MacFilterStatus, slots [][2]string) (MacFilterStatus, [][2]string, error) {
+		if status == MacFilterStatusAllow {
+			return status, macFilterRemove(slots, mac), nil
+		}
+		for i := range slots {
+			if strings.EqualFold(slots[i][0], mac) {
+				return MacFilterStatusDeny, slots, nil
+			}
+		}
+		for i := range slots {
+			if slots[i][0] == "" {
+				slots[i] = [2]string{mac, hostname}
+				return MacFilterStatusDeny, slots, nil
+			}
+		}
+		return status, slots, ErrMacFilterFull
+	})
+}
+
+// WifiHostUnblock removes the Wi-Fi host with the specified MAC address from
+// the MAC filter deny list.
+func (cl *Client) WifiHostUnblock(ctx context.Context, mac string) (bool, error) {
+	return cl.doReqWifiMacFilter(ctx, func(status MacFilterStatus, slots [][2]string) (MacFilterStatus, [][2]string, error) {
+		if status != MacFilterStatusDeny {
+			return status, slots, nil
+		}
+		return status, macFilterRemove(slots, mac), nil
+	})
+}
+
+// WifiTimeSwitch retrieves the Wi-Fi on/off schedule.
+func (cl *Client) WifiTimeSwitch(ctx context.Context) (*WifiSchedule, error) {
+	res, err := cl.Do(ctx, "api/wlan/wifi-time-switch", nil)
+	if err != nil {
+		return nil, err
+	}
+	enabled, _ := res["Enable"].(string)
+	start, _ := res["StartTime"].(string)
+	stop, _ := res["EndTime"].(string)
+	days, _ := res["RepeatDays"].(string)
+	sched := &WifiSchedule{
+		Enabled: enabled == "1",
+	}
+	if sched.Start, err = parseClock(start); err != nil {
+		return nil, err
+	}
+	if sched.Stop, err = parseClock(stop); err != nil {
+		return nil, err
+	}
+	if sched.Weekdays, err = parseWeekdays(days); err != nil {
+		return nil, err
+	}
+	return sched, nil
+}
+
+// WifiTimeSwitchSet sets the Wi-Fi on/off schedule.
+func (cl *Client) WifiTimeSwitchSet(ctx context.Context, sched WifiSchedule) (bool, error) {
+	return cl.doReqCheckOK(ctx, "api/wlan/wifi-time-switch", NewRequest(
+		"Enable", boolToString(sched.Enabled),
+		"StartTime", clockToString(sched.Start),
+		"EndTime", clockToString(sched.Stop),
+		"RepeatDays", weekdaysToString(sched.Weekdays),
+	))
+}
+
+// ModeList retrieves available network modes.
+func (cl *Client) ModeList(ctx context.Context) (XMLData, error) {
+	return cl.Do(ctx, "api/net/net-mode-list", nil)
 }
 
 // ModeInfo retrieves network mode settings information.
@@ -517,13 +1744,135 @@ func (cl *Client) ModeNetworkInfo(ctx context.Context) (XMLData, error) {
 
 // ModeSet sets the network mode.
 func (cl *Client) ModeSet(ctx context.Context, netMode, netBand, lteBand string) (bool, error) {
-	return cl.doReqCheckOK(ctx, "api/net/net-mode", SimpleRequestXML(
+	return cl.doReqCheckOK(ctx, "api/net/net-mode", NewRequest(
 		"NetworkMode", netMode,
 		"NetworkBand", netBand,
 		"LTEBand", lteBand,
 	))
 }
 
+// doReqModeChange wraps a network mode change, retrieving the current network
+// mode settings, applying change, and setting the changed values.
+func (cl *Client) doReqModeChange(ctx context.Context, change func(map[string]string)) (bool, error) {
+	res, err := cl.ModeInfo(ctx)
+	if err != nil {
+		return false, err
+	}
+	vals := make(map[string]string)
+	for _, k := range []string{"NetworkMode", "NetworkBand", "LTEBand", "NRBand"} {
+		vals[k] = xmlStr(res, k)
+	}
+	_, hasLTE := res["LTEBand"]
+	_, hasNR := res["NRBand"]
+	change(vals)
+	// build request (order matters below!), without the LTE band for 3G
+	// devices (see DeviceProfile)
+	pairs := []string{
+		"NetworkMode", vals["NetworkMode"],
+		"NetworkBand", vals["NetworkBand"],
+	}
+	if hasLTE || vals["LTEBand"] != "" {
+		pairs = append(pairs, "LTEBand", vals["LTEBand"])
+	}
+	if hasNR || vals["NRBand"] != "" {
+		pairs = append(pairs, "NRBand", vals["NRBand"])
+	}
+	return cl.doReqCheckOK(ctx, "api/net/net-mode", NewRequest(pairs...))
+}
+
+// NetworkModeSet sets the network mode, retaining the current band settings.
+func (cl *Client) NetworkModeSet(ctx context.Context, mode NetworkMode) (bool, error) {
+	return cl.doReqModeChange(ctx, func(vals map[string]string) {
+		vals["NetworkMode"] = string(mode)
+	})
+}
+
+// LTEBandLock locks the device to the specified LTE bands, retaining the
+// current network mode and network band. When no bands are specified, the
+// lock is cleared (ie, all bands are allowed).
+func (cl *Client) LTEBandLock(ctx context.Context, bands ...Band) (bool, error) {
+	b := Bands(bands...)
+	if b == 0 {
+		b = BandAll
+	}
+	return cl.doReqModeChange(ctx, func(vals map[string]string) {
+		vals["LTEBand"] = b.String()
+	})
+}
+
+// NRBandLock locks NR capable devices to the specified NR (5G) bands,
+// retaining the current network mode and other band settings. When no bands
+// are specified, the lock is cleared (ie, all bands are allowed).
+func (cl *Client) NRBandLock(ctx context.Context, bands ...NRBand) (bool, error) {
+	return cl.doReqModeChange(ctx, func(vals map[string]string) {
+		vals["NRBand"] = NRBands(bands...)
+	})
+}
+
+// NRMode retrieves the NR (5G) SA/NSA mode preference of NR capable devices.
+func (cl *Client) NRMode(ctx context.Context) (NRMode, error) {
+	s, err := cl.doReqString(ctx, "api/net/nr-mode", nil, "nrmode")
+	if err != nil {
+		return NRModeAuto, err
+	}
+	i, err := strconv.Atoi(s)
+	if err != nil {
+		return NRModeAuto, ErrInvalidValue
+	}
+	return NRMode(i), nil
+}
+
+// NRModeSet sets the NR (5G) SA/NSA mode preference of NR capable devices.
+func (cl *Client) NRModeSet(ctx context.Context, mode NRMode) (bool, error) {
+	return cl.doReqCheckOK(ctx, "api/net/nr-mode", NewRequest(
+		"nrmode", fmt.Sprintf("%d", mode),
+	))
+}
+
+// NetworkScan scans for available networks (PLMNs). Note that scanning can
+// take a long time, and uses NetworkScanTimeout as the request timeout,
+// unless overridden with WithCallTimeout.
+func (cl *Client) NetworkScan(ctx context.Context) ([]Network, error) {
+	if _, ok := ctx.Value(timeoutKey{}).(time.Duration); !ok {
+		ctx = WithCallTimeout(ctx, NetworkScanTimeout)
+	}
+	res, err := cl.Do(ctx, "api/net/plmn-list", nil)
+	if err != nil {
+		return nil, err
+	}
+	networks, ok := res["Networks"].(map[string]interface{})
+	if !ok {
+		return nil, nil
+	}
+	var l []Network
+	for _, n := range xmlList(networks["Network"]) {
+		l = append(l, Network{
+			Index:     xmlInt(n, "Index"),
+			State:     xmlInt(n, "State"),
+			FullName:  xmlStr(n, "FullName"),
+			ShortName: xmlStr(n, "ShortName"),
+			Numeric:   xmlStr(n, "Numeric"),
+			Rat:       Rat(xmlInt(n, "Rat")),
+		})
+	}
+	return l, nil
+}
+
+// NetworkRegister manually registers the device on the network with the
+// specified PLMN (ie, MCC and MNC) and radio access technology. When plmn is
+// empty, automatic network selection is restored.
+func (cl *Client) NetworkRegister(ctx context.Context, plmn string, rat Rat) (bool, error) {
+	mode, r := "1", fmt.Sprintf("%d", rat)
+	if plmn == "" {
+		mode, r = "0", ""
+	}
+	return cl.doReqCheckOK(ctx, "api/net/register", NewRequest(
+		"Mode", mode,
+		"Plmn", plmn,
+		"Rat", r,
+	))
+}
+
 // PinInfo retrieves SIM PIN status information.
 func (cl *Client) PinInfo(ctx context.Context) (XMLData, error) {
 	return cl.Do(ctx, "api/pin/status", nil)
@@ -531,7 +1880,7 @@ func (cl *Client) PinInfo(ctx context.Context) (XMLData, error) {
 
 // doReqPin wraps a SIM PIN manipulation request.
 func (cl *Client) doReqPin(ctx context.Context, pt PinType, cur, new, puk string) (bool, error) {
-	return cl.doReqCheckOK(ctx, "api/pin/operate", SimpleRequestXML(
+	return cl.doReqCheckOK(ctx, "api/pin/operate", NewRequest(
 		"OperateType", fmt.Sprintf("%d", pt),
 		"CurrentPin", cur,
 		"NewPin", new,
@@ -588,11 +1937,178 @@ func (cl *Client) Disconnect(ctx context.Context) (bool, error) {
 	})
 }
 
+// MobileDataEnabled retrieves whether mobile data is enabled.
+func (cl *Client) MobileDataEnabled(ctx context.Context) (bool, error) {
+	s, err := cl.doReqString(ctx, "api/dialup/mobile-dataswitch", nil, "dataswitch")
+	if err != nil {
+		return false, err
+	}
+	return s == "1", nil
+}
+
+// MobileDataSet enables or disables mobile data. On many newer devices, this
+// should be used instead of Connect/Disconnect.
+func (cl *Client) MobileDataSet(ctx context.Context, enabled bool) (bool, error) {
+	return cl.doReqCheckOK(ctx, "api/dialup/mobile-dataswitch", NewRequest(
+		"dataswitch", boolToString(enabled),
+	))
+}
+
+// ConnectionSettings retrieves the dialup connection settings.
+func (cl *Client) ConnectionSettings(ctx context.Context) (*ConnectionSettings, error) {
+	res, err := cl.ConnectionInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &ConnectionSettings{
+		ConnectMode:       ConnectMode(xmlInt(res, "ConnectMode")),
+		AutoReconnect:     xmlStr(res, "AutoReconnect") == "1",
+		RoamAutoConnect:   xmlStr(res, "RoamAutoConnectEnable") == "1",
+		RoamAutoReconnect: xmlStr(res, "RoamAutoReconnectEnable") == "1",
+		ReconnectInterval: time.Duration(xmlInt(res, "ReconnectInterval")) * time.Second,
+		MaxIdleTime:       time.Duration(xmlInt(res, "MaxIdelTime", "MaxIdleTime")) * time.Second,
+		MTU:               xmlInt(res, "MTU"),
+	}, nil
+}
+
+// ConnectionSettingsSet sets the dialup connection settings.
+func (cl *Client) ConnectionSettingsSet(ctx context.Context, s ConnectionSettings) (bool, error) {
+	// note: the misspelled MaxIdelTime is what the device expects
+	return cl.doReqCheckOK(ctx, "api/dialup/connection", NewRequest(
+		"RoamAutoConnectEnable", boolToString(s.RoamAutoConnect),
+		"AutoReconnect", boolToString(s.AutoReconnect),
+		"RoamAutoReconnectEnable", boolToString(s.RoamAutoReconnect),
+		"ReconnectInterval", fmt.Sprintf("%d", int(s.ReconnectInterval/time.Second)),
+		"MaxIdelTime", fmt.Sprintf("%d", int(s.MaxIdleTime/time.Second)),
+		"ConnectMode", fmt.Sprintf("%d", s.ConnectMode),
+		"MTU", fmt.Sprintf("%d", s.MTU),
+	))
+}
+
 // ProfileInfo retrieves profile information (ie, APN).
 func (cl *Client) ProfileInfo(ctx context.Context) (XMLData, error) {
 	return cl.Do(ctx, "api/dialup/profiles", nil)
 }
 
+// Profiles retrieves the dialup (APN) profiles.
+func (cl *Client) Profiles(ctx context.Context) ([]Profile, error) {
+	res, err := cl.ProfileInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	def := xmlInt(res, "CurrentProfile")
+	profiles, ok := res["Profiles"].(map[string]interface{})
+	if !ok {
+		return nil, nil
+	}
+	var l []Profile
+	for _, p := range xmlList(profiles["Profile"]) {
+		i := xmlInt(p, "Index")
+		l = append(l, Profile{
+			Index:    i,
+			Name:     xmlStr(p, "Name"),
+			ApnName:  xmlStr(p, "ApnName"),
+			Username: xmlStr(p, "Username"),
+			Password: xmlStr(p, "Password"),
+			AuthMode: AuthMode(xmlInt(p, "AuthMode")),
+			IPType:   IPType(xmlInt(p, "iptype")),
+			ReadOnly: xmlStr(p, "ReadOnly") == "1",
+			Default:  i == def,
+		})
+	}
+	return l, nil
+}
+
+// doReqProfile wraps a dialup profile manipulation request, where modify is 0
+// (none), 1 (add), or 2 (edit).
+func (cl *Client) doReqProfile(ctx context.Context, del, def, modify int, p *Profile) (bool, error) {
+	// use current default when not specified
+	if def == 0 {
+		s, err := cl.doReqString(ctx, "api/dialup/profiles", nil, "CurrentProfile")
+		if err != nil {
+			return false, err
+		}
+		if def, err = strconv.Atoi(s); err != nil {
+			return false, ErrInvalidValue
+		}
+	}
+	req := NewRequest(
+		"Delete", fmt.Sprintf("%d", del),
+		"SetDefault", fmt.Sprintf("%d", def),
+		"Modify", fmt.Sprintf("%d", modify),
+	)
+	if p != nil {
+		index := ""
+		if p.Index != 0 {
+			index = fmt.Sprintf("%d", p.Index)
+		}
+		req.AddNested("Profile", NewRequest(
+			"Index", index,
+			"IsValid", "1",
+			"Name", p.Name,
+			"ApnIsStatic", boolToString(p.ApnName != ""),
+			"ApnName", p.ApnName,
+			"DialupNum", "*99#",
+			"Username", p.Username,
+			"Password", p.Password,
+			"AuthMode", fmt.Sprintf("%d", p.AuthMode),
+			"IpIsStatic", "",
+			"IpAddress", "",
+			"DnsIsStatic", "",
+			"PrimaryDns", "",
+			"SecondaryDns", "",
+			"ReadOnly", "0",
+			"iptype", fmt.Sprintf("%d", p.IPType),
+		))
+	}
+	// send request (order matters above!)
+	return cl.doReqCheckOK(ctx, "api/dialup/profiles", req)
+}
+
+// ProfileCreate creates a dialup (APN) profile. The profile is made the
+// default profile when p.Default is true.
+func (cl *Client) ProfileCreate(ctx context.Context, p Profile) (bool, error) {
+	p.Index = 0
+	def := 0
+	if p.Default {
+		// the device assigns the next index to new profiles
+		l, err := cl.Profiles(ctx)
+		if err != nil {
+			return false, err
+		}
+		def = 1
+		for _, z := range l {
+			if z.Index >= def {
+				def = z.Index + 1
+			}
+		}
+	}
+	return cl.doReqProfile(ctx, 0, def, 1, &p)
+}
+
+// ProfileUpdate updates the dialup (APN) profile with index p.Index. The
+// profile is made the default profile when p.Default is true.
+func (cl *Client) ProfileUpdate(ctx context.Context, p Profile) (bool, error) {
+	if p.Index == 0 {
+		return false, ErrInvalidValue
+	}
+	def := 0
+	if p.Default {
+		def = p.Index
+	}
+	return cl.doReqProfile(ctx, 0, def, 2, &p)
+}
+
+// ProfileDelete deletes the dialup (APN) profile with the specified index.
+func (cl *Client) ProfileDelete(ctx context.Context, index uint) (bool, error) {
+	return cl.doReqProfile(ctx, int(index), 0, 0, nil)
+}
+
+// ProfileSetDefault sets the default dialup (APN) profile.
+func (cl *Client) ProfileSetDefault(ctx context.Context, index uint) (bool, error) {
+	return cl.doReqProfile(ctx, 0, int(index), 0, nil)
+}
+
 // SmsFeatures retrieves SMS feature information.
 func (cl *Client) SmsFeatures(ctx context.Context) (XMLData, error) {
 	return cl.Do(ctx, "api/sms/sms-feature-switch", nil)
@@ -601,7 +2117,7 @@ func (cl *Client) SmsFeatures(ctx context.Context) (XMLData, error) {
 // SmsList retrieves list of SMS in an inbox.
 func (cl *Client) SmsList(ctx context.Context, boxType, page, count uint, sortByName, ascending, unreadPreferred bool) (XMLData, error) {
 	// execute request -- note: the order is important!
-	return cl.Do(ctx, "api/sms/sms-list", SimpleRequestXML(
+	return cl.Do(ctx, "api/sms/sms-list", NewRequest(
 		"PageIndex", fmt.Sprintf("%d", page),
 		"ReadCount", fmt.Sprintf("%d", count),
 		"BoxType", fmt.Sprintf("%d", boxType),
@@ -611,70 +2127,260 @@ func (cl *Client) SmsList(ctx context.Context, boxType, page, count uint, sortBy
 	))
 }
 
+// SmsMessages retrieves a page of SMS messages in an inbox.
+func (cl *Client) SmsMessages(ctx context.Context, boxType SmsBoxType, page, count uint) ([]SmsMessage, error) {
+	res, err := cl.SmsList(ctx, uint(boxType), page, count, false, false, false)
+	if err != nil {
+		return nil, err
+	}
+	return smsMessages(res), nil
+}
+
+// SmsListAll retrieves all SMS messages in an inbox, fetching pages of
+// SmsPageSize messages until the count reported by SmsCount is exhausted.
+func (cl *Client) SmsListAll(ctx context.Context, boxType SmsBoxType) ([]SmsMessage, error) {
+	res, err := cl.SmsCount(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var total int
+	switch boxType {
+	case SmsBoxTypeInbox:
+		total = xmlInt(res, "LocalInbox")
+	case SmsBoxTypeOutbox:
+		total = xmlInt(res, "LocalOutbox")
+	case SmsBoxTypeDraft:
+		total = xmlInt(res, "LocalDraft")
+	}
+	var l []SmsMessage
+	for page := uint(1); len(l) < total; page++ {
+		messages, err := cl.SmsMessages(ctx, boxType, page, SmsPageSize)
+		if err != nil {
+			return nil, err
+		}
+		if len(messages) == 0 {
+			break
+		}
+		l = append(l, messages...)
+	}
+	return l, nil
+}
+
 // SmsCount retrieves count of SMS per inbox type.
 func (cl *Client) SmsCount(ctx context.Context) (XMLData, error) {
 	return cl.Do(ctx, "api/sms/sms-count", nil)
 }
 
-// SmsSend sends an SMS.
+// SmsCounts retrieves the SMS message counts.
+func (cl *Client) SmsCounts(ctx context.Context) (*SmsCounts, error) {
+	res, err := cl.SmsCount(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &SmsCounts{
+		LocalUnread: xmlInt(res, "LocalUnread"),
+		LocalInbox:  xmlInt(res, "LocalInbox"),
+		LocalOutbox: xmlInt(res, "LocalOutbox"),
+		LocalDraft:  xmlInt(res, "LocalDraft"),
+		LocalMax:    xmlInt(res, "LocalMax"),
+		SimUnread:   xmlInt(res, "SimUnread"),
+		SimInbox:    xmlInt(res, "SimInbox"),
+		SimOutbox:   xmlInt(res, "SimOutbox"),
+		SimMax:      xmlInt(res, "SimMax"),
+		NewMsg:      xmlInt(res, "NewMsg"),
+	}, nil
+}
+
+// SmsSend sends an SMS. Messages too long for a single SMS are sent by the
+// device as a concatenated SMS of up to SmsMaxParts parts.
 func (cl *Client) SmsSend(ctx context.Context, msg string, to ...string) (bool, error) {
-	if len(msg) >= 160 {
+	return cl.SmsSendOpts(ctx, SmsOptions{}, msg, to...)
+}
+
+// SmsSendOpts sends an SMS using the specified service center address, save
+// location, and priority options.
+func (cl *Client) SmsSendOpts(ctx context.Context, opts SmsOptions, msg string, to ...string) (bool, error) {
+	if len(SmsParts(msg)) > SmsMaxParts {
 		return false, ErrMessageTooLong
 	}
+	length, _, _ := SmsLength(msg)
 	// build phones
-	phones := []string{}
+	phones := NewRequest()
 	for _, t := range to {
-		phones = append(phones, "Phone", t)
+		phones.Add("Phone", t)
+	}
+	// build request (order matters below!)
+	req := NewRequest().
+		Add("Index", "-1").
+		AddNested("Phones", phones).
+		Add("Sca", opts.Sca).
+		Add("Content", msg).
+		Add("Length", fmt.Sprintf("%d", length)).
+		Add("Reserved", "1").
+		Add("Date", time.Now().Format("2006-01-02 15:04:05"))
+	if opts.SaveType != SmsSaveTypeDefault {
+		req.Add("SaveType", fmt.Sprintf("%d", opts.SaveType))
+	}
+	if opts.Priority != SmsPriorityNormal {
+		req.Add("Priority", fmt.Sprintf("%d", opts.Priority))
+	}
+	return cl.doReqCheckOK(ctx, "api/sms/send-sms", req)
+}
+
+// SmsDeliveryReportSet enables or disables SMS delivery (status) reports.
+func (cl *Client) SmsDeliveryReportSet(ctx context.Context, enabled bool) (bool, error) {
+	res, err := cl.SmsConfig(ctx)
+	if err != nil {
+		return false, err
 	}
 	// send request (order matters below!)
-	return cl.doReqCheckOK(ctx, "api/sms/send-sms", SimpleRequestXML(
+	return cl.doReqCheckOK(ctx, "api/sms/config", NewRequest(
+		"SaveMode", xmlStr(res, "SaveMode"),
+		"Validity", xmlStr(res, "Validity"),
+		"Sca", xmlStr(res, "Sca"),
+		"UseSReport", boolToString(enabled),
+		"SendType", xmlStr(res, "SendType"),
+		"Priority", xmlStr(res, "Priority"),
+	))
+}
+
+// SmsDeliveryReports retrieves the delivery (status) reports in the first
+// count messages of the inbox, matching each to the most recent outbox
+// message sent to the same phone number prior to the report.
+func (cl *Client) SmsDeliveryReports(ctx context.Context, count uint) ([]DeliveryReport, error) {
+	inbox, err := cl.SmsMessages(ctx, SmsBoxTypeInbox, 1, count)
+	if err != nil {
+		return nil, err
+	}
+	outbox, err := cl.SmsMessages(ctx, SmsBoxTypeOutbox, 1, count)
+	if err != nil {
+		return nil, err
+	}
+	var l []DeliveryReport
+	for _, m := range inbox {
+		if m.Type != SmsTypeStatusReport {
+			continue
+		}
+		r := DeliveryReport{
+			Index:     m.Index,
+			Phone:     m.Phone,
+			Date:      m.Date,
+			Content:   m.Content,
+			Delivered: !strings.Contains(strings.ToLower(m.Content), "fail"),
+		}
+		var sent time.Time
+		for _, o := range outbox {
+			if samePhone(o.Phone, m.Phone) && !o.Date.After(m.Date) && o.Date.After(sent) {
+				r.MessageIndex, sent = o.Index, o.Date
+			}
+		}
+		l = append(l, r)
+	}
+	return l, nil
+}
+
+// SmsSendPdu sends a raw (hex encoded) SMS PDU with the specified TPDU
+// length, on devices supporting raw PDU mode.
+func (cl *Client) SmsSendPdu(ctx context.Context, pdu string, length uint) (bool, error) {
+	return cl.doReqCheckOK(ctx, "api/sms/send-pdu", NewRequest(
 		"Index", "-1",
-		"Phones", "\n"+string(xmlPairs("    ", phones...)),
-		"Sca", "",
-		"Content", msg,
-		"Length", fmt.Sprintf("%d", len(msg)),
-		"Reserved", "1",
-		"Date", time.Now().Format("2006-01-02 15:04:05"),
+		"Pdu", pdu,
+		"Length", fmt.Sprintf("%d", length),
+	))
+}
+
+// SmsSendSubmit encodes and sends a SMS-SUBMIT message as a raw PDU, allowing
+// flash messages, custom data coding schemes, and binary payloads to be sent
+// on devices supporting raw PDU mode.
+func (cl *Client) SmsSendSubmit(ctx context.Context, s pdu.Submit) (bool, error) {
+	p, n, err := s.EncodeHex()
+	if err != nil {
+		return false, err
+	}
+	return cl.SmsSendPdu(ctx, p, uint(n))
+}
+
+// SmsPduList retrieves list of SMS in an inbox as raw PDUs, on devices
+// supporting raw PDU mode.
+func (cl *Client) SmsPduList(ctx context.Context, boxType, page, count uint) (XMLData, error) {
+	// execute request -- note: the order is important!
+	return cl.Do(ctx, "api/sms/sms-list-pdu", NewRequest(
+		"PageIndex", fmt.Sprintf("%d", page),
+		"ReadCount", fmt.Sprintf("%d", count),
+		"BoxType", fmt.Sprintf("%d", boxType),
 	))
 }
 
+// SmsPduMessages retrieves and decodes a page of raw PDU SMS messages in an
+// inbox, on devices supporting raw PDU mode.
+func (cl *Client) SmsPduMessages(ctx context.Context, boxType SmsBoxType, page, count uint) ([]*pdu.Deliver, error) {
+	res, err := cl.SmsPduList(ctx, uint(boxType), page, count)
+	if err != nil {
+		return nil, err
+	}
+	messages, ok := res["Messages"].(map[string]interface{})
+	if !ok {
+		return nil, nil
+	}
+	var l []*pdu.Deliver
+	for _, m := range xmlList(messages["Message"]) {
+		d, err := pdu.DecodeHex(xmlStr(m, "Pdu"))
+		if err != nil {
+			return nil, err
+		}
+		l = append(l, d)
+	}
+	return l, nil
+}
+
 // SmsSendStatus retrieves SMS send status information.
 func (cl *Client) SmsSendStatus(ctx context.Context) (XMLData, error) {
 	return cl.Do(ctx, "api/sms/send-status", nil)
 }
 
-// SmsReadSet sets the read status of a SMS.
-func (cl *Client) SmsReadSet(ctx context.Context, id string) (bool, error) {
-	return cl.doReqCheckOK(ctx, "api/sms/set-read", SimpleRequestXML(
-		"Index", id,
-	))
+// SmsReadSet sets the read status of one or more SMS.
+func (cl *Client) SmsReadSet(ctx context.Context, id ...string) (bool, error) {
+	var vals []string
+	for _, i := range id {
+		vals = append(vals, "Index", i)
+	}
+	return cl.doReqCheckOK(ctx, "api/sms/set-read", NewRequest(vals...))
+}
+
+// SmsMarkAllRead sets the read status of all unread SMS in an inbox, in
+// batches of SmsPageSize.
+func (cl *Client) SmsMarkAllRead(ctx context.Context, boxType SmsBoxType) (bool, error) {
+	msgs, err := cl.SmsListAll(ctx, boxType)
+	if err != nil {
+		return false, err
+	}
+	var ids []string
+	for _, m := range msgs {
+		if m.Unread() {
+			ids = append(ids, fmt.Sprintf("%d", m.Index))
+		}
+	}
+	for len(ids) != 0 {
+		n := SmsPageSize
+		if len(ids) < n {
+			n = len(ids)
+		}
+		ok, err := cl.SmsReadSet(ctx, ids[:n]...)
+		if err != nil || !ok {
+			return ok, err
+		}
+		ids = ids[n:]
+	}
+	return true, nil
 }
 
 // SmsDelete deletes a specified SMS.
 func (cl *Client) SmsDelete(ctx context.Context, id uint) (bool, error) {
-	return cl.doReqCheckOK(ctx, "api/sms/delete-sms", SimpleRequestXML(
+	return cl.doReqCheckOK(ctx, "api/sms/delete-sms", NewRequest(
 		"Index", fmt.Sprintf("%d", id),
 	))
 }
 
-// doReqConn wraps a connection manipulation request.
-/*func (cl *Client) doReqConn(
-	ctx context.Context,
-	cm ConnectMode,
-	autoReconnect, roamAutoConnect, roamAutoReconnect bool,
-	interval, idle int,
-) (bool, error) {
-	boolToString()
-	return cl.doReqCheckOK(ctx, "api/dialup/connection", SimpleRequestXML(
-		"RoamAutoConnectEnable", boolToString(roamAutoConnect),
-		"AutoReconnect", boolToString(autoReconnect),
-		"RoamAutoReconnectEnable", boolToString(roamAutoReconnect),
-		"ReconnectInterval", fmt.Sprintf("%d", interval),
-		"MaxIdleTime", fmt.Sprintf("%d", idle),
-		"ConnectMode", cm.String(),
-	))
-}*/
-
 // UssdStatus retrieves current USSD session status information.
 func (cl *Client) UssdStatus(ctx context.Context) (UssdState, error) {
 	s, err := cl.doReqString(ctx, "api/ussd/status", nil, "result")
@@ -690,41 +2396,162 @@ func (cl *Client) UssdStatus(ctx context.Context) (UssdState, error) {
 
 // UssdCode sends a USSD code to the Hilink device.
 func (cl *Client) UssdCode(ctx context.Context, code string) (bool, error) {
-	return cl.doReqCheckOK(ctx, "api/ussd/send", SimpleRequestXML(
+	return cl.UssdCodeOpts(ctx, code, UssdCodeTypeText, 0)
+}
+
+// UssdCodeOpts sends a USSD code to the Hilink device using the specified
+// code type and timeout. When the code type is raw, the code is sent as hex
+// encoded, packed GSM-7. A zero timeout uses the device's default timeout.
+func (cl *Client) UssdCodeOpts(ctx context.Context, code string, codeType UssdCodeType, timeout time.Duration) (bool, error) {
+	if codeType == UssdCodeTypeRaw {
+		b, err := pdu.EncodePackedGSM7(code)
+		if err != nil {
+			return false, err
+		}
+		code = strings.ToUpper(hex.EncodeToString(b))
+	}
+	var t string
+	if timeout != 0 {
+		t = fmt.Sprintf("%d", int(timeout/time.Second))
+	}
+	return cl.doReqCheckOK(ctx, "api/ussd/send", NewRequest(
 		"content", code,
-		"codeType", "CodeType",
-		"timeout", "",
+		"codeType", string(codeType),
+		"timeout", t,
 	))
 }
 
-// UssdContent retrieves content buffer of the active USSD session.
-func (cl *Client) UssdContent(ctx context.Context) (string, error) {
-	return cl.doReqString(ctx, "api/ussd/get", nil, "content")
+// UssdReply sends a reply to a prompt of the active USSD session (ie, a menu
+// selection).
+func (cl *Client) UssdReply(ctx context.Context, text string) (bool, error) {
+	return cl.UssdCode(ctx, text)
+}
+
+// UssdContent retrieves content buffer of the active USSD session.
+func (cl *Client) UssdContent(ctx context.Context) (string, error) {
+	return cl.doReqString(ctx, "api/ussd/get", nil, "content")
+}
+
+// UssdContentOpts retrieves content buffer of the active USSD session, for a
+// code sent using the specified code type. When the code type is raw, the
+// content returned hex encoded (as by some operators) is decoded as packed
+// GSM-7 or UCS-2.
+func (cl *Client) UssdContentOpts(ctx context.Context, codeType UssdCodeType) (string, error) {
+	s, err := cl.UssdContent(ctx)
+	if err != nil || codeType != UssdCodeTypeRaw {
+		return s, err
+	}
+	return ussdDecode(s), nil
+}
+
+// UssdRelease releases the active USSD session.
+func (cl *Client) UssdRelease(ctx context.Context) (bool, error) {
+	return cl.doReqCheckOK(ctx, "api/ussd/release", nil)
+}
+
+// DdnsList retrieves list of DDNS providers.
+func (cl *Client) DdnsList(ctx context.Context) (XMLData, error) {
+	return cl.Do(ctx, "api/ddns/ddns-list", nil)
+}
+
+// LogPath retrieves device log path (URL).
+func (cl *Client) LogPath(ctx context.Context) (string, error) {
+	return cl.doReqString(ctx, "api/device/compresslogfile", nil, "LogPath")
+}
+
+// LogInfo retrieves current log setting information.
+func (cl *Client) LogInfo(ctx context.Context) (XMLData, error) {
+	return cl.Do(ctx, "api/device/logsetting", nil)
+}
+
+// LogSettingSet sets the log level and enables or disables logging.
+func (cl *Client) LogSettingSet(ctx context.Context, level uint, enabled bool) (bool, error) {
+	return cl.doReqCheckOK(ctx, "api/device/logsetting", NewRequest(
+		"loglevel", fmt.Sprintf("%d", level),
+		"logswitch", boolToString(enabled),
+	))
+}
+
+// LogDownload retrieves the compressed device log file using the current
+// session, writing it to w. Returns the number of bytes written. The download
+// is only bounded by the context (and not the client's request timeout),
+// unless overridden with WithCallTimeout.
+func (cl *Client) LogDownload(ctx context.Context, w io.Writer) (_ int64, err error) {
+	p, err := cl.LogPath(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if _, ok := ctx.Value(timeoutKey{}).(time.Duration); !ok {
+		ctx = WithCallTimeout(ctx, 0)
+	}
+	// the log path is relative to the endpoint
+	path := strings.TrimPrefix(p, "/")
+	ctx, end := cl.startSpan(ctx, path, nil)
+	defer func() { end(err) }()
+	res, done, err := cl.doStream(ctx, path, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer done()
+	if err := cl.checkResponse(res, nil); err != nil {
+		return 0, err
+	}
+	return io.Copy(w, res.Body)
+}
+
+// FirmwareUpdateCheck triggers a check for new firmware versions.
+func (cl *Client) FirmwareUpdateCheck(ctx context.Context) (bool, error) {
+	return cl.doReqCheckOK(ctx, "api/online-update/check-new-version", NewRequest())
 }
 
-// UssdRelease releases the active USSD session.
-func (cl *Client) UssdRelease(ctx context.Context) (bool, error) {
-	return cl.doReqCheckOK(ctx, "api/ussd/release", nil)
+// FirmwareNewVersion retrieves the result of the last firmware update check.
+func (cl *Client) FirmwareNewVersion(ctx context.Context) (*FirmwareVersion, error) {
+	res, err := cl.Do(ctx, "api/online-update/check-new-version", nil)
+	if err != nil {
+		return nil, err
+	}
+	v := &FirmwareVersion{
+		Version: xmlStr(res, "Version", "NewVersion", "version"),
+		Size:    xmlUint(res, "Size", "FileSize"),
+		Notes:   xmlStr(res, "ReleaseNote", "Description"),
+	}
+	v.Available = v.Version != "" || xmlStr(res, "NewVersionAvailable") == "1"
+	return v, nil
 }
 
-// DdnsList retrieves list of DDNS providers.
-func (cl *Client) DdnsList(ctx context.Context) (XMLData, error) {
-	return cl.Do(ctx, "api/ddns/ddns-list", nil)
+// FirmwareUpdateStatus retrieves the status of the firmware update process.
+func (cl *Client) FirmwareUpdateStatus(ctx context.Context) (*FirmwareStatus, error) {
+	res, err := cl.Do(ctx, "api/online-update/status", nil)
+	if err != nil {
+		return nil, err
+	}
+	return &FirmwareStatus{
+		Status:     xmlInt(res, "CurrentComponentStatus", "Status"),
+		Progress:   xmlInt(res, "CurrentComponentProgress", "Progress", "DownloadProgress"),
+		Component:  xmlInt(res, "CurrentComponentIndex"),
+		Components: xmlInt(res, "TotalComponents", "CurrentComponents"),
+	}, nil
 }
 
-// LogPath retrieves device log path (URL).
-func (cl *Client) LogPath(ctx context.Context) (string, error) {
-	return cl.doReqString(ctx, "api/device/compresslogfile", nil, "LogPath")
+// FirmwareAutoUpdate retrieves whether automatic firmware updates are enabled.
+func (cl *Client) FirmwareAutoUpdate(ctx context.Context) (bool, error) {
+	s, err := cl.doReqString(ctx, "api/online-update/autoupdate-config", nil, "auto_update")
+	if err != nil {
+		return false, err
+	}
+	return s == "1", nil
 }
 
-// LogInfo retrieves current log setting information.
-func (cl *Client) LogInfo(ctx context.Context) (XMLData, error) {
-	return cl.Do(ctx, "api/device/logsetting", nil)
+// FirmwareAutoUpdateSet enables or disables automatic firmware updates.
+func (cl *Client) FirmwareAutoUpdateSet(ctx context.Context, enabled bool) (bool, error) {
+	return cl.doReqCheckOK(ctx, "api/online-update/autoupdate-config", NewRequest(
+		"auto_update", boolToString(enabled),
+	))
 }
 
 // PhonebookGroupList retrieves list of the phonebook groups.
 func (cl *Client) PhonebookGroupList(ctx context.Context, page, count uint, sortByName, ascending bool) (XMLData, error) {
-	return cl.Do(ctx, "api/pb/group-list", SimpleRequestXML(
+	return cl.Do(ctx, "api/pb/group-list", NewRequest(
 		"PageIndex", fmt.Sprintf("%d", page),
 		"ReadCount", fmt.Sprintf("%d", count),
 		"SortType", boolToString(sortByName),
@@ -746,7 +2573,7 @@ func (cl *Client) PhonebookImport(ctx context.Context, group uint) (XMLData, err
 
 // PhonebookDelete deletes a specified phonebook entry.
 func (cl *Client) PhonebookDelete(ctx context.Context, id uint) (bool, error) {
-	return cl.doReqCheckOK(ctx, "api/pb/delete-pb", SimpleRequestXML(
+	return cl.doReqCheckOK(ctx, "api/pb/delete-pb", NewRequest(
 		"Index", fmt.Sprintf("%d", id),
 	))
 }
@@ -754,7 +2581,7 @@ func (cl *Client) PhonebookDelete(ctx context.Context, id uint) (bool, error) {
 // PhonebookList retrieves list of phonebook entries from a specified group.
 func (cl *Client) PhonebookList(ctx context.Context, group, page, count uint, sim, sortByName, ascending bool, keyword string) (XMLData, error) {
 	// execute request -- note: the order is important!
-	return cl.Do(ctx, "api/pb/pb-list", SimpleRequestXML(
+	return cl.Do(ctx, "api/pb/pb-list", NewRequest(
 		"GroupID", fmt.Sprintf("%d", group),
 		"PageIndex", fmt.Sprintf("%d", page),
 		"ReadCount", fmt.Sprintf("%d", count),
@@ -765,17 +2592,56 @@ func (cl *Client) PhonebookList(ctx context.Context, group, page, count uint, si
 	))
 }
 
+// PhonebookEntries retrieves a page of phonebook entries from a specified
+// group.
+func (cl *Client) PhonebookEntries(ctx context.Context, group, page, count uint, sim bool) ([]PhonebookEntry, error) {
+	res, err := cl.PhonebookList(ctx, group, page, count, sim, false, true, "")
+	if err != nil {
+		return nil, err
+	}
+	phonebooks, ok := res["Phonebooks"].(map[string]interface{})
+	if !ok {
+		return nil, nil
+	}
+	var l []PhonebookEntry
+	for _, z := range xmlList(phonebooks["Phonebook"]) {
+		e := PhonebookEntry{
+			Index:   uint(xmlUint(z, "Index")),
+			GroupID: uint(xmlUint(z, "GroupID")),
+			SIM:     xmlStr(z, "SaveType") == "1",
+		}
+		for _, f := range xmlList(z["Field"]) {
+			v := xmlStr(f, "Value")
+			switch xmlStr(f, "Name") {
+			case "FormattedName":
+				e.Name = v
+			case "MobilePhone":
+				e.Phone = v
+			case "HomePhone":
+				e.HomePhone = v
+			case "WorkPhone":
+				e.WorkPhone = v
+			case "WorkEmail":
+				e.Email = v
+			}
+		}
+		l = append(l, e)
+	}
+	return l, nil
+}
+
 // PhonebookCreate creates a new phonebook entry.
 func (cl *Client) PhonebookCreate(ctx context.Context, group uint, name, phone string, sim bool) (XMLData, error) {
-	return cl.Do(ctx, "api/pb/pb-new", SimpleRequestXML(
+	return cl.Do(ctx, "api/pb/pb-new", NewRequest(
 		"GroupID", fmt.Sprintf("%d", group),
 		"SaveType", boolToString(sim),
-		"Field", xmlNvp("FormattedName", name),
-		"Field", xmlNvp("MobilePhone", phone),
-		"Field", xmlNvp("HomePhone", ""),
-		"Field", xmlNvp("WorkPhone", ""),
-		"Field", xmlNvp("WorkEmail", ""),
-	))
+	).
+		AddNested("Field", NewRequest("Name", "FormattedName", "Value", name)).
+		AddNested("Field", NewRequest("Name", "MobilePhone", "Value", phone)).
+		AddNested("Field", NewRequest("Name", "HomePhone", "Value", "")).
+		AddNested("Field", NewRequest("Name", "WorkPhone", "Value", "")).
+		AddNested("Field", NewRequest("Name", "WorkEmail", "Value", "")),
+	)
 }
 
 // FirewallFeatures retrieves firewall security feature information.
@@ -783,6 +2649,358 @@ func (cl *Client) FirewallFeatures(ctx context.Context) (XMLData, error) {
 	return cl.Do(ctx, "api/security/firewall-switch", nil)
 }
 
+// FirewallIPFilters retrieves the LAN IP filter rules.
+func (cl *Client) FirewallIPFilters(ctx context.Context) ([]IPFilter, error) {
+	res, err := cl.Do(ctx, "api/security/lan-ip-filter", nil)
+	if err != nil {
+		return nil, err
+	}
+	filters, ok := res["IPFilters"].(map[string]interface{})
+	if !ok {
+		return nil, nil
+	}
+	var l []IPFilter
+	for _, z := range xmlList(filters["IPFilter"]) {
+		l = append(l, IPFilter{
+			Enabled:    xmlStr(z, "Status") == "1",
+			Protocol:   Protocol(xmlInt(z, "Protocol")),
+			LanIP:      xmlStr(z, "LanIP"),
+			LanPort:    xmlInt(z, "LanPort"),
+			LanEndPort: xmlInt(z, "LanEndPort"),
+			WanIP:      xmlStr(z, "WanIP"),
+			WanPort:    xmlInt(z, "WanPort"),
+			WanEndPort: xmlInt(z, "WanEndPort"),
+		})
+	}
+	return l, nil
+}
+
+// doReqIPFilters wraps setting the complete list of LAN IP filter rules.
+func (cl *Client) doReqIPFilters(ctx context.Context, filters []IPFilter) (bool, error) {
+	var items [][]string
+	for _, f := range filters {
+		// order matters below!
+		items = append(items, []string{
+			"Status", boolToString(f.Enabled),
+			"Protocol", fmt.Sprintf("%d", f.Protocol),
+			"LanIP", f.LanIP,
+			"LanPort", fmt.Sprintf("%d", f.LanPort),
+			"LanEndPort", fmt.Sprintf("%d", f.LanEndPort),
+			"WanIP", f.WanIP,
+			"WanPort", fmt.Sprintf("%d", f.WanPort),
+			"WanEndPort", fmt.Sprintf("%d", f.WanEndPort),
+		})
+	}
+	return cl.doReqList(ctx, "api/security/lan-ip-filter", "IPFilters", "IPFilter", items...)
+}
+
+// FirewallIPFilterAdd adds a LAN IP filter rule.
+func (cl *Client) FirewallIPFilterAdd(ctx context.Context, filter IPFilter) (bool, error) {
+	l, err := cl.FirewallIPFilters(ctx)
+	if err != nil {
+		return false, err
+	}
+	return cl.doReqIPFilters(ctx, append(l, filter))
+}
+
+// FirewallIPFilterRemove removes the LAN IP filter rule with the specified
+// index (ie, its position in the list returned by FirewallIPFilters).
+func (cl *Client) FirewallIPFilterRemove(ctx context.Context, index uint) (bool, error) {
+	l, err := cl.FirewallIPFilters(ctx)
+	if err != nil {
+		return false, err
+	}
+	if int(index) >= len(l) {
+		return false, ErrInvalidValue
+	}
+	return cl.doReqIPFilters(ctx, append(l[:index], l[index+1:]...))
+}
+
+// FirewallMacFilters retrieves the MAC filter rules.
+func (cl *Client) FirewallMacFilters(ctx context.Context) ([]MacFilter, error) {
+	res, err := cl.Do(ctx, "api/security/mac-filter", nil)
+	if err != nil {
+		return nil, err
+	}
+	filters, ok := res["macfilters"].(map[string]interface{})
+	if !ok {
+		return nil, nil
+	}
+	var l []MacFilter
+	for _, z := range xmlList(filters["macfilter"]) {
+		l = append(l, MacFilter{
+			MacAddress: xmlStr(z, "value"),
+			Enabled:    xmlStr(z, "status") == "1",
+		})
+	}
+	return l, nil
+}
+
+// doReqMacFilters wraps setting the complete list of MAC filter rules.
+func (cl *Client) doReqMacFilters(ctx context.Context, filters []MacFilter) (bool, error) {
+	var items [][]string
+	for _, f := range filters {
+		items = append(items, []string{
+			"value", f.MacAddress,
+			"status", boolToString(f.Enabled),
+		})
+	}
+	return cl.doReqList(ctx, "api/security/mac-filter", "macfilters", "macfilter", items...)
+}
+
+// FirewallMacFilterAdd adds an enabled MAC filter rule for the specified MAC
+// address.
+func (cl *Client) FirewallMacFilterAdd(ctx context.Context, mac string) (bool, error) {
+	l, err := cl.FirewallMacFilters(ctx)
+	if err != nil {
+		return false, err
+	}
+	for _, f := range l {
+		if strings.EqualFold(f.MacAddress, mac) && f.Enabled {
+			return true, nil
+		}
+	}
+	return cl.doReqMacFilters(ctx, append(l, MacFilter{MacAddress: mac, Enabled: true}))
+}
+
+// FirewallMacFilterRemove removes the MAC filter rules for the specified MAC
+// address.
+func (cl *Client) FirewallMacFilterRemove(ctx context.Context, mac string) (bool, error) {
+	l, err := cl.FirewallMacFilters(ctx)
+	if err != nil {
+		return false, err
+	}
+	var filters []MacFilter
+	for _, f := range l {
+		if !strings.EqualFold(f.MacAddress, mac) {
+			filters = append(filters, f)
+		}
+	}
+	return cl.doReqMacFilters(ctx, filters)
+}
+
+// FirewallURLFilters retrieves the URL filter rules.
+func (cl *Client) FirewallURLFilters(ctx context.Context) ([]URLFilter, error) {
+	res, err := cl.Do(ctx, "api/security/url-filter", nil)
+	if err != nil {
+		return nil, err
+	}
+	filters, ok := res["urlfilters"].(map[string]interface{})
+	if !ok {
+		return nil, nil
+	}
+	var l []URLFilter
+	for _, z := range xmlList(filters["urlfilter"]) {
+		l = append(l, URLFilter{
+			URL:     xmlStr(z, "value"),
+			Enabled: xmlStr(z, "status") == "1",
+		})
+	}
+	return l, nil
+}
+
+// doReqURLFilters wraps setting the complete list of URL filter rules.
+func (cl *Client) doReqURLFilters(ctx context.Context, filters []URLFilter) (bool, error) {
+	var items [][]string
+	for _, f := range filters {
+		items = append(items, []string{
+			"value", f.URL,
+			"status", boolToString(f.Enabled),
+		})
+	}
+	return cl.doReqList(ctx, "api/security/url-filter", "urlfilters", "urlfilter", items...)
+}
+
+// FirewallURLFilterAdd adds an enabled URL filter rule for the specified URL.
+func (cl *Client) FirewallURLFilterAdd(ctx context.Context, url string) (bool, error) {
+	l, err := cl.FirewallURLFilters(ctx)
+	if err != nil {
+		return false, err
+	}
+	for _, f := range l {
+		if f.URL == url && f.Enabled {
+			return true, nil
+		}
+	}
+	return cl.doReqURLFilters(ctx, append(l, URLFilter{URL: url, Enabled: true}))
+}
+
+// FirewallURLFilterRemove removes the URL filter rules for the specified URL.
+func (cl *Client) FirewallURLFilterRemove(ctx context.Context, url string) (bool, error) {
+	l, err := cl.FirewallURLFilters(ctx)
+	if err != nil {
+		return false, err
+	}
+	var filters []URLFilter
+	for _, f := range l {
+		if f.URL != url {
+			filters = append(filters, f)
+		}
+	}
+	return cl.doReqURLFilters(ctx, filters)
+}
+
+// TimeRules retrieves the parental control (internet access time limit)
+// rules.
+func (cl *Client) TimeRules(ctx context.Context) ([]TimeRule, error) {
+	res, err := cl.Do(ctx, "api/timerule/timerule", nil)
+	if err != nil {
+		return nil, err
+	}
+	rules, ok := res["timerules"].(map[string]interface{})
+	if !ok {
+		return nil, nil
+	}
+	var l []TimeRule
+	for _, z := range xmlList(rules["timerule"]) {
+		rule := TimeRule{
+			Name:    xmlStr(z, "Name"),
+			Enabled: xmlStr(z, "Enable") == "1",
+		}
+		for _, mac := range strings.Split(xmlStr(z, "MacAddresses"), ",") {
+			if mac = strings.TrimSpace(mac); mac != "" {
+				rule.MacAddresses = append(rule.MacAddresses, mac)
+			}
+		}
+		if rule.Start, err = parseClock(xmlStr(z, "StartTime")); err != nil {
+			return nil, err
+		}
+		if rule.Stop, err = parseClock(xmlStr(z, "EndTime")); err != nil {
+			return nil, err
+		}
+		if rule.Weekdays, err = parseWeekdays(xmlStr(z, "RepeatDays")); err != nil {
+			return nil, err
+		}
+		l = append(l, rule)
+	}
+	return l, nil
+}
+
+// doReqTimeRules wraps setting the complete list of parental control rules.
+func (cl *Client) doReqTimeRules(ctx context.Context, rules []TimeRule) (bool, error) {
+	var items [][]string
+	for i, r := range rules {
+		// order matters below!
+		items = append(items, []string{
+			"Index", strconv.Itoa(i),
+			"Name", r.Name,
+			"Enable", boolToString(r.Enabled),
+			"MacAddresses", strings.Join(r.MacAddresses, ","),
+			"StartTime", clockToString(r.Start),
+			"EndTime", clockToString(r.Stop),
+			"RepeatDays", weekdaysToString(r.Weekdays),
+		})
+	}
+	return cl.doReqList(ctx, "api/timerule/timerule", "timerules", "timerule", items...)
+}
+
+// TimeRuleAdd adds a parental control rule, replacing any existing rule with
+// the same name.
+func (cl *Client) TimeRuleAdd(ctx context.Context, rule TimeRule) (bool, error) {
+	if rule.Name == "" || len(rule.MacAddresses) == 0 {
+		return false, ErrInvalidValue
+	}
+	l, err := cl.TimeRules(ctx)
+	if err != nil {
+		return false, err
+	}
+	var rules []TimeRule
+	for _, r := range l {
+		if r.Name != rule.Name {
+			rules = append(rules, r)
+		}
+	}
+	return cl.doReqTimeRules(ctx, append(rules, rule))
+}
+
+// TimeRuleDelete deletes the parental control rule with the specified name.
+func (cl *Client) TimeRuleDelete(ctx context.Context, name string) (bool, error) {
+	l, err := cl.TimeRules(ctx)
+	if err != nil {
+		return false, err
+	}
+	var rules []TimeRule
+	for _, r := range l {
+		if r.Name != name {
+			rules = append(rules, r)
+		}
+	}
+	return cl.doReqTimeRules(ctx, rules)
+}
+
+// TimeRuleEnable enables or disables the parental control rules with the
+// specified names (ie, to switch a homework hours rule set on or off). When
+// no names are specified, all rules are changed.
+func (cl *Client) TimeRuleEnable(ctx context.Context, enabled bool, name ...string) (bool, error) {
+	l, err := cl.TimeRules(ctx)
+	if err != nil {
+		return false, err
+	}
+	names := make(map[string]bool)
+	for _, n := range name {
+		names[n] = true
+	}
+	for i := range l {
+		if len(names) == 0 || names[l[i].Name] {
+			l[i].Enabled = enabled
+		}
+	}
+	return cl.doReqTimeRules(ctx, l)
+}
+
+// RemoteAccess retrieves the WAN-side (remote) management access settings.
+func (cl *Client) RemoteAccess(ctx context.Context) (*RemoteAccess, error) {
+	res, err := cl.Do(ctx, "api/security/acls", nil)
+	if err != nil {
+		return nil, err
+	}
+	access := &RemoteAccess{
+		Enabled:      xmlStr(res, "RemoteManageEnable") == "1",
+		HTTPPort:     xmlInt(res, "RemoteManagePort"),
+		HTTPSEnabled: xmlStr(res, "RemoteManageHttpsEnable") == "1",
+		HTTPSPort:    xmlInt(res, "RemoteManageHttpsPort"),
+	}
+	if acls, ok := res["Acls"].(map[string]interface{}); ok {
+		for _, z := range xmlList(acls["Acl"]) {
+			if xmlStr(z, "AclStatus") != "0" {
+				access.AllowedIPs = append(access.AllowedIPs, xmlStr(z, "AclIp"))
+			}
+		}
+	}
+	return access, nil
+}
+
+// RemoteAccessSet sets the WAN-side (remote) management access settings.
+//
+// Note: when AllowedIPs is empty, remote management is permitted from any
+// address.
+func (cl *Client) RemoteAccessSet(ctx context.Context, access RemoteAccess) (bool, error) {
+	for _, port := range []int{access.HTTPPort, access.HTTPSPort} {
+		if port < 0 || port > 65535 {
+			return false, ErrInvalidValue
+		}
+	}
+	var items [][]string
+	for _, ip := range access.AllowedIPs {
+		if _, _, err := net.ParseCIDR(ip); err != nil && net.ParseIP(ip) == nil {
+			return false, ErrInvalidAddress
+		}
+		items = append(items, []string{
+			"AclIp", ip,
+			"AclStatus", "1",
+		})
+	}
+	// order matters below!
+	return cl.doReqCheckOK(ctx, "api/security/acls", NewRequest(
+		"RemoteManageEnable", boolToString(access.Enabled),
+		"RemoteManagePort", strconv.Itoa(access.HTTPPort),
+		"RemoteManageHttpsEnable", boolToString(access.HTTPSEnabled),
+		"RemoteManageHttpsPort", strconv.Itoa(access.HTTPSPort),
+	).
+		AddNested("Acls", requestItems("Acl", items...)),
+	)
+}
+
 // DmzConfig retrieves DMZ status and IP address of DMZ host.
 func (cl *Client) DmzConfig(ctx context.Context) (XMLData, error) {
 	return cl.Do(ctx, "api/security/dmz", nil)
@@ -791,7 +3009,7 @@ func (cl *Client) DmzConfig(ctx context.Context) (XMLData, error) {
 // DmzConfigSet enables or disables the DMZ and the DMZ IP address of the
 // device.
 func (cl *Client) DmzConfigSet(ctx context.Context, enabled bool, dmzIPAddress string) (bool, error) {
-	return cl.doReqCheckOK(ctx, "api/security/dmz", SimpleRequestXML(
+	return cl.doReqCheckOK(ctx, "api/security/dmz", NewRequest(
 		"DmzIPAddress", dmzIPAddress,
 		"DmzStatus", boolToString(enabled),
 	))
@@ -804,7 +3022,7 @@ func (cl *Client) SipAlg(ctx context.Context) (XMLData, error) {
 
 // SipAlgSet enables/disables SIP application-level gateway and sets SIP port.
 func (cl *Client) SipAlgSet(ctx context.Context, port uint, enabled bool) (bool, error) {
-	return cl.doReqCheckOK(ctx, "api/security/sip", SimpleRequestXML(
+	return cl.doReqCheckOK(ctx, "api/security/sip", NewRequest(
 		"SipPort", fmt.Sprintf("%d", port),
 		"SipStatus", boolToString(enabled),
 	))
@@ -817,7 +3035,7 @@ func (cl *Client) NatType(ctx context.Context) (XMLData, error) {
 
 // NatTypeSet sets NAT type (values: 0, 1).
 func (cl *Client) NatTypeSet(ctx context.Context, ntype uint) (bool, error) {
-	return cl.doReqCheckOK(ctx, "api/security/nat", SimpleRequestXML(
+	return cl.doReqCheckOK(ctx, "api/security/nat", NewRequest(
 		"NATType", fmt.Sprintf("%d", ntype),
 	))
 }
@@ -832,12 +3050,89 @@ func (cl *Client) UpnpSet(ctx context.Context, enabled bool) (bool, error) {
 	return cl.doReqCheckOK(
 		ctx,
 		"api/security/upnp",
-		SimpleRequestXML(
+		NewRequest(
 			"UpnpStatus", boolToString(enabled),
 		),
 	)
 }
 
+// VirtualServers retrieves the port forwarding (virtual server) rules.
+func (cl *Client) VirtualServers(ctx context.Context) ([]VirtualServer, error) {
+	res, err := cl.Do(ctx, "api/security/virtual-servers", nil)
+	if err != nil {
+		return nil, err
+	}
+	servers, ok := res["Servers"].(map[string]interface{})
+	if !ok {
+		return nil, nil
+	}
+	var l []VirtualServer
+	for _, z := range xmlList(servers["Server"]) {
+		l = append(l, VirtualServer{
+			Name:       xmlStr(z, "VirtualServerIPName"),
+			Enabled:    xmlStr(z, "VirtualServerStatus") == "1",
+			Protocol:   Protocol(xmlInt(z, "VirtualServerProtocol")),
+			RemoteIP:   xmlStr(z, "VirtualServerRemoteIP"),
+			WanPort:    xmlInt(z, "VirtualServerWanPort"),
+			WanEndPort: xmlInt(z, "VirtualServerWanEndPort"),
+			LanIP:      xmlStr(z, "VirtualServerIPAddress"),
+			LanPort:    xmlInt(z, "VirtualServerLanPort"),
+			LanEndPort: xmlInt(z, "VirtualServerLanEndPort"),
+		})
+	}
+	return l, nil
+}
+
+// doReqVirtualServers wraps setting the complete list of port forwarding
+// (virtual server) rules.
+func (cl *Client) doReqVirtualServers(ctx context.Context, servers []VirtualServer) (bool, error) {
+	var items [][]string
+	for _, v := range servers {
+		if v.WanEndPort == 0 {
+			v.WanEndPort = v.WanPort
+		}
+		if v.LanEndPort == 0 {
+			v.LanEndPort = v.LanPort
+		}
+		// order matters below!
+		items = append(items, []string{
+			"VirtualServerIPName", v.Name,
+			"VirtualServerStatus", boolToString(v.Enabled),
+			"VirtualServerRemoteIP", v.RemoteIP,
+			"VirtualServerWanPort", fmt.Sprintf("%d", v.WanPort),
+			"VirtualServerWanEndPort", fmt.Sprintf("%d", v.WanEndPort),
+			"VirtualServerLanPort", fmt.Sprintf("%d", v.LanPort),
+			"VirtualServerLanEndPort", fmt.Sprintf("%d", v.LanEndPort),
+			"VirtualServerIPAddress", v.LanIP,
+			"VirtualServerProtocol", fmt.Sprintf("%d", v.Protocol),
+		})
+	}
+	return cl.doReqList(ctx, "api/security/virtual-servers", "Servers", "Server", items...)
+}
+
+// VirtualServerAdd adds a port forwarding (virtual server) rule.
+func (cl *Client) VirtualServerAdd(ctx context.Context, server VirtualServer) (bool, error) {
+	l, err := cl.VirtualServers(ctx)
+	if err != nil {
+		return false, err
+	}
+	return cl.doReqVirtualServers(ctx, append(l, server))
+}
+
+// VirtualServerDelete deletes the port forwarding (virtual server) rule with
+// the specified index (ie, its position in the list returned by
+// VirtualServers).
+func (cl *Client) VirtualServerDelete(ctx context.Context, index uint) (bool, error) {
+	l, err := cl.VirtualServers(ctx)
+	if err != nil {
+		return false, err
+	}
+	if int(index) >= len(l) {
+		return false, ErrInvalidValue
+	}
+	return cl.doReqVirtualServers(ctx, append(l[:index], l[index+1:]...))
+}
+
 // TODO:
 // UserLogin/UserLogout/UserPasswordChange
 //
@@ -845,12 +3140,35 @@ func (cl *Client) UpnpSet(ctx context.Context, enabled bool) (bool, error) {
 // firewall ("security") configuration
 // wifi profile management
 
+// timeoutKey is the context key for overriding the request timeout.
+type timeoutKey struct{}
+
+// WithCallTimeout returns a copy of the context that overrides the client's
+// request timeout for calls made with it, for slow operations (ie, firmware
+// checks) in an otherwise fast-timeout program. A timeout of 0 disables the
+// request timeout, leaving only the context's deadline (if any) in effect:
+//
+//	ctx := hilink.WithCallTimeout(ctx, 90*time.Second)
+//	ok, err := cl.FirmwareUpdateCheck(ctx)
+func WithCallTimeout(ctx context.Context, timeout time.Duration) context.Context {
+	return context.WithValue(ctx, timeoutKey{}, timeout)
+}
+
 // CLientOption is a client option.
 type ClientOption func(*Client)
 
-// WithURL is a client option to set the URL endpoint.
+// WithURL is a client option to set the URL endpoint. The endpoint must be a
+// http or https URL (see NewClientE).
 func WithURL(endpoint string) ClientOption {
 	return func(cl *Client) {
+		switch u, err := url.Parse(endpoint); {
+		case err != nil:
+			cl.invalidOption(fmt.Errorf("%w: %v", ErrInvalidURL, err))
+		case u.Scheme != "http" && u.Scheme != "https":
+			cl.invalidOption(fmt.Errorf("%w: %q must have scheme http or https", ErrInvalidURL, endpoint))
+		case u.Host == "":
+			cl.invalidOption(fmt.Errorf("%w: %q must have a host", ErrInvalidURL, endpoint))
+		}
 		for strings.HasSuffix(endpoint, "/") {
 			endpoint = strings.TrimSuffix(endpoint, "/")
 		}
@@ -859,13 +3177,18 @@ func WithURL(endpoint string) ClientOption {
 }
 
 // WithAuth is a client option specifying the identifier and password to use.
-// The option is ignored if id is an empty string.
+// The option is ignored if id is an empty string, and is invalid if id is an
+// empty string and pw is not (see NewClientE).
 func WithAuth(id, pw string) ClientOption {
 	return func(cl *Client) {
+		if id == "" && pw != "" {
+			cl.invalidOption(fmt.Errorf("%w: password without identifier", ErrInvalidAuth))
+		}
 		if id != "" {
 			cl.authID = id
 			h := sha256.Sum256([]byte(pw))
 			cl.authPW = id + base64.StdEncoding.EncodeToString([]byte(hex.EncodeToString(h[:])))
+			cl.authB64 = base64.StdEncoding.EncodeToString([]byte(pw))
 		}
 	}
 }
@@ -878,30 +3201,215 @@ func WithNoStart(nostart bool) ClientOption {
 }
 
 // WithHTTPClient is a client option that sets the underlying http client.
+// The http client's cookie jar, if any, is used for the device session.
 func WithHTTPClient(client *http.Client) ClientOption {
 	return func(cl *Client) {
 		cl.cl = client
 	}
 }
 
-// WithTransport is a client option that sets the http transport used.
+// WithTransport is a client option that sets the http transport used. A TLS
+// configuration previously set with WithTLSConfig is applied to the
+// transport.
 func WithTransport(transport http.RoundTripper) ClientOption {
 	return func(cl *Client) {
+		if cl.tlsConfig != nil {
+			transport = tlsTransport(transport, cl.tlsConfig)
+		}
 		cl.cl.Transport = transport
 	}
 }
 
+// WithRateLimit is a client option that limits requests to r requests per
+// second, with bursts of up to burst requests, so that bursts of requests (ie,
+// polling several endpoints) do not trigger the device's system busy (100004)
+// protection. Requests over the limit wait, until the request context is
+// closed. A golang.org/x/time/rate.Limit can be passed as float64(r).
+func WithRateLimit(r float64, burst int) ClientOption {
+	return func(cl *Client) {
+		cl.limiter = nil
+		if r > 0 {
+			cl.limiter = newLimiter(r, burst)
+		}
+	}
+}
+
+// WithConfigCache is a client option that caches the static configuration
+// responses (GlobalConfig, NetworkTypes, PCAssistantConfig, DeviceConfig,
+// and WebUIConfig) for the ttl, as they do not change while the device is
+// running. Reduces the load on the device when polling frequently. See
+// ClearConfigCache.
+func WithConfigCache(ttl time.Duration) ClientOption {
+	return func(cl *Client) {
+		cl.cache = nil
+		if ttl > 0 {
+			cl.cache = newConfigCache(ttl)
+		}
+	}
+}
+
+// WithUserAgent is a client option that sets the User-Agent header sent with
+// requests, as some firmware responds differently depending on the user
+// agent (ie, to the WebUI of a mobile browser).
+func WithUserAgent(userAgent string) ClientOption {
+	return WithHeader("User-Agent", userAgent)
+}
+
+// WithHeader is a client option that adds a header sent with requests (ie,
+// authentication headers required by a reverse proxy in front of the
+// device). The Content-Type and CSRF token headers of POST requests are
+// always set by the client, and cannot be overridden.
+func WithHeader(key, value string) ClientOption {
+	return func(cl *Client) {
+		if cl.headers == nil {
+			cl.headers = make(http.Header)
+		}
+		if http.CanonicalHeaderKey(key) == "User-Agent" {
+			cl.headers.Set(key, value)
+			return
+		}
+		cl.headers.Add(key, value)
+	}
+}
+
+// WithRequestHook is a client option that adds a hook called with each
+// request before it is sent (ie, to inject headers). Hooks are called in the
+// order added, and may be called concurrently.
+func WithRequestHook(f func(*http.Request)) ClientOption {
+	return func(cl *Client) {
+		cl.requestHooks = append(cl.requestHooks, f)
+	}
+}
+
+// WithResponseHook is a client option that adds a hook called with each
+// response and its raw body, before the response is checked or decoded (ie,
+// to capture raw XML, or to collect metrics). Hooks are called in the order
+// added, may be called concurrently, and must not modify the body. For
+// streamed responses (ie, LogDownload), the body is nil.
+func WithResponseHook(f func(*http.Response, []byte)) ClientOption {
+	return func(cl *Client) {
+		cl.responseHooks = append(cl.responseHooks, f)
+	}
+}
+
+// WithTLSConfig is a client option that sets the TLS configuration used for
+// HTTPS endpoints. The configuration is applied to the default transport, a
+// *http.Transport, or the transport wrapped by WithLogf, WithSlog or a
+// fixture transport, and is also applied to transports later set with
+// WithTransport. Other transports are used as-is.
+func WithTLSConfig(tlsConfig *tls.Config) ClientOption {
+	return func(cl *Client) {
+		cl.tlsConfig = tlsConfig
+		cl.cl.Transport = tlsTransport(cl.cl.Transport, tlsConfig)
+	}
+}
+
+// WithInsecureSkipVerify is a client option that disables verification of
+// the device's TLS certificate, for HTTPS endpoints using self-signed
+// certificates.
+func WithInsecureSkipVerify() ClientOption {
+	return WithTLSConfig(&tls.Config{
+		InsecureSkipVerify: true,
+	})
+}
+
+// transportWrapper is the interface for transports wrapping another
+// transport (ie, the transports written by WithLogf and WithSlog).
+type transportWrapper interface {
+	http.RoundTripper
+	// wrapped returns the wrapped transport.
+	wrapped() http.RoundTripper
+	// wrap returns a copy of the transport, wrapping transport instead.
+	wrap(transport http.RoundTripper) http.RoundTripper
+}
+
+// tlsTransport returns a copy of the transport using the TLS configuration.
+// Wrapped transports are copied, and are not modified.
+func tlsTransport(transport http.RoundTripper, tlsConfig *tls.Config) http.RoundTripper {
+	switch t := transport.(type) {
+	case nil:
+		return tlsTransport(http.DefaultTransport, tlsConfig)
+	case *http.Transport:
+		t = t.Clone()
+		t.TLSClientConfig = tlsConfig
+		return t
+	case transportWrapper:
+		return t.wrap(tlsTransport(t.wrapped(), tlsConfig))
+	}
+	return transport
+}
+
 // WithLogf is a client option that writes all http request and response data
-// to the specified log func.
+// to the specified log func. Passwords, session cookies and tokens are
+// redacted, unless disabled with WithNoRedact.
 func WithLogf(logf func(string, ...interface{})) ClientOption {
 	return func(cl *Client) {
-		cl.cl.Transport = httplog.NewPrefixedRoundTripLogger(cl.cl.Transport, logf)
+		cl.cl.Transport = &logTransport{
+			transport: cl.cl.Transport,
+			logf:      logf,
+		}
+	}
+}
+
+// logTransport is the transport written by WithLogf, that redacts the logged
+// requests and responses unless disabled with WithNoRedact on the client
+// making the request (see logContext).
+type logTransport struct {
+	transport http.RoundTripper
+	logf      func(string, ...interface{})
+}
+
+// wrapped satisfies the transportWrapper interface.
+func (t *logTransport) wrapped() http.RoundTripper {
+	return t.transport
+}
+
+// wrap satisfies the transportWrapper interface.
+func (t *logTransport) wrap(transport http.RoundTripper) http.RoundTripper {
+	return &logTransport{
+		transport: transport,
+		logf:      t.logf,
+	}
+}
+
+// RoundTrip satisfies the http.RoundTripper interface.
+func (t *logTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	logf := t.logf
+	if noRedact, _ := req.Context().Value(noRedactKey{}).(bool); !noRedact {
+		logf = func(s string, v ...interface{}) {
+			t.logf("%s", redact(fmt.Sprintf(s, v...)))
+		}
+	}
+	return httplog.NewPrefixedRoundTripLogger(t.transport, logf).RoundTrip(req)
+}
+
+// noRedactKey is the context key for the WithNoRedact option of the client
+// making a request.
+type noRedactKey struct{}
+
+// logContext returns the context for a request, with the client's
+// WithNoRedact option, as the transport is shared by derived clients (see
+// With).
+func (cl *Client) logContext(ctx context.Context) context.Context {
+	return context.WithValue(ctx, noRedactKey{}, cl.noRedact)
+}
+
+// WithNoRedact is a client option to disable redaction of passwords, session
+// cookies and tokens in the data written by WithLogf.
+func WithNoRedact(noRedact bool) ClientOption {
+	return func(cl *Client) {
+		cl.noRedact = noRedact
 	}
 }
 
-// WithTimeout is a client option that sets the request timeout.
+// WithTimeout is a client option that sets the request timeout. A timeout of
+// 0 disables the request timeout, and a negative timeout is invalid (see
+// NewClientE).
 func WithTimeout(timeout time.Duration) ClientOption {
 	return func(cl *Client) {
+		if timeout < 0 {
+			cl.invalidOption(fmt.Errorf("%w: %v", ErrInvalidTimeout, timeout))
+		}
 		cl.cl.Timeout = timeout
 	}
 }
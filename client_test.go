@@ -0,0 +1,130 @@
+package hilink
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWithTLSConfigWrapped(t *testing.T) {
+	s := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		_, _ = w.Write([]byte(`<response><SesInfo>SessionID=id</SesInfo><TokInfo>token</TokInfo></response>`))
+	}))
+	defer s.Close()
+	logf := func(string, ...interface{}) {}
+	tests := []struct {
+		name string
+		opts []ClientOption
+	}{
+		{"before log", []ClientOption{WithInsecureSkipVerify(), WithLogf(logf)}},
+		{"after log", []ClientOption{WithLogf(logf), WithInsecureSkipVerify()}},
+		{"before transport", []ClientOption{WithInsecureSkipVerify(), WithTransport(new(http.Transport))}},
+		{"after transport", []ClientOption{WithTransport(new(http.Transport)), WithLogf(logf), WithInsecureSkipVerify()}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cl := NewClient(append([]ClientOption{WithURL(s.URL)}, test.opts...)...)
+			if _, _, err := cl.NewSessionAndTokenID(context.Background()); err != nil {
+				t.Fatalf("expected no error, got: %v", err)
+			}
+		})
+	}
+}
+
+func TestWithTLSConfigDerived(t *testing.T) {
+	s := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		_, _ = w.Write([]byte(`<response><SesInfo>SessionID=id</SesInfo><TokInfo>token</TokInfo></response>`))
+	}))
+	// silence the handshake error of the parent client
+	s.Config.ErrorLog = log.New(ioutil.Discard, "", 0)
+	s.StartTLS()
+	defer s.Close()
+	fixtures := NewFixtureTransport(t.TempDir(), FixtureRecord, nil)
+	cl := NewClient(WithURL(s.URL), WithTransport(fixtures))
+	derived := cl.With(WithInsecureSkipVerify())
+	if _, _, err := derived.NewSessionAndTokenID(context.Background()); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if fixtures.transport != http.DefaultTransport {
+		t.Errorf("expected parent fixture transport to be unmodified")
+	}
+	if _, _, err := cl.NewSessionAndTokenID(context.Background()); err == nil {
+		t.Errorf("expected certificate error for parent client")
+	}
+	if n := fixtures.counts["GET /api/webserver/SesTokInfo"]; n != 2 {
+		t.Errorf("expected 2 shared fixture requests, got: %d", n)
+	}
+}
+
+func TestDataPlanSet(t *testing.T) {
+	var req string
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "POST" {
+			buf, _ := ioutil.ReadAll(r.Body)
+			req = string(buf)
+			_, _ = w.Write([]byte(`<response>OK</response>`))
+			return
+		}
+		_, _ = w.Write([]byte(`<response><StartDay>1</StartDay><DataLimit>500MB</DataLimit><DataLimitAwoke>1</DataLimitAwoke><MonthThreshold>90</MonthThreshold><SetMonthData>1</SetMonthData></response>`))
+	}))
+	defer s.Close()
+	cl := NewClient(WithURL(s.URL))
+	ok, err := cl.DataPlanSet(context.Background(), DataPlan{Enabled: true, StartDay: 15, DataLimit: 2 << 30, Threshold: 80})
+	if err != nil || !ok {
+		t.Fatalf("expected success, got: %t %v", ok, err)
+	}
+	exp := "<StartDay>15</StartDay>\n  <DataLimit>2GB</DataLimit>\n  <DataLimitAwoke>1</DataLimitAwoke>\n  <MonthThreshold>80</MonthThreshold>\n  <SetMonthData>1</SetMonthData>"
+	if !strings.Contains(req, exp) {
+		t.Errorf("expected request to contain:\n%s\ngot:\n%s", exp, req)
+	}
+}
+
+func TestLogDownload(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/api/device/compresslogfile":
+			_, _ = w.Write([]byte(`<response><LogPath>/log/device.tar.gz</LogPath></response>`))
+		case "/log/device.tar.gz":
+			if req.Header.Get("X-Test") != "1" {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			// slower than the request timeout
+			_, _ = w.Write([]byte("first"))
+			w.(http.Flusher).Flush()
+			time.Sleep(200 * time.Millisecond)
+			_, _ = w.Write([]byte("second"))
+		default:
+			_, _ = w.Write([]byte(`<response><SesInfo>SessionID=id</SesInfo><TokInfo>token</TokInfo></response>`))
+		}
+	}))
+	defer s.Close()
+	var paths []string
+	cl := NewClient(
+		WithURL(s.URL),
+		WithTimeout(100*time.Millisecond),
+		WithRequestHook(func(req *http.Request) {
+			req.Header.Set("X-Test", "1")
+		}),
+		WithResponseHook(func(res *http.Response, _ []byte) {
+			paths = append(paths, res.Request.URL.Path)
+		}),
+	)
+	var buf bytes.Buffer
+	n, err := cl.LogDownload(context.Background(), &buf)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if n != 11 || buf.String() != "firstsecond" {
+		t.Errorf("expected firstsecond, got: %d %q", n, buf.String())
+	}
+	if exp := "/api/device/compresslogfile /log/device.tar.gz"; !strings.HasSuffix(strings.Join(paths, " "), exp) {
+		t.Errorf("expected responses ending with %s, got: %v", exp, paths)
+	}
+}
@@ -0,0 +1,222 @@
+// Command hilink_exporter is a Prometheus exporter for Huawei Hilink devices.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kenshaw/hilink"
+)
+
+func main() {
+	endpoint := flag.String("endpoint", "http://192.168.8.1/", "api endpoint(s), comma separated")
+	username := flag.String("username", "", "device username")
+	password := flag.String("password", "", "device password")
+	debug := flag.Bool("v", false, "enable verbose")
+	listen := flag.String("l", ":9770", "listen address")
+	interval := flag.Duration("interval", 15*time.Second, "poll interval")
+	flag.Parse()
+	if err := run(context.Background(), *endpoint, *username, *password, *debug, *listen, *interval); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, endpoint, username, password string, debug bool, listen string, interval time.Duration) error {
+	var devices []*device
+	for _, z := range strings.Split(endpoint, ",") {
+		if z = strings.TrimSpace(z); z == "" {
+			continue
+		}
+		u, err := url.Parse(z)
+		if err != nil {
+			return err
+		}
+		// options
+		opts := []hilink.ClientOption{
+			hilink.WithURL(z),
+			hilink.WithAuth(username, password),
+			// start the session and log in on the first request
+			hilink.WithNoStart(true),
+		}
+		if debug {
+			opts = append(opts, hilink.WithLogf(log.Printf))
+		}
+		devices = append(devices, &device{
+			name: u.Host,
+			cl:   hilink.NewClient(opts...),
+		})
+	}
+	if len(devices) == 0 {
+		return fmt.Errorf("must specify endpoint")
+	}
+	// poll
+	for _, d := range devices {
+		go d.run(ctx, interval)
+	}
+	http.HandleFunc("/metrics", func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		writeMetrics(w, devices)
+	})
+	log.Printf("listening on %s", listen)
+	return http.ListenAndServe(listen, nil)
+}
+
+// sources are the polled endpoints, keyed by metric subsystem.
+var sources = []struct {
+	name string
+	f    func(*hilink.Client, context.Context) (hilink.XMLData, error)
+}{
+	{"signal", (*hilink.Client).SignalInfo},
+	{"status", (*hilink.Client).StatusInfo},
+	{"traffic", (*hilink.Client).TrafficInfo},
+	{"month", (*hilink.Client).MonthInfo},
+	{"sms", (*hilink.Client).SmsCount},
+}
+
+// device is a polled device.
+type device struct {
+	name string
+	cl   *hilink.Client
+
+	sync.Mutex
+	up      bool
+	errors  int
+	metrics map[string]float64
+}
+
+// run polls the device until the context is closed.
+func (d *device) run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	loggedIn := false
+	for {
+		if !loggedIn {
+			loggedIn = d.cl.Relogin(ctx) == nil
+		}
+		metrics, err := d.poll(ctx)
+		if err != nil {
+			log.Printf("%s: %v", d.name, err)
+			// force re-login on the next poll
+			loggedIn = false
+		}
+		d.Lock()
+		d.up = err == nil
+		if err != nil {
+			d.errors++
+		} else {
+			d.metrics = metrics
+		}
+		d.Unlock()
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// poll retrieves the numeric values from the polled endpoints.
+func (d *device) poll(ctx context.Context) (map[string]float64, error) {
+	metrics := make(map[string]float64)
+	for _, src := range sources {
+		res, err := src.f(d.cl, ctx)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", src.name, err)
+		}
+		for k, v := range res {
+			s, ok := v.(string)
+			if !ok {
+				continue
+			}
+			if f, ok := parseValue(s); ok {
+				metrics["hilink_"+src.name+"_"+snakeCase(k)] = f
+			}
+		}
+	}
+	return metrics, nil
+}
+
+// unitRE matches numeric values with an optional unit suffix (ie, -95dBm).
+var unitRE = regexp.MustCompile(`^\s*[<>=]*\s*(-?[0-9]+(?:\.[0-9]+)?)\s*[a-zA-Z%]*\s*$`)
+
+// parseValue parses a numeric value, stripping any unit suffix.
+func parseValue(s string) (float64, bool) {
+	m := unitRE.FindStringSubmatch(s)
+	if m == nil {
+		return 0, false
+	}
+	f, err := strconv.ParseFloat(m[1], 64)
+	return f, err == nil
+}
+
+// snakeCase converts a CamelCase name to snake_case (ie, CurrentDownloadRate
+// to current_download_rate, and SINR to sinr).
+func snakeCase(s string) string {
+	isUpper := func(c rune) bool { return 'A' <= c && c <= 'Z' }
+	isLower := func(c rune) bool { return 'a' <= c && c <= 'z' || '0' <= c && c <= '9' }
+	var b strings.Builder
+	r := []rune(s)
+	for i, c := range r {
+		switch {
+		case isUpper(c):
+			if i != 0 && (isLower(r[i-1]) || isUpper(r[i-1]) && i+1 < len(r) && isLower(r[i+1])) {
+				b.WriteRune('_')
+			}
+			b.WriteRune(c + 'a' - 'A')
+		case isLower(c):
+			b.WriteRune(c)
+		default:
+			b.WriteRune('_')
+		}
+	}
+	return b.String()
+}
+
+// writeMetrics writes the device metrics in the Prometheus text format.
+func writeMetrics(w io.Writer, devices []*device) {
+	values := make(map[string][]string)
+	add := func(name, dev string, v float64) {
+		values[name] = append(values[name], fmt.Sprintf("%s{device=%q} %s", name, dev, strconv.FormatFloat(v, 'g', -1, 64)))
+	}
+	for _, d := range devices {
+		d.Lock()
+		up := 0.0
+		if d.up {
+			up = 1
+		}
+		add("hilink_up", d.name, up)
+		add("hilink_poll_errors_total", d.name, float64(d.errors))
+		for k, v := range d.metrics {
+			add(k, d.name, v)
+		}
+		d.Unlock()
+	}
+	var names []string
+	for k := range values {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		typ := "gauge"
+		if strings.HasSuffix(name, "_total") {
+			typ = "counter"
+		}
+		fmt.Fprintf(w, "# TYPE %s %s\n", name, typ)
+		for _, line := range values[name] {
+			fmt.Fprintln(w, line)
+		}
+	}
+}
@@ -14,14 +14,23 @@ import (
 func main() {
 	endpoint := flag.String("endpoint", "http://192.168.8.1/", "api endpoint")
 	debug := flag.Bool("v", false, "enable verbose")
+	all := flag.Bool("all", false, "also retrieve status, signal, traffic and network information")
+	discover := flag.Bool("discover", false, "discover devices on the local network")
+	scan := flag.String("scan", "", "with -discover, also scan the network (ie, 192.168.8.0/24)")
 	flag.Parse()
-	if err := run(context.Background(), *endpoint, *debug); err != nil {
+	var err error
+	if *discover {
+		err = runDiscover(context.Background(), *scan)
+	} else {
+		err = run(context.Background(), *endpoint, *debug, *all)
+	}
+	if err != nil {
 		fmt.Fprintf(os.Stderr, "error: %v\n", err)
 		os.Exit(1)
 	}
 }
 
-func run(ctx context.Context, endpoint string, debug bool) error {
+func run(ctx context.Context, endpoint string, debug, all bool) error {
 	// options
 	opts := []hilink.ClientOption{
 		hilink.WithURL(endpoint),
@@ -41,7 +50,12 @@ func run(ctx context.Context, endpoint string, debug bool) error {
 		return err
 	}
 	// get device info
-	d, err := cl.DeviceInfo(ctx)
+	var d interface{}
+	if all {
+		d, err = cl.InfoAll(ctx)
+	} else {
+		d, err = cl.DeviceInfo(ctx)
+	}
 	if err != nil {
 		return err
 	}
@@ -53,3 +67,23 @@ func run(ctx context.Context, endpoint string, debug bool) error {
 	_, err = os.Stdout.Write(append(buf, '\n'))
 	return err
 }
+
+func runDiscover(ctx context.Context, scan string) error {
+	var opts []hilink.DiscoverOption
+	if scan != "" {
+		opts = append(opts, hilink.WithScan(scan))
+	}
+	devices, err := hilink.Discover(ctx, opts...)
+	if err != nil {
+		return err
+	}
+	if devices == nil {
+		devices = []hilink.DiscoveredDevice{}
+	}
+	buf, err := json.MarshalIndent(devices, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = os.Stdout.Write(append(buf, '\n'))
+	return err
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config is the hlcli config file.
+type Config struct {
+	// Profile holds the default settings.
+	Profile `yaml:",inline"`
+	// Profiles are the per-device profiles, selectable with -profile.
+	Profiles map[string]Profile `yaml:"profiles"`
+}
+
+// Profile holds the settings for a device.
+type Profile struct {
+	Endpoint string        `yaml:"endpoint"`
+	Username string        `yaml:"username"`
+	Password string        `yaml:"password"`
+	Timeout  time.Duration `yaml:"timeout"`
+	Output   string        `yaml:"output"`
+	// Insecure disables verification of the device's TLS certificate, for
+	// https endpoints with self-signed certificates.
+	Insecure bool `yaml:"insecure"`
+	// UserAgent and Headers are sent with requests, for firmware that
+	// responds differently by user agent, or for devices behind a reverse
+	// proxy.
+	UserAgent string            `yaml:"user_agent"`
+	Headers   map[string]string `yaml:"headers"`
+	// Record and Replay are the fixture directories, and are only set by
+	// the -record and -replay flags.
+	Record string `yaml:"-"`
+	Replay string `yaml:"-"`
+}
+
+// merge merges the non-empty values of o into p.
+func (p *Profile) merge(o Profile) {
+	if o.Endpoint != "" {
+		p.Endpoint = o.Endpoint
+	}
+	if o.Username != "" {
+		p.Username = o.Username
+	}
+	if o.Password != "" {
+		p.Password = o.Password
+	}
+	if o.Timeout != 0 {
+		p.Timeout = o.Timeout
+	}
+	if o.Output != "" {
+		p.Output = o.Output
+	}
+	if o.Insecure {
+		p.Insecure = true
+	}
+	if o.UserAgent != "" {
+		p.UserAgent = o.UserAgent
+	}
+	if len(o.Headers) != 0 {
+		headers := make(map[string]string, len(p.Headers)+len(o.Headers))
+		for k, v := range p.Headers {
+			headers[k] = v
+		}
+		for k, v := range o.Headers {
+			headers[k] = v
+		}
+		p.Headers = headers
+	}
+}
+
+// configFile returns the default config file path
+// ($XDG_CONFIG_HOME/hlcli/config.yaml, or ~/.config/hlcli/config.yaml).
+func configFile() string {
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		dir = filepath.Join(home, ".config")
+	}
+	return filepath.Join(dir, "hlcli", "config.yaml")
+}
+
+// loadProfile loads the config file, returning the default settings merged
+// with the named profile. A missing config file is only an error when the
+// path or a profile was explicitly specified.
+func loadProfile(path, name string) (Profile, error) {
+	explicit := path != ""
+	if !explicit {
+		path = configFile()
+	}
+	var cfg Config
+	buf, err := ioutil.ReadFile(path)
+	switch {
+	case err != nil && os.IsNotExist(err) && !explicit && name == "":
+		return Profile{}, nil
+	case err != nil:
+		return Profile{}, err
+	}
+	if err := yaml.Unmarshal(buf, &cfg); err != nil {
+		return Profile{}, fmt.Errorf("unable to read %s: %v", path, err)
+	}
+	p := cfg.Profile
+	if name != "" {
+		o, ok := cfg.Profiles[name]
+		if !ok {
+			return Profile{}, fmt.Errorf("unknown profile %q in %s", name, path)
+		}
+		p.merge(o)
+	}
+	return p, nil
+}
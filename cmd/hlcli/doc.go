@@ -3,177 +3,437 @@ package main
 // Code generated by gen.go. DO NOT EDIT.
 
 var methodParamMap = map[string][]string{
-	"NewSessionAndTokenID": {},
-	"SetSessionAndTokenID": {"sessionID", "tokenID"},
-	"GlobalConfig":         {},
-	"NetworkTypes":         {},
-	"PCAssistantConfig":    {},
-	"DeviceConfig":         {},
-	"WebUIConfig":          {},
-	"SmsConfig":            {},
-	"WlanConfig":           {},
-	"DhcpConfig":           {},
-	"CradleStatusInfo":     {},
-	"CradleMACSet":         {"addr"},
-	"CradleMAC":            {},
-	"AutorunVersion":       {},
-	"DeviceBasicInfo":      {},
-	"PublicKey":            {},
-	"DeviceControl":        {"code"},
-	"DeviceReboot":         {},
-	"DeviceReset":          {},
-	"DeviceBackup":         {},
-	"DeviceShutdown":       {},
-	"DeviceFeatures":       {},
-	"DeviceInfo":           {},
-	"DeviceModeSet":        {"mode"},
-	"FastbootFeatures":     {},
-	"PowerFeatures":        {},
-	"TetheringFeatures":    {},
-	"SignalInfo":           {},
-	"ConnectionInfo":       {},
-	"GlobalFeatures":       {},
-	"Language":             {},
-	"LanguageSet":          {"lang"},
-	"NotificationInfo":     {},
-	"SimInfo":              {},
-	"StatusInfo":           {},
-	"TrafficInfo":          {},
-	"TrafficClear":         {},
-	"MonthInfo":            {},
-	"WlanMonthInfo":        {},
-	"NetworkInfo":          {},
-	"WifiFeatures":         {},
-	"ModeList":             {},
-	"ModeInfo":             {},
-	"ModeNetworkInfo":      {},
-	"ModeSet":              {"netMode", "netBand", "lteBand"},
-	"PinInfo":              {},
-	"PinEnter":             {"pin"},
-	"PinActivate":          {"pin"},
-	"PinDeactivate":        {"pin"},
-	"PinChange":            {"pin", "new"},
-	"PinEnterPuk":          {"puk", "new"},
-	"PinSaveInfo":          {},
-	"PinSimlockInfo":       {},
-	"Connect":              {},
-	"Disconnect":           {},
-	"ProfileInfo":          {},
-	"SmsFeatures":          {},
-	"SmsList":              {"boxType", "page", "count", "sortByName", "ascending", "unreadPreferred"},
-	"SmsCount":             {},
-	"SmsSend":              {"msg", "to"},
-	"SmsSendStatus":        {},
-	"SmsReadSet":           {"id"},
-	"SmsDelete":            {"id"},
-	"UssdStatus":           {},
-	"UssdCode":             {"code"},
-	"UssdContent":          {},
-	"UssdRelease":          {},
-	"DdnsList":             {},
-	"LogPath":              {},
-	"LogInfo":              {},
-	"PhonebookGroupList":   {"page", "count", "sortByName", "ascending"},
-	"PhonebookCount":       {},
-	"PhonebookImport":      {"group"},
-	"PhonebookDelete":      {"id"},
-	"PhonebookList":        {"group", "page", "count", "sim", "sortByName", "ascending", "keyword"},
-	"PhonebookCreate":      {"group", "name", "phone", "sim"},
-	"FirewallFeatures":     {},
-	"DmzConfig":            {},
-	"DmzConfigSet":         {"enabled", "dmzIPAddress"},
-	"SipAlg":               {},
-	"SipAlgSet":            {"port", "enabled"},
-	"NatType":              {},
-	"NatTypeSet":           {"ntype"},
-	"Upnp":                 {},
-	"UpnpSet":              {"enabled"},
+	"ClearConfigCache":        {},
+	"Capabilities":            {},
+	"With":                    {"opts"},
+	"Relogin":                 {},
+	"DoInto":                  {"path", "data", "v"},
+	"DoRaw":                   {"path", "body"},
+	"NewSessionAndTokenID":    {},
+	"SetSessionAndTokenID":    {"sessionID", "tokenID"},
+	"GlobalConfig":            {},
+	"NetworkTypes":            {},
+	"PCAssistantConfig":       {},
+	"DeviceConfig":            {},
+	"WebUIConfig":             {},
+	"SmsConfig":               {},
+	"WlanConfig":              {},
+	"DhcpConfig":              {},
+	"LanSettings":             {},
+	"LanSettingsSet":          {"settings"},
+	"StaticLeases":            {},
+	"StaticLeaseAdd":          {"mac", "ip"},
+	"StaticLeaseDelete":       {"mac"},
+	"IPv6Settings":            {},
+	"IPv6SettingsSet":         {"settings"},
+	"Routes":                  {},
+	"RouteAdd":                {"route"},
+	"RouteDelete":             {"destination", "mask"},
+	"CradleStatusInfo":        {},
+	"CradleMACSet":            {"addr"},
+	"CradleMAC":               {},
+	"AutorunVersion":          {},
+	"DeviceBasicInfo":         {},
+	"PublicKey":               {},
+	"DeviceControl":           {"code"},
+	"DeviceReboot":            {},
+	"RebootAndWait":           {},
+	"WaitUntilReady":          {},
+	"DeviceReset":             {},
+	"DeviceBackup":            {},
+	"DeviceShutdown":          {},
+	"DeviceFeatures":          {},
+	"DeviceInfo":              {},
+	"DeviceModeSet":           {"mode"},
+	"FastbootFeatures":        {},
+	"FastbootFeaturesSet":     {"enabled"},
+	"PowerFeatures":           {},
+	"PowerFeaturesSet":        {"enabled"},
+	"TetheringFeatures":       {},
+	"TetheringFeaturesSet":    {"enabled"},
+	"SignalInfo":              {},
+	"ServingCell":             {},
+	"NeighborCells":           {},
+	"ConnectionInfo":          {},
+	"GlobalFeatures":          {},
+	"Language":                {},
+	"LanguageSet":             {"lang"},
+	"NotificationInfo":        {},
+	"ClearNotifications":      {},
+	"SimInfo":                 {},
+	"StatusInfo":              {},
+	"Status":                  {},
+	"TrafficInfo":             {},
+	"Traffic":                 {},
+	"TrafficClear":            {},
+	"MonthInfo":               {},
+	"MonthTraffic":            {},
+	"DataPlan":                {},
+	"DataPlanSet":             {"plan"},
+	"WlanMonthInfo":           {},
+	"NetworkInfo":             {},
+	"WifiFeatures":            {},
+	"WifiSwitchInfo":          {},
+	"WifiSwitch":              {"enabled"},
+	"WifiSwitch24GHz":         {"enabled"},
+	"WifiSwitch5GHz":          {"enabled"},
+	"WifiHostList":            {},
+	"WifiHosts":               {},
+	"LanHostInfo":             {},
+	"LanHosts":                {},
+	"AllHosts":                {},
+	"WakeOnLan":               {"mac"},
+	"BandwidthLimits":         {},
+	"BandwidthLimitSet":       {"mac", "upload", "download"},
+	"BandwidthLimitDelete":    {"mac"},
+	"WifiMacFilter":           {},
+	"WifiHostBlock":           {"mac"},
+	"WifiHostUnblock":         {"mac"},
+	"WifiTimeSwitch":          {},
+	"WifiTimeSwitchSet":       {"sched"},
+	"ModeList":                {},
+	"ModeInfo":                {},
+	"ModeNetworkInfo":         {},
+	"ModeSet":                 {"netMode", "netBand", "lteBand"},
+	"NetworkModeSet":          {"mode"},
+	"LTEBandLock":             {"bands"},
+	"NRBandLock":              {"bands"},
+	"NRMode":                  {},
+	"NRModeSet":               {"mode"},
+	"NetworkScan":             {},
+	"NetworkRegister":         {"plmn", "rat"},
+	"PinInfo":                 {},
+	"PinEnter":                {"pin"},
+	"PinActivate":             {"pin"},
+	"PinDeactivate":           {"pin"},
+	"PinChange":               {"pin", "new"},
+	"PinEnterPuk":             {"puk", "new"},
+	"PinSaveInfo":             {},
+	"PinSimlockInfo":          {},
+	"Connect":                 {},
+	"Disconnect":              {},
+	"MobileDataEnabled":       {},
+	"MobileDataSet":           {"enabled"},
+	"ConnectionSettings":      {},
+	"ConnectionSettingsSet":   {"s"},
+	"ProfileInfo":             {},
+	"Profiles":                {},
+	"ProfileCreate":           {"p"},
+	"ProfileUpdate":           {"p"},
+	"ProfileDelete":           {"index"},
+	"ProfileSetDefault":       {"index"},
+	"SmsFeatures":             {},
+	"SmsList":                 {"boxType", "page", "count", "sortByName", "ascending", "unreadPreferred"},
+	"SmsMessages":             {"boxType", "page", "count"},
+	"SmsListAll":              {"boxType"},
+	"SmsCount":                {},
+	"SmsCounts":               {},
+	"SmsSend":                 {"msg", "to"},
+	"SmsSendOpts":             {"opts", "msg", "to"},
+	"SmsDeliveryReportSet":    {"enabled"},
+	"SmsDeliveryReports":      {"count"},
+	"SmsSendPdu":              {"pdu", "length"},
+	"SmsSendSubmit":           {"s"},
+	"SmsPduList":              {"boxType", "page", "count"},
+	"SmsPduMessages":          {"boxType", "page", "count"},
+	"SmsSendStatus":           {},
+	"SmsReadSet":              {"id"},
+	"SmsMarkAllRead":          {"boxType"},
+	"SmsDelete":               {"id"},
+	"UssdStatus":              {},
+	"UssdCode":                {"code"},
+	"UssdCodeOpts":            {"code", "codeType", "timeout"},
+	"UssdReply":               {"text"},
+	"UssdContent":             {},
+	"UssdContentOpts":         {"codeType"},
+	"UssdRelease":             {},
+	"DdnsList":                {},
+	"LogPath":                 {},
+	"LogInfo":                 {},
+	"LogSettingSet":           {"level", "enabled"},
+	"LogDownload":             {"w"},
+	"FirmwareUpdateCheck":     {},
+	"FirmwareNewVersion":      {},
+	"FirmwareUpdateStatus":    {},
+	"FirmwareAutoUpdate":      {},
+	"FirmwareAutoUpdateSet":   {"enabled"},
+	"PhonebookGroupList":      {"page", "count", "sortByName", "ascending"},
+	"PhonebookCount":          {},
+	"PhonebookImport":         {"group"},
+	"PhonebookDelete":         {"id"},
+	"PhonebookList":           {"group", "page", "count", "sim", "sortByName", "ascending", "keyword"},
+	"PhonebookEntries":        {"group", "page", "count", "sim"},
+	"PhonebookCreate":         {"group", "name", "phone", "sim"},
+	"FirewallFeatures":        {},
+	"FirewallIPFilters":       {},
+	"FirewallIPFilterAdd":     {"filter"},
+	"FirewallIPFilterRemove":  {"index"},
+	"FirewallMacFilters":      {},
+	"FirewallMacFilterAdd":    {"mac"},
+	"FirewallMacFilterRemove": {"mac"},
+	"FirewallURLFilters":      {},
+	"FirewallURLFilterAdd":    {"url"},
+	"FirewallURLFilterRemove": {"url"},
+	"TimeRules":               {},
+	"TimeRuleAdd":             {"rule"},
+	"TimeRuleDelete":          {"name"},
+	"TimeRuleEnable":          {"enabled", "name"},
+	"RemoteAccess":            {},
+	"RemoteAccessSet":         {"access"},
+	"DmzConfig":               {},
+	"DmzConfigSet":            {"enabled", "dmzIPAddress"},
+	"SipAlg":                  {},
+	"SipAlgSet":               {"port", "enabled"},
+	"NatType":                 {},
+	"NatTypeSet":              {"ntype"},
+	"Upnp":                    {},
+	"UpnpSet":                 {"enabled"},
+	"VirtualServers":          {},
+	"VirtualServerAdd":        {"server"},
+	"VirtualServerDelete":     {"index"},
+	"WlanBasicSettings":       {},
+	"WlanBasicSettingsSet":    {"ssid", "hidden"},
+	"AutoApn":                 {},
+	"AutoApnSet":              {"enabled"},
+	"NetworkReconnect":        {},
+	"SmsExport":               {"w", "boxType", "format"},
+	"FirmwareUpgradeStart":    {},
+	"FirmwareUpgradeCancel":   {},
+	"FirmwareUpgradeWait":     {"interval", "progress"},
+	"InfoAll":                 {},
+	"SmsAll":                  {"boxType"},
+	"PhonebookAll":            {"group", "sim"},
+	"WifiHostsAll":            {},
+	"DeviceProfile":           {},
+	"SMS":                     {},
+	"Net":                     {},
+	"Wlan":                    {},
+	"Device":                  {},
+	"Security":                {},
+	"SampleThroughput":        {"window"},
+	"UssdSendAndWait":         {"code"},
+	"UssdSendAndWaitOpts":     {"code", "codeType"},
+	"UssdStart":               {"code"},
+	"UssdStartOpts":           {"code", "codeType"},
+	"UssdRun":                 {"script"},
+	"WatchSms":                {"interval"},
+	"WatchSmsFunc":            {"interval", "f", "errf"},
 }
 
 var methodCommentMap = map[string]string{
-	"NewSessionAndTokenID": "NewSessionAndTokenID starts a session with the server, and returns the session and token.",
-	"SetSessionAndTokenID": "SetSessionAndTokenID sets the sessionID and tokenID for the Client.",
-	"GlobalConfig":         "GlobalConfig retrieves global Hilink configuration.",
-	"NetworkTypes":         "NetworkTypes retrieves available network types.",
-	"PCAssistantConfig":    "PCAssistantConfig retrieves PC Assistant configuration.",
-	"DeviceConfig":         "DeviceConfig retrieves device configuration.",
-	"WebUIConfig":          "WebUIConfig retrieves WebUI configuration.",
-	"SmsConfig":            "SmsConfig retrieves device SMS configuration.",
-	"WlanConfig":           "WlanConfig retrieves basic WLAN settings.",
-	"DhcpConfig":           "DhcpConfig retrieves DHCP configuration.",
-	"CradleStatusInfo":     "CradleStatusInfo retrieves cradle status information.",
-	"CradleMACSet":         "CradleMACSet sets the MAC address for the cradle.",
-	"CradleMAC":            "CradleMAC retrieves cradle MAC address.",
-	"AutorunVersion":       "AutorunVersion retrieves device autorun version.",
-	"DeviceBasicInfo":      "DeviceBasicInfo retrieves basic device information.",
-	"PublicKey":            "PublicKey retrieves webserver public key.",
-	"DeviceControl":        "DeviceControl sends a control code to the device.",
-	"DeviceReboot":         "DeviceReboot restarts the device.",
-	"DeviceReset":          "DeviceReset resets the device configuration.",
-	"DeviceBackup":         "DeviceBackup backups device configuration and retrieves backed up configuration data as a base64 encoded string.",
-	"DeviceShutdown":       "DeviceShutdown shuts down the device.",
-	"DeviceFeatures":       "DeviceFeatures retrieves device feature information.",
-	"DeviceInfo":           "DeviceInfo retrieves general device information.",
-	"DeviceModeSet":        "DeviceModeSet sets the device mode (0-project, 1-debug).",
-	"FastbootFeatures":     "FastbootFeatures retrieves fastboot feature information.",
-	"PowerFeatures":        "PowerFeatures retrieves power feature information.",
-	"TetheringFeatures":    "TetheringFeatures retrieves USB tethering feature information.",
-	"SignalInfo":           "SignalInfo retrieves network signal information.",
-	"ConnectionInfo":       "ConnectionInfo retrieves connection (dialup) information.",
-	"GlobalFeatures":       "GlobalFeatures retrieves global feature information.",
-	"Language":             "Language retrieves current language.",
-	"LanguageSet":          "LanguageSet sets the language.",
-	"NotificationInfo":     "NotificationInfo retrieves notification information.",
-	"SimInfo":              "SimInfo retrieves SIM card information.",
-	"StatusInfo":           "StatusInfo retrieves general device status information.",
-	"TrafficInfo":          "TrafficInfo retrieves traffic statistic information.",
-	"TrafficClear":         "TrafficClear clears the current traffic statistics.",
-	"MonthInfo":            "MonthInfo retrieves the month download statistic information.",
-	"WlanMonthInfo":        "WlanMonthInfo retrieves the WLAN month download statistic information.",
-	"NetworkInfo":          "NetworkInfo retrieves network provider information.",
-	"WifiFeatures":         "WifiFeatures retrieves wifi feature information.",
-	"ModeList":             "ModeList retrieves available network modes.",
-	"ModeInfo":             "ModeInfo retrieves network mode settings information.",
-	"ModeNetworkInfo":      "ModeNetworkInfo retrieves current network mode information.",
-	"ModeSet":              "ModeSet sets the network mode.",
-	"PinInfo":              "PinInfo retrieves SIM PIN status information.",
-	"PinEnter":             "PinEnter enters a SIM PIN.",
-	"PinActivate":          "PinActivate activates a SIM PIN.",
-	"PinDeactivate":        "PinDeactivate deactivates a SIM PIN.",
-	"PinChange":            "PinChange changes a SIM PIN.",
-	"PinEnterPuk":          "PinEnterPuk enters a SIM PIN puk.",
-	"PinSaveInfo":          "PinSaveInfo retrieves SIM PIN save information.",
-	"PinSimlockInfo":       "PinSimlockInfo retrieves SIM lock information.",
-	"Connect":              "Connect connects the Hilink device to the network provider.",
-	"Disconnect":           "Disconnect disconnects the Hilink device from the network provider.",
-	"ProfileInfo":          "ProfileInfo retrieves profile information (ie, APN).",
-	"SmsFeatures":          "SmsFeatures retrieves SMS feature information.",
-	"SmsList":              "SmsList retrieves list of SMS in an inbox.",
-	"SmsCount":             "SmsCount retrieves count of SMS per inbox type.",
-	"SmsSend":              "SmsSend sends an SMS.",
-	"SmsSendStatus":        "SmsSendStatus retrieves SMS send status information.",
-	"SmsReadSet":           "SmsReadSet sets the read status of a SMS.",
-	"SmsDelete":            "SmsDelete deletes a specified SMS.",
-	"UssdStatus":           "UssdStatus retrieves current USSD session status information.",
-	"UssdCode":             "UssdCode sends a USSD code to the Hilink device.",
-	"UssdContent":          "UssdContent retrieves content buffer of the active USSD session.",
-	"UssdRelease":          "UssdRelease releases the active USSD session.",
-	"DdnsList":             "DdnsList retrieves list of DDNS providers.",
-	"LogPath":              "LogPath retrieves device log path (URL).",
-	"LogInfo":              "LogInfo retrieves current log setting information.",
-	"PhonebookGroupList":   "PhonebookGroupList retrieves list of the phonebook groups.",
-	"PhonebookCount":       "PhonebookCount retrieves count of phonebook entries per group.",
-	"PhonebookImport":      "PhonebookImport imports SIM contacts into specified phonebook group.",
-	"PhonebookDelete":      "PhonebookDelete deletes a specified phonebook entry.",
-	"PhonebookList":        "PhonebookList retrieves list of phonebook entries from a specified group.",
-	"PhonebookCreate":      "PhonebookCreate creates a new phonebook entry.",
-	"FirewallFeatures":     "FirewallFeatures retrieves firewall security feature information.",
-	"DmzConfig":            "DmzConfig retrieves DMZ status and IP address of DMZ host.",
-	"DmzConfigSet":         "DmzConfigSet enables or disables the DMZ and the DMZ IP address of the device.",
-	"SipAlg":               "SipAlg retrieves status and port of the SIP application-level gateway.",
-	"SipAlgSet":            "SipAlgSet enables/disables SIP application-level gateway and sets SIP port.",
-	"NatType":              "NatType retrieves NAT type.",
-	"NatTypeSet":           "NatTypeSet sets NAT type (values: 0, 1).",
-	"Upnp":                 "Upnp retrieves the status of UPNP.",
-	"UpnpSet":              "UpnpSet enables/disables UPNP.",
+	"ClearConfigCache":        "ClearConfigCache clears the responses cached by the config cache (ie, after a firmware update or device reset).",
+	"Capabilities":            "Capabilities detects the capabilities of the device. Feature information not available on the device (ie, when an endpoint is not supported by the firmware) is treated as the feature not being present.",
+	"With":                    "With returns a new client derived from the client, with the options applied, for calls needing different settings (ie, a longer timeout, or a different logger) in an otherwise differently configured program:  	res, err := cl.With(hilink.WithTimeout(hilink.NetworkScanTimeout)).NetworkScan(ctx)  The derived client shares the session (ie, the session cookie, CSRF token, and rate limit) with the client, and a copy of the client's http client, to which the options are applied. As such, the derived client should only be used with the same URL endpoint, and transport options (ie, WithLogf) wrap the client's transport. Invalid options are ignored (see NewClientE).",
+	"Relogin":                 "Relogin starts a new session, logging in again when the Auth option was given (ie, after the device has expired the session or rebooted).",
+	"DoInto":                  "DoInto sends a request to the server with the provided path, decoding the response into v with UnmarshalXML. If data is nil, then GET will be used as the HTTP method, otherwise POST will be used.",
+	"DoRaw":                   "DoRaw sends a request to the server with the provided path, returning the undecoded response body. If body is nil, then GET will be used as the HTTP method, otherwise POST will be used.",
+	"NewSessionAndTokenID":    "NewSessionAndTokenID starts a session with the server, and returns the session and token.",
+	"SetSessionAndTokenID":    "SetSessionAndTokenID sets the sessionID and tokenID for the Client. The session cookie is set in the http client's cookie jar (creating one if the http client has none), replacing any previous session cookie, while other cookies set by the device are preserved.",
+	"GlobalConfig":            "GlobalConfig retrieves global Hilink configuration.",
+	"NetworkTypes":            "NetworkTypes retrieves available network types.",
+	"PCAssistantConfig":       "PCAssistantConfig retrieves PC Assistant configuration.",
+	"DeviceConfig":            "DeviceConfig retrieves device configuration.",
+	"WebUIConfig":             "WebUIConfig retrieves WebUI configuration.",
+	"SmsConfig":               "SmsConfig retrieves device SMS configuration.",
+	"WlanConfig":              "WlanConfig retrieves basic WLAN settings.",
+	"DhcpConfig":              "DhcpConfig retrieves DHCP configuration.",
+	"LanSettings":             "LanSettings retrieves the router LAN (DHCP) settings.",
+	"LanSettingsSet":          "LanSettingsSet sets the router LAN (DHCP) settings, after validating them.  Note: changing the router's IP address or subnet will cause the device to restart its LAN interface, and the client will need to be recreated with the new address.",
+	"StaticLeases":            "StaticLeases retrieves the static DHCP bindings.",
+	"StaticLeaseAdd":          "StaticLeaseAdd adds a static DHCP binding of the MAC address to the IP address, replacing any existing binding for the MAC address.",
+	"StaticLeaseDelete":       "StaticLeaseDelete deletes the static DHCP binding for the MAC address.",
+	"IPv6Settings":            "IPv6Settings retrieves the IPv6 configuration.",
+	"IPv6SettingsSet":         "IPv6SettingsSet sets the IPv6 configuration.",
+	"Routes":                  "Routes retrieves the static routes.",
+	"RouteAdd":                "RouteAdd adds a static route, replacing any existing route with the same destination and mask.",
+	"RouteDelete":             "RouteDelete deletes the static route with the specified destination and mask.",
+	"CradleStatusInfo":        "CradleStatusInfo retrieves cradle status information.",
+	"CradleMACSet":            "CradleMACSet sets the MAC address for the cradle.",
+	"CradleMAC":               "CradleMAC retrieves cradle MAC address.",
+	"AutorunVersion":          "AutorunVersion retrieves device autorun version.",
+	"DeviceBasicInfo":         "DeviceBasicInfo retrieves basic device information.",
+	"PublicKey":               "PublicKey retrieves webserver public key.",
+	"DeviceControl":           "DeviceControl sends a control code to the device.",
+	"DeviceReboot":            "DeviceReboot restarts the device.",
+	"RebootAndWait":           "RebootAndWait restarts the device, and waits until the WebUI answers again, establishing a new session (logging in again when the Auth option was given). The overall deadline is the deadline of ctx, or RebootTimeout when ctx has no deadline.",
+	"WaitUntilReady":          "WaitUntilReady waits until the device has finished booting and the SIM is ready (ie, after power-on, or after the SIM PIN has been entered), polling every 2 seconds. Until then, the device may refuse connections, or respond with errors (ie, system not available), which are ignored. Each time the device responds, a new session is started (logging in again when the Auth option was given), and the status is checked. The overall deadline is the deadline of ctx, or RebootTimeout when ctx has no deadline.",
+	"DeviceReset":             "DeviceReset resets the device configuration.",
+	"DeviceBackup":            "DeviceBackup backups device configuration and retrieves backed up configuration data as a base64 encoded string.",
+	"DeviceShutdown":          "DeviceShutdown shuts down the device.",
+	"DeviceFeatures":          "DeviceFeatures retrieves device feature information.",
+	"DeviceInfo":              "DeviceInfo retrieves general device information.",
+	"DeviceModeSet":           "DeviceModeSet sets the device mode (0-project, 1-debug).",
+	"FastbootFeatures":        "FastbootFeatures retrieves fastboot feature information.",
+	"FastbootFeaturesSet":     "FastbootFeaturesSet enables or disables fastboot.",
+	"PowerFeatures":           "PowerFeatures retrieves power feature information.",
+	"PowerFeaturesSet":        "PowerFeaturesSet enables or disables power saving.",
+	"TetheringFeatures":       "TetheringFeatures retrieves USB tethering feature information.",
+	"TetheringFeaturesSet":    "TetheringFeaturesSet enables or disables USB tethering.",
+	"SignalInfo":              "SignalInfo retrieves network signal information.",
+	"ServingCell":             "ServingCell retrieves the extended serving cell information, including any carrier aggregation secondary cells reported by the device (as scc1_band, scc1_pci, ...).",
+	"NeighborCells":           "NeighborCells retrieves the list of neighbor cells, where supported by the device.",
+	"ConnectionInfo":          "ConnectionInfo retrieves connection (dialup) information.",
+	"GlobalFeatures":          "GlobalFeatures retrieves global feature information.",
+	"Language":                "Language retrieves current language.",
+	"LanguageSet":             "LanguageSet sets the language.",
+	"NotificationInfo":        "NotificationInfo retrieves notification information.",
+	"ClearNotifications":      "ClearNotifications clears the device notifications.",
+	"SimInfo":                 "SimInfo retrieves SIM card information.",
+	"StatusInfo":              "StatusInfo retrieves general device status information.",
+	"Status":                  "Status retrieves the general device status.",
+	"TrafficInfo":             "TrafficInfo retrieves traffic statistic information.",
+	"Traffic":                 "Traffic retrieves the traffic statistics.",
+	"TrafficClear":            "TrafficClear clears the current traffic statistics.",
+	"MonthInfo":               "MonthInfo retrieves the month download statistic information.",
+	"MonthTraffic":            "MonthTraffic retrieves the current month traffic statistics.",
+	"DataPlan":                "DataPlan retrieves the monthly data plan settings.",
+	"DataPlanSet":             "DataPlanSet sets the monthly data plan settings, keeping the device's data limit alert state.",
+	"WlanMonthInfo":           "WlanMonthInfo retrieves the WLAN month download statistic information.",
+	"NetworkInfo":             "NetworkInfo retrieves network provider information. When the device only reports the numeric PLMN, the operator name is looked up from a built-in table.",
+	"WifiFeatures":            "WifiFeatures retrieves wifi feature information.",
+	"WifiSwitchInfo":          "WifiSwitchInfo retrieves the on/off status of the Wi-Fi radios.",
+	"WifiSwitch":              "WifiSwitch turns all of the Wi-Fi radios reported by the device on or off.",
+	"WifiSwitch24GHz":         "WifiSwitch24GHz turns the 2.4 GHz Wi-Fi radio on or off.",
+	"WifiSwitch5GHz":          "WifiSwitch5GHz turns the 5 GHz Wi-Fi radio on or off.",
+	"WifiHostList":            "WifiHostList retrieves the list of hosts connected to the Wi-Fi.",
+	"WifiHosts":               "WifiHosts retrieves the hosts connected to the Wi-Fi along with their per-station statistics.",
+	"LanHostInfo":             "LanHostInfo retrieves the list of hosts connected to the LAN (ie, by Ethernet), as reported by B-series routers. Depending on the firmware, Wi-Fi hosts are also included.",
+	"LanHosts":                "LanHosts retrieves the active hosts connected to the LAN.",
+	"AllHosts":                "AllHosts retrieves the hosts connected to the LAN and the Wi-Fi, merging hosts reported by both by MAC address. Hosts from a list not supported by the device firmware (ie, the LAN list on devices without Ethernet) are omitted.",
+	"WakeOnLan":               "WakeOnLan sends a Wake-on-LAN magic packet to the LAN host with the MAC address, using the Wake-on-LAN function of routers whose firmware supports it (ie, to wake a host behind the router remotely).",
+	"BandwidthLimits":         "BandwidthLimits retrieves the per-device bandwidth limits, on routers whose firmware supports bandwidth control.",
+	"BandwidthLimitSet":       "BandwidthLimitSet sets the upload and download limits (in kbit/s, 0 being unlimited) of the host with the MAC address, replacing any existing limit for the MAC address. Setting both limits to 0 removes the limit.",
+	"BandwidthLimitDelete":    "BandwidthLimitDelete removes the bandwidth limit of the host with the MAC address.",
+	"WifiMacFilter":           "WifiMacFilter retrieves the Wi-Fi MAC filter settings.",
+	"WifiHostBlock":           "WifiHostBlock evicts and blocks the Wi-Fi host with the specified MAC address by adding it to the MAC filter deny list (or removing it from the allow list, when the MAC filter is in allow mode).",
+	"WifiHostUnblock":         "WifiHostUnblock removes the Wi-Fi host with the specified MAC address from the MAC filter deny list.",
+	"WifiTimeSwitch":          "WifiTimeSwitch retrieves the Wi-Fi on/off schedule.",
+	"WifiTimeSwitchSet":       "WifiTimeSwitchSet sets the Wi-Fi on/off schedule.",
+	"ModeList":                "ModeList retrieves available network modes.",
+	"ModeInfo":                "ModeInfo retrieves network mode settings information.",
+	"ModeNetworkInfo":         "ModeNetworkInfo retrieves current network mode information.",
+	"ModeSet":                 "ModeSet sets the network mode.",
+	"NetworkModeSet":          "NetworkModeSet sets the network mode, retaining the current band settings.",
+	"LTEBandLock":             "LTEBandLock locks the device to the specified LTE bands, retaining the current network mode and network band. When no bands are specified, the lock is cleared (ie, all bands are allowed).",
+	"NRBandLock":              "NRBandLock locks NR capable devices to the specified NR (5G) bands, retaining the current network mode and other band settings. When no bands are specified, the lock is cleared (ie, all bands are allowed).",
+	"NRMode":                  "NRMode retrieves the NR (5G) SA/NSA mode preference of NR capable devices.",
+	"NRModeSet":               "NRModeSet sets the NR (5G) SA/NSA mode preference of NR capable devices.",
+	"NetworkScan":             "NetworkScan scans for available networks (PLMNs). Note that scanning can take a long time, and uses NetworkScanTimeout as the request timeout, unless overridden with WithCallTimeout.",
+	"NetworkRegister":         "NetworkRegister manually registers the device on the network with the specified PLMN (ie, MCC and MNC) and radio access technology. When plmn is empty, automatic network selection is restored.",
+	"PinInfo":                 "PinInfo retrieves SIM PIN status information.",
+	"PinEnter":                "PinEnter enters a SIM PIN.",
+	"PinActivate":             "PinActivate activates a SIM PIN.",
+	"PinDeactivate":           "PinDeactivate deactivates a SIM PIN.",
+	"PinChange":               "PinChange changes a SIM PIN.",
+	"PinEnterPuk":             "PinEnterPuk enters a SIM PIN puk.",
+	"PinSaveInfo":             "PinSaveInfo retrieves SIM PIN save information.",
+	"PinSimlockInfo":          "PinSimlockInfo retrieves SIM lock information.",
+	"Connect":                 "Connect connects the Hilink device to the network provider.",
+	"Disconnect":              "Disconnect disconnects the Hilink device from the network provider.",
+	"MobileDataEnabled":       "MobileDataEnabled retrieves whether mobile data is enabled.",
+	"MobileDataSet":           "MobileDataSet enables or disables mobile data. On many newer devices, this should be used instead of Connect/Disconnect.",
+	"ConnectionSettings":      "ConnectionSettings retrieves the dialup connection settings.",
+	"ConnectionSettingsSet":   "ConnectionSettingsSet sets the dialup connection settings.",
+	"ProfileInfo":             "ProfileInfo retrieves profile information (ie, APN).",
+	"Profiles":                "Profiles retrieves the dialup (APN) profiles.",
+	"ProfileCreate":           "ProfileCreate creates a dialup (APN) profile. The profile is made the default profile when p.Default is true.",
+	"ProfileUpdate":           "ProfileUpdate updates the dialup (APN) profile with index p.Index. The profile is made the default profile when p.Default is true.",
+	"ProfileDelete":           "ProfileDelete deletes the dialup (APN) profile with the specified index.",
+	"ProfileSetDefault":       "ProfileSetDefault sets the default dialup (APN) profile.",
+	"SmsFeatures":             "SmsFeatures retrieves SMS feature information.",
+	"SmsList":                 "SmsList retrieves list of SMS in an inbox.",
+	"SmsMessages":             "SmsMessages retrieves a page of SMS messages in an inbox.",
+	"SmsListAll":              "SmsListAll retrieves all SMS messages in an inbox, fetching pages of SmsPageSize messages until the count reported by SmsCount is exhausted.",
+	"SmsCount":                "SmsCount retrieves count of SMS per inbox type.",
+	"SmsCounts":               "SmsCounts retrieves the SMS message counts.",
+	"SmsSend":                 "SmsSend sends an SMS. Messages too long for a single SMS are sent by the device as a concatenated SMS of up to SmsMaxParts parts.",
+	"SmsSendOpts":             "SmsSendOpts sends an SMS using the specified service center address, save location, and priority options.",
+	"SmsDeliveryReportSet":    "SmsDeliveryReportSet enables or disables SMS delivery (status) reports.",
+	"SmsDeliveryReports":      "SmsDeliveryReports retrieves the delivery (status) reports in the first count messages of the inbox, matching each to the most recent outbox message sent to the same phone number prior to the report.",
+	"SmsSendPdu":              "SmsSendPdu sends a raw (hex encoded) SMS PDU with the specified TPDU length, on devices supporting raw PDU mode.",
+	"SmsSendSubmit":           "SmsSendSubmit encodes and sends a SMS-SUBMIT message as a raw PDU, allowing flash messages, custom data coding schemes, and binary payloads to be sent on devices supporting raw PDU mode.",
+	"SmsPduList":              "SmsPduList retrieves list of SMS in an inbox as raw PDUs, on devices supporting raw PDU mode.",
+	"SmsPduMessages":          "SmsPduMessages retrieves and decodes a page of raw PDU SMS messages in an inbox, on devices supporting raw PDU mode.",
+	"SmsSendStatus":           "SmsSendStatus retrieves SMS send status information.",
+	"SmsReadSet":              "SmsReadSet sets the read status of one or more SMS.",
+	"SmsMarkAllRead":          "SmsMarkAllRead sets the read status of all unread SMS in an inbox, in batches of SmsPageSize.",
+	"SmsDelete":               "SmsDelete deletes a specified SMS.",
+	"UssdStatus":              "UssdStatus retrieves current USSD session status information.",
+	"UssdCode":                "UssdCode sends a USSD code to the Hilink device.",
+	"UssdCodeOpts":            "UssdCodeOpts sends a USSD code to the Hilink device using the specified code type and timeout. When the code type is raw, the code is sent as hex encoded, packed GSM-7. A zero timeout uses the device's default timeout.",
+	"UssdReply":               "UssdReply sends a reply to a prompt of the active USSD session (ie, a menu selection).",
+	"UssdContent":             "UssdContent retrieves content buffer of the active USSD session.",
+	"UssdContentOpts":         "UssdContentOpts retrieves content buffer of the active USSD session, for a code sent using the specified code type. When the code type is raw, the content returned hex encoded (as by some operators) is decoded as packed GSM-7 or UCS-2.",
+	"UssdRelease":             "UssdRelease releases the active USSD session.",
+	"DdnsList":                "DdnsList retrieves list of DDNS providers.",
+	"LogPath":                 "LogPath retrieves device log path (URL).",
+	"LogInfo":                 "LogInfo retrieves current log setting information.",
+	"LogSettingSet":           "LogSettingSet sets the log level and enables or disables logging.",
+	"LogDownload":             "LogDownload retrieves the compressed device log file using the current session, writing it to w. Returns the number of bytes written. The download is only bounded by the context (and not the client's request timeout), unless overridden with WithCallTimeout.",
+	"FirmwareUpdateCheck":     "FirmwareUpdateCheck triggers a check for new firmware versions.",
+	"FirmwareNewVersion":      "FirmwareNewVersion retrieves the result of the last firmware update check.",
+	"FirmwareUpdateStatus":    "FirmwareUpdateStatus retrieves the status of the firmware update process.",
+	"FirmwareAutoUpdate":      "FirmwareAutoUpdate retrieves whether automatic firmware updates are enabled.",
+	"FirmwareAutoUpdateSet":   "FirmwareAutoUpdateSet enables or disables automatic firmware updates.",
+	"PhonebookGroupList":      "PhonebookGroupList retrieves list of the phonebook groups.",
+	"PhonebookCount":          "PhonebookCount retrieves count of phonebook entries per group.",
+	"PhonebookImport":         "PhonebookImport imports SIM contacts into specified phonebook group.",
+	"PhonebookDelete":         "PhonebookDelete deletes a specified phonebook entry.",
+	"PhonebookList":           "PhonebookList retrieves list of phonebook entries from a specified group.",
+	"PhonebookEntries":        "PhonebookEntries retrieves a page of phonebook entries from a specified group.",
+	"PhonebookCreate":         "PhonebookCreate creates a new phonebook entry.",
+	"FirewallFeatures":        "FirewallFeatures retrieves firewall security feature information.",
+	"FirewallIPFilters":       "FirewallIPFilters retrieves the LAN IP filter rules.",
+	"FirewallIPFilterAdd":     "FirewallIPFilterAdd adds a LAN IP filter rule.",
+	"FirewallIPFilterRemove":  "FirewallIPFilterRemove removes the LAN IP filter rule with the specified index (ie, its position in the list returned by FirewallIPFilters).",
+	"FirewallMacFilters":      "FirewallMacFilters retrieves the MAC filter rules.",
+	"FirewallMacFilterAdd":    "FirewallMacFilterAdd adds an enabled MAC filter rule for the specified MAC address.",
+	"FirewallMacFilterRemove": "FirewallMacFilterRemove removes the MAC filter rules for the specified MAC address.",
+	"FirewallURLFilters":      "FirewallURLFilters retrieves the URL filter rules.",
+	"FirewallURLFilterAdd":    "FirewallURLFilterAdd adds an enabled URL filter rule for the specified URL.",
+	"FirewallURLFilterRemove": "FirewallURLFilterRemove removes the URL filter rules for the specified URL.",
+	"TimeRules":               "TimeRules retrieves the parental control (internet access time limit) rules.",
+	"TimeRuleAdd":             "TimeRuleAdd adds a parental control rule, replacing any existing rule with the same name.",
+	"TimeRuleDelete":          "TimeRuleDelete deletes the parental control rule with the specified name.",
+	"TimeRuleEnable":          "TimeRuleEnable enables or disables the parental control rules with the specified names (ie, to switch a homework hours rule set on or off). When no names are specified, all rules are changed.",
+	"RemoteAccess":            "RemoteAccess retrieves the WAN-side (remote) management access settings.",
+	"RemoteAccessSet":         "RemoteAccessSet sets the WAN-side (remote) management access settings.  Note: when AllowedIPs is empty, remote management is permitted from any address.",
+	"DmzConfig":               "DmzConfig retrieves DMZ status and IP address of DMZ host.",
+	"DmzConfigSet":            "DmzConfigSet enables or disables the DMZ and the DMZ IP address of the device.",
+	"SipAlg":                  "SipAlg retrieves status and port of the SIP application-level gateway.",
+	"SipAlgSet":               "SipAlgSet enables/disables SIP application-level gateway and sets SIP port.",
+	"NatType":                 "NatType retrieves NAT type.",
+	"NatTypeSet":              "NatTypeSet sets NAT type (values: 0, 1).",
+	"Upnp":                    "Upnp retrieves the status of UPNP.",
+	"UpnpSet":                 "UpnpSet enables/disables UPNP.",
+	"VirtualServers":          "VirtualServers retrieves the port forwarding (virtual server) rules.",
+	"VirtualServerAdd":        "VirtualServerAdd adds a port forwarding (virtual server) rule.",
+	"VirtualServerDelete":     "VirtualServerDelete deletes the port forwarding (virtual server) rule with the specified index (ie, its position in the list returned by VirtualServers).",
+	"WlanBasicSettings":       "WlanBasicSettings retrieves the basic Wi-Fi settings.",
+	"WlanBasicSettingsSet":    "WlanBasicSettingsSet sets the Wi-Fi SSID and whether the SSID is hidden, restarting Wi-Fi.",
+	"AutoApn":                 "AutoApn retrieves whether the APN is automatically selected.",
+	"AutoApnSet":              "AutoApnSet enables or disables automatic APN selection.",
+	"NetworkReconnect":        "NetworkReconnect reconnects to the mobile network.",
+	"SmsExport":               "SmsExport writes all SMS messages in an inbox to w in the specified format.",
+	"FirmwareUpgradeStart":    "FirmwareUpgradeStart acknowledges the new firmware version found by the last firmware update check, starting the download and install of the firmware.",
+	"FirmwareUpgradeCancel":   "FirmwareUpgradeCancel cancels the download of a firmware upgrade.",
+	"FirmwareUpgradeWait":     "FirmwareUpgradeWait polls the firmware update status every interval, passing each status to progress (if not nil), until the download and install of all components completes or ctx is done.  As the device reboots to install the firmware, a failed request after the final component has reached 100% is treated as completion.",
+	"InfoAll":                 "InfoAll concurrently retrieves the device information, status, signal, traffic statistics and network operator, returning a combined snapshot. An error is returned if any of the requests fail.",
+	"SmsAll":                  "SmsAll returns an iterator over all SMS messages in an inbox, transparently fetching pages of SmsPageSize messages as the iteration progresses. A failed request is yielded as an error, ending the iteration.",
+	"PhonebookAll":            "PhonebookAll returns an iterator over all phonebook entries in a group, transparently fetching pages of SmsPageSize entries as the iteration progresses. A failed request is yielded as an error, ending the iteration.",
+	"WifiHostsAll":            "WifiHostsAll returns an iterator over the Wi-Fi hosts. A failed request is yielded as an error, ending the iteration.",
+	"DeviceProfile":           "DeviceProfile returns the device profile given with WithDeviceProfile, or detects it from the device name reported by the device. When the device refuses to report its name (ie, when login is required), the default profile is used.",
+	"SMS":                     "SMS returns the SMS (and USSD) methods of the client.",
+	"Net":                     "Net returns the mobile network, connection, and LAN methods of the client.",
+	"Wlan":                    "Wlan returns the Wi-Fi methods of the client.",
+	"Device":                  "Device returns the device, firmware, and log methods of the client.",
+	"Security":                "Security returns the firewall, port forwarding, remote access, and SIM PIN methods of the client.",
+	"SampleThroughput":        "SampleThroughput measures the current upload and download rates, by taking two traffic statistics readings window apart (default 1 second). Handles devices with 32-bit counters that roll over.",
+	"UssdSendAndWait":         "UssdSendAndWait sends a USSD code and waits until the USSD status is no longer waiting (or ctx is done), returning the USSD content.",
+	"UssdSendAndWaitOpts":     "UssdSendAndWaitOpts sends a USSD code using the specified code type, and waits until the USSD status is no longer waiting (or ctx is done), returning the USSD content (see UssdContentOpts).",
+	"UssdStart":               "UssdStart starts an interactive USSD session by sending the USSD code, returning the session and the content (ie, menu) sent by the network.",
+	"UssdStartOpts":           "UssdStartOpts starts an interactive USSD session by sending the USSD code using the specified code type, which is also used for the session's replies, returning the session and the content (ie, menu) sent by the network.",
+	"UssdRun":                 "UssdRun runs a USSD script, returning the content returned for each of the executed steps. The USSD session is released after the script completes or fails.",
+	"WatchSms":                "WatchSms watches the inbox for new SMS messages, polling the device's unread count every interval. New unread messages are delivered on the returned message channel, and marked as read once received. Errors encountered while polling are delivered on the returned error channel, and do not stop the watch. Both channels are closed when ctx is done.  Messages are marked as read before the receiver has processed them. Use WatchSmsFunc when messages must not be lost (ie, when forwarding).",
+	"WatchSmsFunc":            "WatchSmsFunc watches the inbox for new SMS messages, polling the device's unread count every interval, and calling f with each new unread message, oldest first. A message is only marked as read after f returns nil, and is passed to f again on the next poll when f returns an error, providing at-least-once delivery. Errors encountered while polling, and errors returned by f, are passed to errf (when not nil), and do not stop the watch. When polling fails, a new session is started (logging in again when the Auth option was given). Blocks until ctx is done, returning the context error.",
 }
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+
+	"github.com/kenshaw/hilink"
+)
+
+// errDryRun is the error returned by the dry run transport, stopping the
+// request from being sent.
+var errDryRun = errors.New("dry run")
+
+// dryRunTransport is a http transport that writes requests instead of
+// sending them.
+type dryRunTransport struct {
+	w io.Writer
+}
+
+// RoundTrip satisfies the http.RoundTripper interface.
+func (t *dryRunTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	fmt.Fprintf(t.w, "%s %s\n", req.Method, req.URL)
+	if req.Body != nil {
+		defer req.Body.Close()
+		buf, err := ioutil.ReadAll(req.Body)
+		if err != nil {
+			return nil, err
+		}
+		fmt.Fprintf(t.w, "Content-Type: %s\n\n%s", req.Header.Get("Content-Type"), buf)
+		if len(buf) != 0 && buf[len(buf)-1] != '\n' {
+			fmt.Fprintln(t.w)
+		}
+	}
+	return nil, errDryRun
+}
+
+// doDryRun calls the method using a client that writes the first request to
+// w without sending it. Methods that read the current values from the device
+// before making changes (ie, the Add and Delete methods) will only write the
+// initial read request.
+func doDryRun(ctx context.Context, w io.Writer, cfg Profile, method reflect.Method, in []reflect.Value) error {
+	cl := hilink.NewClient(
+		hilink.WithURL(cfg.Endpoint),
+		hilink.WithTransport(&dryRunTransport{w: w}),
+	)
+	_, err := callValue(ctx, cl, method, in)
+	switch {
+	case errors.Is(err, errDryRun):
+		return nil
+	case err != nil:
+		return err
+	}
+	return errors.New("method did not make a request")
+}
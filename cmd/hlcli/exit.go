@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net"
+
+	"github.com/kenshaw/hilink"
+)
+
+// Exit codes.
+const (
+	// exitError is the exit code for general errors.
+	exitError = 1
+	// exitUsage is the exit code for invalid usage (same as flag.ExitOnError).
+	exitUsage = 2
+	// exitAuth is the exit code for authentication failures.
+	exitAuth = 3
+	// exitBusy is the exit code when the device is busy.
+	exitBusy = 4
+	// exitUnsupported is the exit code when the method is not supported by the
+	// device firmware.
+	exitUnsupported = 5
+	// exitUnreachable is the exit code when the device is not reachable.
+	exitUnreachable = 6
+)
+
+// exitCodeError wraps an error with an exit code.
+type exitCodeError struct {
+	code int
+	err  error
+}
+
+// Error satisfies the error interface.
+func (err *exitCodeError) Error() string {
+	return err.err.Error()
+}
+
+// Unwrap satisfies the errors.Unwrap interface.
+func (err *exitCodeError) Unwrap() error {
+	return err.err
+}
+
+// exitCode returns the exit code for the error.
+func exitCode(err error) int {
+	var exitErr *exitCodeError
+	if errors.As(err, &exitErr) {
+		return exitErr.code
+	}
+	if errors.Is(err, hilink.ErrNotSupported) {
+		return exitUnsupported
+	}
+	var apiErr *hilink.APIError
+	if errors.As(err, &apiErr) {
+		switch apiErr.Code {
+		case 100003, 108001, 108002, 108003, 108006, 108007, 125001:
+			return exitAuth
+		case 100004, 113018, 120001:
+			return exitBusy
+		case 100002:
+			return exitUnsupported
+		}
+		return exitError
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) || errors.Is(err, context.DeadlineExceeded) {
+		return exitUnreachable
+	}
+	return exitError
+}
@@ -1,3 +1,4 @@
+//go:build ignore
 // +build ignore
 
 package main
@@ -13,27 +14,31 @@ import (
 	"io/ioutil"
 	"log"
 	"os"
-	"path/filepath"
+	"sort"
 	"strings"
 )
 
 func main() {
 	out := flag.String("o", "doc.go", "out file")
-	pkg := flag.String("pkg", "github.com/kenshaw/hilink", "go package")
+	dir := flag.String("dir", "../..", "hilink package directory")
 	flag.Parse()
-	if err := run(*out, *pkg); err != nil {
+	if err := run(*out, *dir); err != nil {
 		log.Fatal(err)
 	}
 }
 
-func run(out, pkg string) error {
+func run(out, dir string) error {
 	fs := token.NewFileSet()
-	pkgs, err := parser.ParseDir(fs, filepath.Join(os.Getenv("GOPATH"), "src", pkg), nil, parser.ParseComments)
+	// skip tests, as the external tests are a separate package
+	notTest := func(fi os.FileInfo) bool {
+		return !strings.HasSuffix(fi.Name(), "_test.go")
+	}
+	pkgs, err := parser.ParseDir(fs, dir, notTest, parser.ParseComments)
 	if err != nil {
 		return err
 	}
 	if len(pkgs) != 1 {
-		return fmt.Errorf("invalid package count in %s", pkg)
+		return fmt.Errorf("invalid package count in %s", dir)
 	}
 	// silly loop because it pkgs is a map ...
 	var pkgName string
@@ -42,10 +47,17 @@ func run(out, pkg string) error {
 	if pkgName != "hilink" {
 		return fmt.Errorf("invalid package name %s", pkgName)
 	}
+	// sort files, so the generated maps are in a stable order
+	var names []string
+	for name := range pkgs[pkgName].Files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
 	buf := new(bytes.Buffer)
 	buf.WriteString(hdr)
 	buf.WriteString("var methodParamMap = map[string][]string{\n")
-	for _, f := range pkgs[pkgName].Files {
+	for _, name := range names {
+		f := pkgs[pkgName].Files[name]
 		for _, d := range f.Decls {
 			fd, typ, ok := getRecvType(d)
 			if !ok || typ != "Client" || !fd.Name.IsExported() || fd.Name.Name == "Do" {
@@ -66,7 +78,8 @@ func run(out, pkg string) error {
 	}
 	buf.WriteString("}\n\n")
 	buf.WriteString("var methodCommentMap = map[string]string{\n")
-	for _, f := range pkgs[pkgName].Files {
+	for _, name := range names {
+		f := pkgs[pkgName].Files[name]
 		for _, d := range f.Decls {
 			fd, typ, ok := getRecvType(d)
 			if !ok || typ != "Client" || !fd.Name.IsExported() || fd.Name.Name == "Do" {
@@ -3,16 +3,19 @@ package main
 //go:generate go run gen.go
 
 import (
+	"bytes"
 	"context"
-	"encoding/json"
 	"errors"
 	"flag"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"log"
 	"os"
 	"reflect"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/kenshaw/hilink"
 )
@@ -26,10 +29,25 @@ func main() {
 		doHelpMethods()
 	case len(os.Args) == 3 && (os.Args[1] == "help" || os.Args[1] == "list"):
 		doHelpMethodParams(os.Args[2])
+	case os.Args[1] == "raw":
+		if err := doRaw(context.Background(), os.Args[2:]); err != nil {
+			fmt.Fprintf(os.Stderr, "error: %v\n", err)
+			os.Exit(exitCode(err))
+		}
+	case os.Args[1] == "schema":
+		if err := doSchema(os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "error: %v\n", err)
+			os.Exit(exitCode(err))
+		}
+	case os.Args[1] == "shell":
+		if err := doShell(context.Background(), os.Args[2:]); err != nil {
+			fmt.Fprintf(os.Stderr, "error: %v\n", err)
+			os.Exit(exitCode(err))
+		}
 	default:
 		if err := run(context.Background()); err != nil {
 			fmt.Fprintf(os.Stderr, "error: %v\n", err)
-			os.Exit(1)
+			os.Exit(exitCode(err))
 		}
 	}
 }
@@ -43,75 +61,360 @@ func run(ctx context.Context) error {
 	}
 	// create flagset
 	fs := flag.NewFlagSet(method.Name, flag.ExitOnError)
-	debug := fs.Bool("v", false, "enable verbose")
-	endpoint := fs.String("endpoint", "http://192.168.8.1/", "api endpoint")
-	isVariadic := method.Type.IsVariadic()
+	g := addGlobalFlags(fs)
+	watch := fs.Duration("watch", 0, "re-run the method every interval")
+	diff := fs.Bool("diff", false, "only print changed lines with -watch")
+	dryRun := fs.Bool("dry-run", false, "print the request without sending it")
 	// add method params to flagset
+	in, err := addMethodFlags(fs, method)
+	if err != nil {
+		return err
+	}
+	// parse flags
+	fs.Parse(os.Args[2:])
+	convertArgs(method, in)
+	// load config, with flags overriding config values
+	cfg, err := g.settings(fs)
+	if err != nil {
+		return err
+	}
+	if *dryRun {
+		return doDryRun(ctx, os.Stdout, cfg, method, in)
+	}
+	// execute on multiple devices
+	endpoints, err := g.endpoints(cfg)
+	if err != nil {
+		return err
+	}
+	if len(endpoints) > 1 {
+		if *watch != 0 {
+			return errors.New("-watch cannot be used with multiple endpoints")
+		}
+		return doFanOut(ctx, cfg, *g.debug, endpoints, method, in, *g.query)
+	}
+	// create client
+	cl, err := newClient(cfg, *g.debug)
+	if err != nil {
+		return err
+	}
+	if *watch != 0 {
+		return doWatch(ctx, cl, cfg, *g.debug, method, in, *g.query, *watch, *diff)
+	}
+	return call(ctx, os.Stdout, cl, method, in, cfg.Output, *g.query)
+}
+
+// doWatch calls the method every interval, printing the full output, or only
+// the changed lines when diff is true. Errors are printed, and a new session
+// is started before the next call.
+func doWatch(ctx context.Context, cl *hilink.Client, cfg Profile, debug bool, method reflect.Method, in []reflect.Value, query string, interval time.Duration, diff bool) error {
+	var prev []string
+	for {
+		buf := new(bytes.Buffer)
+		err := call(ctx, buf, cl, method, in, cfg.Output, query)
+		now := time.Now().Format("15:04:05")
+		switch {
+		case err != nil:
+			fmt.Fprintf(os.Stderr, "%s error: %v\n", now, err)
+			if c, err := newClient(cfg, debug); err == nil {
+				cl = c
+			}
+		case !diff:
+			fmt.Fprintf(os.Stdout, "--- %s\n%s", now, buf.String())
+		default:
+			lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+			if changed := diffLines(prev, lines); len(changed) != 0 {
+				fmt.Fprintf(os.Stdout, "--- %s\n%s\n", now, strings.Join(changed, "\n"))
+			}
+			prev = lines
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
+
+// diffLines returns the lines removed from a (prefixed with -) and added in b
+// (prefixed with +).
+func diffLines(a, b []string) []string {
+	count := func(l []string) map[string]int {
+		m := make(map[string]int)
+		for _, s := range l {
+			m[s]++
+		}
+		return m
+	}
+	ma, mb := count(a), count(b)
+	var changed []string
+	for _, s := range a {
+		if mb[s] == 0 {
+			changed = append(changed, "- "+s)
+		} else {
+			mb[s]--
+		}
+	}
+	for _, s := range b {
+		if ma[s] == 0 {
+			changed = append(changed, "+ "+s)
+		} else {
+			ma[s]--
+		}
+	}
+	return changed
+}
+
+// doRaw sends a raw request to the specified path, using GET, or POST when a
+// request body is provided, printing the decoded response.
+func doRaw(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("raw", flag.ExitOnError)
+	g := addGlobalFlags(fs)
+	path := fs.String("path", "", "api path (ie, api/device/information)")
+	body := fs.String("body", "", "file containing the xml request body to POST, or - for stdin")
+	fs.Parse(args)
+	if *path == "" {
+		return errors.New("must specify path")
+	}
+	var err error
+	// read body
+	var buf []byte
+	switch *body {
+	case "":
+	case "-":
+		if buf, err = ioutil.ReadAll(os.Stdin); err != nil {
+			return err
+		}
+	default:
+		if buf, err = ioutil.ReadFile(*body); err != nil {
+			return err
+		}
+	}
+	cfg, err := g.settings(fs)
+	if err != nil {
+		return err
+	}
+	cl, err := newClient(cfg, *g.debug)
+	if err != nil {
+		return err
+	}
+	res, err := cl.DoRaw(ctx, strings.TrimPrefix(*path, "/"), buf)
+	if err != nil {
+		return err
+	}
+	if cfg.Output == "xml" {
+		_, err := os.Stdout.Write(res)
+		return err
+	}
+	// decode, checking for errors
+	m, err := hilink.DecodeXMLRoot(res)
+	if err != nil {
+		return err
+	}
+	v, ok := m["response"]
+	if !ok {
+		return hilink.ErrMissingRootElement
+	}
+	return writeOutput(os.Stdout, cfg.Output, *g.query, v)
+}
+
+// globalFlags are the flags common to all methods.
+type globalFlags struct {
+	debug    *bool
+	endpoint *endpointList
+	devices  *string
+	profile  *string
+	config   *string
+	output   *string
+	query    *string
+	record   *string
+	replay   *string
+	insecure *bool
+	ua       *string
+}
+
+// addGlobalFlags adds the common flags to the flagset.
+func addGlobalFlags(fs *flag.FlagSet) *globalFlags {
+	g := &globalFlags{
+		debug:    fs.Bool("v", false, "enable verbose"),
+		endpoint: new(endpointList),
+		devices:  fs.String("devices", "", "file containing api endpoints, one per line"),
+		profile:  fs.String("profile", "", "config profile"),
+		config:   fs.String("config", "", "config file (default ~/.config/hlcli/config.yaml)"),
+		output:   fs.String("o", "json", "output format (json, yaml, xml, table)"),
+		query:    fs.String("q", "", "select a value using a dotted path (ie, Messages.Message.0.Phone)"),
+		record:   fs.String("record", "", "record requests and responses to fixtures in the directory"),
+		replay:   fs.String("replay", "", "replay responses from fixtures in the directory"),
+		insecure: fs.Bool("insecure", false, "do not verify the device's TLS certificate (https endpoints)"),
+		ua:       fs.String("user-agent", "", "user agent sent with requests"),
+	}
+	fs.Var(g.endpoint, "endpoint", "api endpoint (default "+hilink.DefaultURL+"), may be repeated or comma separated")
+	return g
+}
+
+// endpoints returns the endpoints from the devices file, the endpoint flags,
+// or the config.
+func (g *globalFlags) endpoints(cfg Profile) ([]string, error) {
+	switch {
+	case *g.devices != "":
+		return readDevices(*g.devices)
+	case len(*g.endpoint) != 0:
+		return *g.endpoint, nil
+	}
+	return []string{cfg.Endpoint}, nil
+}
+
+// settings loads the config profile, with the parsed flags overriding config
+// values.
+func (g *globalFlags) settings(fs *flag.FlagSet) (Profile, error) {
+	cfg, err := loadProfile(*g.config, *g.profile)
+	if err != nil {
+		return Profile{}, err
+	}
+	fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "endpoint":
+			cfg.Endpoint = (*g.endpoint)[0]
+		case "o":
+			cfg.Output = *g.output
+		case "insecure":
+			cfg.Insecure = *g.insecure
+		case "user-agent":
+			cfg.UserAgent = *g.ua
+		}
+	})
+	if cfg.Endpoint == "" {
+		cfg.Endpoint = hilink.DefaultURL
+	}
+	if cfg.Output == "" {
+		cfg.Output = *g.output
+	}
+	if *g.record != "" && *g.replay != "" {
+		return Profile{}, errors.New("-record and -replay cannot be used together")
+	}
+	cfg.Record, cfg.Replay = *g.record, *g.replay
+	if _, ok := outputFormats[cfg.Output]; !ok {
+		return Profile{}, fmt.Errorf("unsupported output format %q", cfg.Output)
+	}
+	return cfg, nil
+}
+
+// newClient creates a client, that starts a session and logs in (when a
+// username is configured) on the first request.
+func newClient(cfg Profile, debug bool) (*hilink.Client, error) {
+	// hilink options
+	opts := []hilink.ClientOption{
+		hilink.WithURL(cfg.Endpoint),
+		hilink.WithAuth(cfg.Username, cfg.Password),
+		hilink.WithNoStart(true),
+	}
+	if cfg.Timeout != 0 {
+		opts = append(opts, hilink.WithTimeout(cfg.Timeout))
+	}
+	switch {
+	case cfg.Record != "":
+		opts = append(opts, hilink.WithTransport(hilink.NewFixtureTransport(cfg.Record, hilink.FixtureRecord, nil)))
+	case cfg.Replay != "":
+		opts = append(opts, hilink.WithTransport(hilink.NewFixtureTransport(cfg.Replay, hilink.FixtureReplay, nil)))
+	}
+	if cfg.Insecure {
+		opts = append(opts, hilink.WithInsecureSkipVerify())
+	}
+	if cfg.UserAgent != "" {
+		opts = append(opts, hilink.WithUserAgent(cfg.UserAgent))
+	}
+	for k, v := range cfg.Headers {
+		opts = append(opts, hilink.WithHeader(k, v))
+	}
+	if debug {
+		opts = append(opts, hilink.WithLogf(log.Printf))
+	}
+	// create client
+	cl, err := hilink.NewClientE(opts...)
+	if err != nil {
+		return nil, &exitCodeError{code: exitUsage, err: err}
+	}
+	return cl, nil
+}
+
+// addMethodFlags adds the method params to the flagset, returning the values
+// for the method call, with the first two (client and context) left empty.
+func addMethodFlags(fs *flag.FlagSet, method reflect.Method) ([]reflect.Value, error) {
+	if !callable(method) {
+		return nil, fmt.Errorf("method %s cannot be called from the command line", method.Name)
+	}
 	in := make([]reflect.Value, method.Type.NumIn())
 	for i := 2; i < method.Type.NumIn(); i++ {
 		p := method.Type.In(i)
 		n := methodParamMap[method.Name][i-2]
-		var v interface{}
 		switch p.Kind() {
 		case reflect.Bool:
-			v = fs.Bool(n, false, "")
+			in[i] = reflect.ValueOf(fs.Bool(n, false, "")).Elem()
 		case reflect.Int:
-			v = fs.Int(n, 0, "")
+			in[i] = reflect.ValueOf(fs.Int(n, 0, "")).Elem()
 		case reflect.Uint:
-			v = fs.Uint(n, 0, "")
+			in[i] = reflect.ValueOf(fs.Uint(n, 0, "")).Elem()
 		case reflect.String:
-			v = fs.String(n, "", "")
-		}
-		// special ...string case
-		if p.Kind() == reflect.Slice && isVariadic &&
-			i == method.Type.NumIn()-1 && reflect.String == p.Elem().Kind() {
-			v = fs.String(n, "", "")
+			in[i] = reflect.ValueOf(fs.String(n, "", "")).Elem()
+		default:
+			// durations, slices (ie, ...string) and structs
+			v := &paramValue{v: reflect.New(p).Elem()}
+			fs.Var(v, n, "")
+			in[i] = v.v
 		}
-		in[i] = reflect.ValueOf(v).Elem()
-	}
-	// parse flags
-	fs.Parse(os.Args[2:])
-	// hilink options
-	opts := []hilink.ClientOption{
-		hilink.WithURL(*endpoint),
 	}
-	if *debug {
-		opts = append(opts, hilink.WithLogf(log.Printf))
+	return in, nil
+}
+
+// convertArgs converts the parsed method params to their named types (ie,
+// hilink.Rat).
+func convertArgs(method reflect.Method, in []reflect.Value) {
+	for i := 2; i < method.Type.NumIn(); i++ {
+		if p := method.Type.In(i); p.Kind() != reflect.Slice && in[i].Type() != p {
+			in[i] = in[i].Convert(p)
+		}
 	}
-	// create client
-	cl := hilink.NewClient(opts...)
-	// retrieve session id
-	sessID, tokID, err := cl.NewSessionAndTokenID(ctx)
+}
+
+// call calls the method, writing the result (or the value selected by query)
+// to w in the output format.
+func call(ctx context.Context, w io.Writer, cl *hilink.Client, method reflect.Method, in []reflect.Value, output, query string) error {
+	v, err := callValue(ctx, cl, method, in)
 	if err != nil {
 		return err
 	}
-	// set session id
-	if err := cl.SetSessionAndTokenID(sessID, tokID); err != nil {
+	// special handling for bool
+	if s, ok := v.(string); ok && method.Type.Out(0).Kind() == reflect.Bool {
+		_, err := fmt.Fprintln(w, s)
 		return err
 	}
+	// encode and output
+	return writeOutput(w, output, query, v)
+}
+
+// callValue calls the method, returning the result. Bool results are
+// returned as SUCCESS or FAILURE.
+func callValue(ctx context.Context, cl *hilink.Client, method reflect.Method, in []reflect.Value) (interface{}, error) {
 	// push client onto params and execute
 	in[0] = reflect.ValueOf(cl)
 	in[1] = reflect.ValueOf(ctx)
-	out := method.Func.Call(in)
+	var out []reflect.Value
+	if method.Type.IsVariadic() {
+		// variadic params are passed as a slice
+		out = method.Func.CallSlice(in)
+	} else {
+		out = method.Func.Call(in)
+	}
 	if !out[1].IsNil() {
-		return out[1].Interface().(error)
+		return nil, out[1].Interface().(error)
 	}
 	// special handling for bool
 	if out[0].Type().Kind() == reflect.Bool {
-		msg := "SUCCESS"
 		if !out[0].Bool() {
-			msg = "FAILURE"
+			return "FAILURE", nil
 		}
-		fmt.Fprintln(os.Stdout, msg)
-		return nil
+		return "SUCCESS", nil
 	}
-	// json encode and output
-	buf, err := json.MarshalIndent(out[0].Interface(), "", "  ")
-	if err != nil {
-		return err
-	}
-	_, err = os.Stdout.Write(append(buf, '\n'))
-	return err
+	return out[0].Interface(), nil
 }
 
 func max(a, b int) int {
@@ -128,8 +431,8 @@ func findMethod(typ reflect.Type, methodName string) (reflect.Method, error) {
 	methodNum := 0
 	for i := 0; i < typ.NumMethod(); i++ {
 		m := typ.Method(i)
-		// skip if results != 2 or if last result is not error
-		if m.Type.NumOut() != 2 || !m.Type.Out(1).Implements(errorInterface) {
+		// skip methods that can't be called from the command line
+		if !callable(m) {
 			continue
 		}
 		if strings.ToLower(methodName) == strings.ToLower(m.Name) {
@@ -138,7 +441,7 @@ func findMethod(typ reflect.Type, methodName string) (reflect.Method, error) {
 		}
 	}
 	if !found {
-		return reflect.Method{}, errors.New("unknown method name")
+		return reflect.Method{}, &exitCodeError{code: exitUsage, err: errors.New("unknown method name")}
 	}
 	return typ.Method(methodNum), nil
 }
@@ -161,8 +464,8 @@ Where <method> is one of the following:
 `)
 	for i := 0; i < len(methods); i++ {
 		m := methods[i]
-		// skip if results != 2 or if last result is not error
-		if m.Type.NumOut() != 2 || !m.Type.Out(1).Implements(errorInterface) {
+		// skip methods that can't be called from the command line
+		if !callable(m) {
 			continue
 		}
 		comment := strings.TrimSuffix(strings.TrimPrefix(methodCommentMap[m.Name], m.Name+" "), ".")
@@ -174,6 +477,23 @@ Where <method> is one of the following:
 Note that method names are case-insensitive.
 For help regarding the available parameters for a method:
 	`+os.Args[0]+` help <method>
+
+To run multiple methods interactively, using a single session:
+	`+os.Args[0]+` shell
+
+To print a JSON description of the methods and their parameters:
+	`+os.Args[0]+` schema
+
+To send a raw request to an API path (POST when a body is provided):
+	`+os.Args[0]+` raw -path api/... [-body file.xml|-]
+
+Exit codes:
+	1  general error
+	2  invalid usage or unknown method
+	3  authentication failure
+	4  device busy
+	5  method not supported by the device firmware
+	6  device not reachable
 `)
 }
 
@@ -182,20 +502,26 @@ func doHelpMethodParams(methodName string) {
 	method, err := findMethod(typ, methodName)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "error: unknown method %q\n", methodName)
-		os.Exit(1)
+		os.Exit(exitUsage)
 	}
 	methodTyp := method.Func.Type()
 	str := fmt.Sprintf("Parameters for %s:\n", method.Name)
-	str += "  -v                  enable verbose\n  -endpoint=string    api endpoint\n"
+	str += "  -v                  enable verbose\n  -endpoint=string    api endpoint, may be repeated or comma separated\n"
+	str += "  -devices=string     file containing api endpoints, one per line\n"
+	str += "  -profile=string     config profile\n  -config=string      config file (default ~/.config/hlcli/config.yaml)\n"
+	str += "  -o=string           output format (json, yaml, xml, table)\n"
+	str += "  -q=string           select a value using a dotted path\n"
+	str += "  -watch=duration     re-run the method every interval\n  -diff               only print changed lines with -watch\n"
+	str += "  -dry-run            print the request without sending it\n"
+	str += "  -record=string      record requests and responses to fixtures in the directory\n"
+	str += "  -replay=string      replay responses from fixtures in the directory\n"
+	str += "  -insecure           do not verify the device's TLS certificate\n"
+	str += "  -user-agent=string  user agent sent with requests\n"
 	for i := 2; i < methodTyp.NumIn(); i++ {
 		p := methodTyp.In(i)
-		lastIsVariadic := methodTyp.IsVariadic() && i == methodTyp.NumIn()-1
 		str += "  -" + methodParamMap[method.Name][i-2]
-		if methodTyp.Kind() != reflect.Bool {
-			str += "=" + strings.TrimPrefix(p.String(), "[]")
-			if lastIsVariadic {
-				str += "..."
-			}
+		if p.Kind() != reflect.Bool {
+			str += "=" + paramUsage(p)
 		}
 		str += "\n"
 	}
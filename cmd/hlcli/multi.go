@@ -0,0 +1,109 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// endpointList is a flag value for one or more endpoints, specified either by
+// repeating the flag, or as a comma separated list.
+type endpointList []string
+
+// String satisfies the flag.Value interface.
+func (l *endpointList) String() string {
+	return strings.Join(*l, ",")
+}
+
+// Set satisfies the flag.Value interface.
+func (l *endpointList) Set(s string) error {
+	for _, z := range strings.Split(s, ",") {
+		if z = strings.TrimSpace(z); z != "" {
+			*l = append(*l, z)
+		}
+	}
+	return nil
+}
+
+// readDevices reads the endpoints from a devices file, containing one endpoint
+// per line, ignoring blank lines and lines starting with #.
+func readDevices(name string) ([]string, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var l []string
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		if line := strings.TrimSpace(s.Text()); line != "" && !strings.HasPrefix(line, "#") {
+			l = append(l, line)
+		}
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	if len(l) == 0 {
+		return nil, fmt.Errorf("no devices in %s", name)
+	}
+	return l, nil
+}
+
+// doFanOut calls the method on all endpoints concurrently, writing the results
+// keyed by endpoint. Failed calls are included in the results as an error
+// value.
+func doFanOut(ctx context.Context, cfg Profile, debug bool, endpoints []string, method reflect.Method, in []reflect.Value, query string) error {
+	results := make(map[string]interface{})
+	var failed []error
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, endpoint := range endpoints {
+		wg.Add(1)
+		go func(endpoint string) {
+			defer wg.Done()
+			c := cfg
+			c.Endpoint = endpoint
+			args := append([]reflect.Value(nil), in...)
+			v, err := func() (interface{}, error) {
+				cl, err := newClient(c, debug)
+				if err != nil {
+					return nil, err
+				}
+				v, err := callValue(ctx, cl, method, args)
+				if err != nil || query == "" {
+					return v, err
+				}
+				return selectPath(v, query)
+			}()
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				failed = append(failed, err)
+				v = map[string]interface{}{"error": err.Error()}
+			}
+			results[endpoint] = v
+		}(endpoint)
+	}
+	wg.Wait()
+	if err := writeOutput(os.Stdout, cfg.Output, "", results); err != nil {
+		return err
+	}
+	if len(failed) == 0 {
+		return nil
+	}
+	// use the exit code of the failures when they are all the same
+	code := exitCode(failed[0])
+	for _, err := range failed[1:] {
+		if exitCode(err) != code {
+			code = exitError
+		}
+	}
+	return &exitCodeError{
+		code: code,
+		err:  fmt.Errorf("%d of %d devices failed", len(failed), len(endpoints)),
+	}
+}
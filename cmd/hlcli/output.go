@@ -0,0 +1,320 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"gopkg.in/yaml.v3"
+)
+
+// outputFormats are the supported output formats.
+var outputFormats = map[string]func(io.Writer, interface{}) error{
+	"json":  writeJSON,
+	"yaml":  writeYAML,
+	"xml":   writeXML,
+	"table": writeTable,
+}
+
+// writeOutput writes v to w in the specified format. When query is not
+// empty, only the value selected by the query is written, with simple values
+// written as is.
+func writeOutput(w io.Writer, format, query string, v interface{}) error {
+	f, ok := outputFormats[format]
+	if !ok {
+		return fmt.Errorf("unsupported output format %q", format)
+	}
+	if query != "" {
+		var err error
+		if v, err = selectPath(v, query); err != nil {
+			return err
+		}
+		switch v.(type) {
+		case map[string]interface{}, []interface{}:
+		case nil:
+			_, err := fmt.Fprintln(w)
+			return err
+		default:
+			_, err := fmt.Fprintln(w, v)
+			return err
+		}
+	}
+	return f(w, v)
+}
+
+// selectPath selects the value in v using a dotted path, where each
+// component is either a map key (case-insensitive when there is no exact
+// match) or a list index (ie, Messages.Message.0.Phone).
+func selectPath(v interface{}, path string) (interface{}, error) {
+	v, err := normalize(v)
+	if err != nil {
+		return nil, err
+	}
+	for _, k := range strings.Split(path, ".") {
+		switch x := v.(type) {
+		case map[string]interface{}:
+			z, ok := x[k]
+			if !ok {
+				for key, val := range x {
+					if strings.EqualFold(key, k) {
+						z, ok = val, true
+						break
+					}
+				}
+			}
+			if !ok {
+				return nil, fmt.Errorf("%s: no such key %q", path, k)
+			}
+			v = z
+		case []interface{}:
+			i, err := strconv.Atoi(k)
+			if err != nil || i < 0 || i >= len(x) {
+				return nil, fmt.Errorf("%s: invalid index %q", path, k)
+			}
+			v = x[i]
+		default:
+			return nil, fmt.Errorf("%s: cannot select %q from a value", path, k)
+		}
+	}
+	return v, nil
+}
+
+// writeJSON writes v as indented JSON.
+func writeJSON(w io.Writer, v interface{}) error {
+	buf, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(buf, '\n'))
+	return err
+}
+
+// writeYAML writes v as YAML.
+func writeYAML(w io.Writer, v interface{}) error {
+	v, err := normalize(v)
+	if err != nil {
+		return err
+	}
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(v); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+// writeXML writes v as indented XML, with a <response> root element, as
+// returned by the device.
+func writeXML(w io.Writer, v interface{}) error {
+	v, err := normalize(v)
+	if err != nil {
+		return err
+	}
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		m = map[string]interface{}{"item": v}
+	}
+	buf := new(bytes.Buffer)
+	if err := encodeXML(buf, "", "response", m); err != nil {
+		return err
+	}
+	_, err = w.Write(buf.Bytes())
+	return err
+}
+
+// encodeXML encodes v as indented XML elements named name, with map keys in
+// sorted order, attributes keyed by their name prefixed with a -, and lists
+// encoded as repeated elements.
+func encodeXML(buf *bytes.Buffer, indent, name string, v interface{}) error {
+	switch x := v.(type) {
+	case []interface{}:
+		for _, z := range x {
+			if err := encodeXML(buf, indent, name, z); err != nil {
+				return err
+			}
+		}
+		return nil
+	case map[string]interface{}:
+		var keys []string
+		buf.WriteString(indent + "<" + name)
+		for k := range x {
+			switch {
+			case k == "#text":
+			case strings.HasPrefix(k, "-"):
+				buf.WriteString(" " + k[1:] + `="`)
+				if err := xml.EscapeText(buf, []byte(xmlText(x[k]))); err != nil {
+					return err
+				}
+				buf.WriteString(`"`)
+			default:
+				keys = append(keys, k)
+			}
+		}
+		text, ok := x["#text"]
+		if len(keys) == 0 && !ok {
+			buf.WriteString("/>\n")
+			return nil
+		}
+		buf.WriteString(">")
+		if ok {
+			if err := xml.EscapeText(buf, []byte(xmlText(text))); err != nil {
+				return err
+			}
+		}
+		if len(keys) != 0 {
+			buf.WriteString("\n")
+			sort.Strings(keys)
+			for _, k := range keys {
+				if err := encodeXML(buf, indent+"  ", k, x[k]); err != nil {
+					return err
+				}
+			}
+			buf.WriteString(indent)
+		}
+		buf.WriteString("</" + name + ">\n")
+		return nil
+	case nil:
+		buf.WriteString(indent + "<" + name + "/>\n")
+		return nil
+	}
+	buf.WriteString(indent + "<" + name + ">")
+	if err := xml.EscapeText(buf, []byte(xmlText(v))); err != nil {
+		return err
+	}
+	buf.WriteString("</" + name + ">\n")
+	return nil
+}
+
+// xmlText returns the text of a simple (normalized) value.
+func xmlText(v interface{}) string {
+	if f, ok := v.(float64); ok {
+		return strconv.FormatFloat(f, 'f', -1, 64)
+	}
+	return fmt.Sprint(v)
+}
+
+// writeTable writes v as a table. List-like values (ie, SMS lists, host
+// lists) are written as rows, with a column per field, while other values are
+// flattened and written as aligned key/value columns.
+func writeTable(w io.Writer, v interface{}) error {
+	v, err := normalize(v)
+	if err != nil {
+		return err
+	}
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	if rows := findRows(v); rows != nil {
+		// collect columns, in order of appearance
+		var cols []string
+		seen := make(map[string]bool)
+		var flat []map[string]string
+		for _, row := range rows {
+			m := make(map[string]string)
+			flatten(m, "", row)
+			var keys []string
+			for k := range m {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			for _, k := range keys {
+				if !seen[k] {
+					cols, seen[k] = append(cols, k), true
+				}
+			}
+			flat = append(flat, m)
+		}
+		fmt.Fprintln(tw, strings.Join(cols, "\t"))
+		for _, m := range flat {
+			vals := make([]string, len(cols))
+			for i, c := range cols {
+				vals[i] = m[c]
+			}
+			fmt.Fprintln(tw, strings.Join(vals, "\t"))
+		}
+		return tw.Flush()
+	}
+	m := make(map[string]string)
+	flatten(m, "", v)
+	if s, ok := m[""]; ok && len(m) == 1 {
+		_, err := fmt.Fprintln(w, s)
+		return err
+	}
+	var keys []string
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Fprintf(tw, "%s\t%s\n", k, m[k])
+	}
+	return tw.Flush()
+}
+
+// normalize converts v to generic maps, lists and scalars, by round tripping
+// through JSON.
+func normalize(v interface{}) (interface{}, error) {
+	buf, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var z interface{}
+	if err := json.Unmarshal(buf, &z); err != nil {
+		return nil, err
+	}
+	return z, nil
+}
+
+// findRows finds the first list of maps in v, searching nested maps (ie,
+// <Messages><Message>...</Message></Messages>) in key order.
+func findRows(v interface{}) []interface{} {
+	switch x := v.(type) {
+	case []interface{}:
+		for _, z := range x {
+			if _, ok := z.(map[string]interface{}); !ok {
+				return nil
+			}
+		}
+		return x
+	case map[string]interface{}:
+		var keys []string
+		for k := range x {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			if rows := findRows(x[k]); rows != nil {
+				return rows
+			}
+		}
+	}
+	return nil
+}
+
+// flatten flattens v into m, using dotted keys for nested values.
+func flatten(m map[string]string, prefix string, v interface{}) {
+	join := func(k string) string {
+		if prefix == "" {
+			return k
+		}
+		return prefix + "." + k
+	}
+	switch x := v.(type) {
+	case map[string]interface{}:
+		for k, z := range x {
+			flatten(m, join(k), z)
+		}
+	case []interface{}:
+		for i, z := range x {
+			flatten(m, join(fmt.Sprintf("%d", i)), z)
+		}
+	case nil:
+		m[prefix] = ""
+	default:
+		m[prefix] = fmt.Sprintf("%v", x)
+	}
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kenshaw/hilink"
+)
+
+// param types with special handling.
+var (
+	durationType = reflect.TypeOf(time.Duration(0))
+	bandType     = reflect.TypeOf(hilink.Band(0))
+	nrBandType   = reflect.TypeOf(hilink.NRBand(0))
+)
+
+// callable returns true when the method returns a value and an error, and
+// all of its params can be passed on the command line.
+func callable(m reflect.Method) bool {
+	if m.Type.NumOut() != 2 || !m.Type.Out(1).Implements(errorInterface) {
+		return false
+	}
+	if len(methodParamMap[m.Name]) != m.Type.NumIn()-2 {
+		return false
+	}
+	for i := 2; i < m.Type.NumIn(); i++ {
+		if !paramSupported(m.Type.In(i)) {
+			return false
+		}
+	}
+	return true
+}
+
+// paramSupported returns true when a param of type p can be passed on the
+// command line.
+func paramSupported(p reflect.Type) bool {
+	switch p.Kind() {
+	case reflect.Bool, reflect.Int, reflect.Uint, reflect.String, reflect.Struct:
+		return true
+	case reflect.Slice:
+		return elemSupported(p.Elem())
+	}
+	return p == durationType
+}
+
+// elemSupported returns true when a slice element of type p can be passed on
+// the command line.
+func elemSupported(p reflect.Type) bool {
+	switch p.Kind() {
+	case reflect.Int, reflect.Uint, reflect.Uint64, reflect.String:
+		return true
+	}
+	return false
+}
+
+// paramValue is a flag value for the method params not handled by the flag
+// package: durations, comma separated slices (ie, ...string, or band lists),
+// and structs (as JSON).
+type paramValue struct {
+	v reflect.Value
+}
+
+// String satisfies the flag.Value interface.
+func (p *paramValue) String() string {
+	if p == nil || !p.v.IsValid() || p.v.IsZero() {
+		return ""
+	}
+	return fmt.Sprint(p.v.Interface())
+}
+
+// Set satisfies the flag.Value interface.
+func (p *paramValue) Set(s string) error {
+	switch typ := p.v.Type(); {
+	case typ == durationType:
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return err
+		}
+		p.v.SetInt(int64(d))
+	case typ.Kind() == reflect.Slice:
+		for _, z := range strings.Split(s, ",") {
+			if z = strings.TrimSpace(z); z == "" {
+				continue
+			}
+			v, err := parseElem(typ.Elem(), z)
+			if err != nil {
+				return err
+			}
+			p.v.Set(reflect.Append(p.v, v))
+		}
+	case typ.Kind() == reflect.Struct:
+		return json.Unmarshal([]byte(s), p.v.Addr().Interface())
+	default:
+		return fmt.Errorf("unsupported type %s", typ)
+	}
+	return nil
+}
+
+// parseElem parses a slice element of type typ. Bands are parsed as band
+// numbers (ie, 3 or b3 for Band3, and 78 or n78 for NRBand78).
+func parseElem(typ reflect.Type, s string) (reflect.Value, error) {
+	v := reflect.New(typ).Elem()
+	switch {
+	case typ == bandType:
+		n, err := strconv.Atoi(strings.TrimPrefix(strings.ToLower(s), "b"))
+		if err != nil || hilink.BandN(n) == 0 {
+			return v, fmt.Errorf("invalid band %q", s)
+		}
+		v.SetUint(uint64(hilink.BandN(n)))
+	case typ == nrBandType:
+		n, err := strconv.Atoi(strings.TrimPrefix(strings.ToLower(s), "n"))
+		if err != nil || n < 1 {
+			return v, fmt.Errorf("invalid nr band %q", s)
+		}
+		v.SetInt(int64(n))
+	case typ.Kind() == reflect.String:
+		v.SetString(s)
+	case typ.Kind() == reflect.Int:
+		i, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return v, err
+		}
+		v.SetInt(i)
+	default:
+		i, err := strconv.ParseUint(s, 10, 64)
+		if err != nil {
+			return v, err
+		}
+		v.SetUint(i)
+	}
+	return v, nil
+}
+
+// paramUsage returns the usage for a param of type p (ie, the type name for
+// the help output).
+func paramUsage(p reflect.Type) string {
+	switch p.Kind() {
+	case reflect.Slice:
+		return p.Elem().String() + ",..."
+	case reflect.Struct:
+		return p.String() + " (json)"
+	}
+	return p.String()
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/kenshaw/hilink"
+)
+
+// MethodSchema describes a method.
+type MethodSchema struct {
+	Name    string        `json:"name"`
+	Command string        `json:"command"`
+	Doc     string        `json:"doc"`
+	Params  []ParamSchema `json:"params"`
+	Returns string        `json:"returns"`
+}
+
+// ParamSchema describes a method param.
+type ParamSchema struct {
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	Kind     string `json:"kind"`
+	Variadic bool   `json:"variadic,omitempty"`
+}
+
+// doSchema writes a JSON description of the methods to w.
+func doSchema(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(methodSchemas())
+}
+
+// methodSchemas returns the schemas for the methods that can be called from
+// the command line, sorted by name.
+func methodSchemas() []MethodSchema {
+	typ := reflect.TypeOf(&hilink.Client{})
+	var l []MethodSchema
+	for i := 0; i < typ.NumMethod(); i++ {
+		m := typ.Method(i)
+		if !callable(m) {
+			continue
+		}
+		params := methodParamMap[m.Name]
+		s := MethodSchema{
+			Name:    m.Name,
+			Command: strings.ToLower(m.Name),
+			Doc:     methodCommentMap[m.Name],
+			Params:  []ParamSchema{},
+			Returns: m.Type.Out(0).String(),
+		}
+		for j := 2; j < m.Type.NumIn(); j++ {
+			p := ParamSchema{
+				Name:     params[j-2],
+				Type:     m.Type.In(j).String(),
+				Kind:     m.Type.In(j).Kind().String(),
+				Variadic: m.Type.IsVariadic() && j == m.Type.NumIn()-1,
+			}
+			s.Params = append(s.Params, p)
+		}
+		l = append(l, s)
+	}
+	sort.Slice(l, func(i, j int) bool {
+		return l[i].Name < l[j].Name
+	})
+	return l
+}
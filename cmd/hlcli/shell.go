@@ -0,0 +1,346 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/kenshaw/hilink"
+)
+
+// doShell runs an interactive shell, executing methods using a single
+// authenticated session.
+func doShell(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("shell", flag.ExitOnError)
+	g := addGlobalFlags(fs)
+	fs.Parse(args)
+	cfg, err := g.settings(fs)
+	if err != nil {
+		return err
+	}
+	cl, err := newClient(cfg, *g.debug)
+	if err != nil {
+		return err
+	}
+	typ := reflect.TypeOf(cl)
+	// history
+	var history []string
+	historyFile := filepath.Join(filepath.Dir(configFile()), "history")
+	if buf, err := ioutil.ReadFile(historyFile); err == nil {
+		history = strings.Split(strings.TrimSpace(string(buf)), "\n")
+	}
+	r := newLineReader(os.Stdin, os.Stdout, history, func(line string) []string {
+		return complete(typ, line)
+	})
+	defer r.close()
+	fmt.Fprintf(os.Stdout, "connected to %s, type 'help' for a list of methods, 'exit' to quit\n", cfg.Endpoint)
+	for {
+		line, err := r.readLine("hlcli> ")
+		switch {
+		case err == io.EOF:
+			fmt.Fprintln(os.Stdout)
+			return nil
+		case err != nil:
+			return err
+		}
+		args, err := splitArgs(line)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error: %v\n", err)
+			continue
+		}
+		if len(args) == 0 {
+			continue
+		}
+		r.addHistory(line)
+		appendHistory(historyFile, line)
+		switch args[0] {
+		case "exit", "quit":
+			return nil
+		case "help", "list":
+			if len(args) > 1 {
+				doHelpMethodParams(args[1])
+			} else {
+				doHelpMethods()
+			}
+			continue
+		}
+		if err := shellCall(ctx, cl, typ, args, cfg.Output); err != nil {
+			fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		}
+	}
+}
+
+// shellCall parses the args and calls the method.
+func shellCall(ctx context.Context, cl *hilink.Client, typ reflect.Type, args []string, output string) error {
+	method, err := findMethod(typ, args[0])
+	if err != nil {
+		return err
+	}
+	fs := flag.NewFlagSet(method.Name, flag.ContinueOnError)
+	o := fs.String("o", output, "output format (json, yaml, xml, table)")
+	q := fs.String("q", "", "select a value using a dotted path")
+	in, err := addMethodFlags(fs, method)
+	if err != nil {
+		return err
+	}
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+	convertArgs(method, in)
+	return call(ctx, os.Stdout, cl, method, in, *o, *q)
+}
+
+// complete returns the completions for the line, completing method names for
+// the first word, and method params for subsequent words.
+func complete(typ reflect.Type, line string) []string {
+	words := strings.Fields(line)
+	if len(words) == 0 || (len(words) == 1 && !strings.HasSuffix(line, " ")) {
+		prefix := ""
+		if len(words) == 1 {
+			prefix = strings.ToLower(words[0])
+		}
+		var l []string
+		for _, name := range append(methodNames(typ), "exit", "help") {
+			if strings.HasPrefix(name, prefix) {
+				l = append(l, name)
+			}
+		}
+		sort.Strings(l)
+		return l
+	}
+	method, err := findMethod(typ, words[0])
+	if err != nil {
+		return nil
+	}
+	prefix := ""
+	if !strings.HasSuffix(line, " ") {
+		prefix = words[len(words)-1]
+	}
+	var l []string
+	for _, n := range append(methodParamMap[method.Name], "o", "q") {
+		if name := "-" + n + "="; strings.HasPrefix(name, prefix) {
+			l = append(l, name)
+		}
+	}
+	return l
+}
+
+// methodNames returns the lower case names of the callable methods.
+func methodNames(typ reflect.Type) []string {
+	var l []string
+	for i := 0; i < typ.NumMethod(); i++ {
+		m := typ.Method(i)
+		if callable(m) {
+			l = append(l, strings.ToLower(m.Name))
+		}
+	}
+	return l
+}
+
+// splitArgs splits a line into args, handling single and double quotes.
+func splitArgs(line string) ([]string, error) {
+	var args []string
+	var arg strings.Builder
+	inArg, quote := false, rune(0)
+	for _, c := range line {
+		switch {
+		case quote != 0 && c == quote:
+			quote = 0
+		case quote != 0:
+			arg.WriteRune(c)
+		case c == '\'' || c == '"':
+			quote, inArg = c, true
+		case unicode.IsSpace(c):
+			if inArg {
+				args, inArg = append(args, arg.String()), false
+				arg.Reset()
+			}
+		default:
+			arg.WriteRune(c)
+			inArg = true
+		}
+	}
+	if quote != 0 {
+		return nil, errors.New("unterminated quote")
+	}
+	if inArg {
+		args = append(args, arg.String())
+	}
+	return args, nil
+}
+
+// appendHistory appends the line to the history file.
+func appendHistory(name, line string) {
+	if err := os.MkdirAll(filepath.Dir(name), 0o700); err != nil {
+		return
+	}
+	f, err := os.OpenFile(name, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	fmt.Fprintln(f, line)
+}
+
+// lineReader reads lines from a terminal, with tab completion and history.
+// When the input is not a terminal (or stty is not available), lines are read
+// without editing support.
+type lineReader struct {
+	in       *bufio.Reader
+	out      io.Writer
+	history  []string
+	complete func(string) []string
+	state    string
+}
+
+// newLineReader creates a new line reader, putting the terminal into
+// non-canonical mode.
+func newLineReader(in *os.File, out io.Writer, history []string, complete func(string) []string) *lineReader {
+	r := &lineReader{
+		in:       bufio.NewReader(in),
+		out:      out,
+		history:  history,
+		complete: complete,
+	}
+	if state, err := stty(in, "-g"); err == nil {
+		if _, err := stty(in, "-icanon", "-echo", "min", "1"); err == nil {
+			r.state = strings.TrimSpace(state)
+		}
+	}
+	return r
+}
+
+// stty runs stty with the args on the terminal.
+func stty(in *os.File, args ...string) (string, error) {
+	cmd := exec.Command("stty", args...)
+	cmd.Stdin = in
+	out, err := cmd.Output()
+	return string(out), err
+}
+
+// close restores the terminal state.
+func (r *lineReader) close() {
+	if r.state != "" {
+		stty(os.Stdin, r.state)
+	}
+}
+
+// addHistory adds the line to the history.
+func (r *lineReader) addHistory(line string) {
+	if n := len(r.history); n == 0 || r.history[n-1] != line {
+		r.history = append(r.history, line)
+	}
+}
+
+// readLine reads a line.
+func (r *lineReader) readLine(prompt string) (string, error) {
+	fmt.Fprint(r.out, prompt)
+	if r.state == "" {
+		line, err := r.in.ReadString('\n')
+		if err == io.EOF && line != "" {
+			err = nil
+		}
+		return strings.TrimSpace(line), err
+	}
+	var line []rune
+	pos := len(r.history)
+	redraw := func() {
+		fmt.Fprint(r.out, "\r\033[K"+prompt+string(line))
+	}
+	for {
+		c, _, err := r.in.ReadRune()
+		if err != nil {
+			return "", err
+		}
+		switch c {
+		case '\r', '\n':
+			fmt.Fprintln(r.out)
+			return string(line), nil
+		case 4: // ctrl-d
+			if len(line) == 0 {
+				return "", io.EOF
+			}
+		case 21: // ctrl-u
+			line = line[:0]
+			redraw()
+		case 127, '\b':
+			if len(line) != 0 {
+				line = line[:len(line)-1]
+				fmt.Fprint(r.out, "\b \b")
+			}
+		case '\t':
+			l := r.complete(string(line))
+			switch {
+			case len(l) == 1:
+				line = append(line[:len(line)-len(lastWord(line))], []rune(l[0])...)
+				if !strings.HasSuffix(l[0], "=") {
+					line = append(line, ' ')
+				}
+			case len(l) > 1:
+				if p := commonPrefix(l); len(p) > len(lastWord(line)) {
+					line = append(line[:len(line)-len(lastWord(line))], []rune(p)...)
+				} else {
+					fmt.Fprintln(r.out)
+					fmt.Fprintln(r.out, strings.Join(l, "  "))
+				}
+			}
+			redraw()
+		case 27: // escape sequence
+			if b, _ := r.in.ReadByte(); b != '[' {
+				continue
+			}
+			switch b, _ := r.in.ReadByte(); b {
+			case 'A': // up
+				if pos > 0 {
+					pos--
+					line = []rune(r.history[pos])
+				}
+			case 'B': // down
+				if pos < len(r.history) {
+					pos++
+				}
+				line = nil
+				if pos < len(r.history) {
+					line = []rune(r.history[pos])
+				}
+			}
+			redraw()
+		default:
+			if unicode.IsPrint(c) {
+				line = append(line, c)
+				fmt.Fprint(r.out, string(c))
+			}
+		}
+	}
+}
+
+// lastWord returns the last (partial) word of the line.
+func lastWord(line []rune) []rune {
+	i := len(line)
+	for i > 0 && !unicode.IsSpace(line[i-1]) {
+		i--
+	}
+	return line[i:]
+}
+
+// commonPrefix returns the common prefix of the strings.
+func commonPrefix(l []string) string {
+	p := l[0]
+	for _, s := range l[1:] {
+		for !strings.HasPrefix(s, p) {
+			p = p[:len(p)-1]
+		}
+	}
+	return p
+}
@@ -0,0 +1,347 @@
+// Command hmqtt is a MQTT bridge for Huawei Hilink devices.
+//
+// hmqtt periodically publishes the device state (signal, connection status,
+// data usage and SMS counts) as JSON to <prefix>/<id>/state, and sends SMS
+// messages published to <prefix>/<id>/sms/send. The payload of a SMS message
+// is either the message text (sent to the -sms-to recipients), or a JSON
+// object with to and message fields.
+//
+// With -discovery (the default), Home Assistant MQTT discovery configs are
+// published, so that the device sensors and a SMS notify entity appear
+// automatically in Home Assistant.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/kenshaw/hilink"
+)
+
+func main() {
+	endpoint := flag.String("endpoint", "http://192.168.8.1/", "api endpoint")
+	deviceUsername := flag.String("device-username", "", "device username")
+	devicePassword := flag.String("device-password", "", "device password")
+	debug := flag.Bool("v", false, "enable verbose")
+	broker := flag.String("broker", "tcp://localhost:1883", "mqtt broker url")
+	username := flag.String("username", "", "mqtt username")
+	password := flag.String("password", "", "mqtt password")
+	id := flag.String("id", "", "device id used in topics (default the device serial number)")
+	prefix := flag.String("prefix", "hilink", "topic prefix")
+	discovery := flag.Bool("discovery", true, "publish home assistant discovery configs")
+	discoveryPrefix := flag.String("discovery-prefix", "homeassistant", "home assistant discovery prefix")
+	smsTo := flag.String("sms-to", "", "default sms recipients, comma separated")
+	interval := flag.Duration("interval", 30*time.Second, "poll interval")
+	flag.Parse()
+	b := &bridge{
+		id:              *id,
+		prefix:          *prefix,
+		discovery:       *discovery,
+		discoveryPrefix: *discoveryPrefix,
+	}
+	for _, to := range strings.Split(*smsTo, ",") {
+		if to = strings.TrimSpace(to); to != "" {
+			b.smsTo = append(b.smsTo, to)
+		}
+	}
+	opts := mqttOptions{
+		username:  *username,
+		password:  *password,
+		keepAlive: 60 * time.Second,
+	}
+	if err := run(context.Background(), *endpoint, *deviceUsername, *devicePassword, *debug, *broker, opts, *interval, b); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, endpoint, deviceUsername, devicePassword string, debug bool, broker string, opts mqttOptions, interval time.Duration, b *bridge) error {
+	// options
+	clOpts := []hilink.ClientOption{
+		hilink.WithURL(endpoint),
+		hilink.WithAuth(deviceUsername, devicePassword),
+		// start the session and log in on the first request
+		hilink.WithNoStart(true),
+	}
+	if debug {
+		clOpts = append(clOpts, hilink.WithLogf(log.Printf))
+	}
+	// create client
+	b.cl = hilink.NewClient(clOpts...)
+	if err := b.init(ctx, endpoint); err != nil {
+		return err
+	}
+	opts.clientID = "hmqtt-" + b.id
+	opts.willTopic, opts.willPayload = b.topic("availability"), "offline"
+	// monitor
+	m := hilink.NewMonitor(b.cl, interval, hilink.WithSources(
+		hilink.MonitorSignal|hilink.MonitorStatus|hilink.MonitorMonth|hilink.MonitorSms,
+	))
+	go func() {
+		for err := range m.Errors() {
+			log.Printf("poll failed: %v", err)
+		}
+	}()
+	go m.Run(ctx)
+	// publish, reconnecting to the broker when the connection is lost
+	for {
+		c, err := dialMQTT(ctx, broker, opts)
+		if err == nil {
+			log.Printf("connected to %s, publishing to %s", broker, b.topic("state"))
+			err = b.serve(ctx, c, m.Snapshots())
+			c.Close()
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		log.Printf("mqtt: %v, reconnecting", err)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(10 * time.Second):
+		}
+	}
+}
+
+// bridge publishes device state to a MQTT broker.
+type bridge struct {
+	cl              *hilink.Client
+	id              string
+	prefix          string
+	discovery       bool
+	discoveryPrefix string
+	smsTo           []string
+	device          map[string]interface{}
+	last            []byte
+}
+
+// idRE matches characters not allowed in ids.
+var idRE = regexp.MustCompile(`[^a-zA-Z0-9_-]+`)
+
+// init retrieves the device information, used for the device id and the
+// discovery device config.
+func (b *bridge) init(ctx context.Context, endpoint string) error {
+	b.device = map[string]interface{}{
+		"manufacturer": "Huawei",
+	}
+	info, err := b.cl.DeviceInfo(ctx)
+	if err != nil {
+		log.Printf("unable to retrieve device information: %v", err)
+	}
+	if b.id == "" {
+		for _, k := range []string{"SerialNumber", "Imei"} {
+			if s, _ := info[k].(string); s != "" {
+				b.id = s
+				break
+			}
+		}
+	}
+	if b.id == "" {
+		u, err := url.Parse(endpoint)
+		if err != nil {
+			return err
+		}
+		b.id = u.Hostname()
+	}
+	b.id = strings.ToLower(idRE.ReplaceAllString(b.id, "_"))
+	name := "Hilink " + b.id
+	if s, _ := info["DeviceName"].(string); s != "" {
+		name, b.device["model"] = s, s
+	}
+	if s, _ := info["SoftwareVersion"].(string); s != "" {
+		b.device["sw_version"] = s
+	}
+	b.device["name"] = name
+	b.device["identifiers"] = []string{"hilink_" + b.id}
+	return nil
+}
+
+// topic returns the topic for the device.
+func (b *bridge) topic(name string) string {
+	return b.prefix + "/" + b.id + "/" + name
+}
+
+// serve publishes the discovery configs and device state until the broker
+// connection is lost.
+func (b *bridge) serve(ctx context.Context, c *mqttClient, snapshots <-chan hilink.Snapshot) error {
+	if b.discovery {
+		for _, e := range entities {
+			if err := c.Publish(b.discoveryTopic(e), b.discoveryConfig(e), true); err != nil {
+				return err
+			}
+		}
+	}
+	if err := c.Subscribe(b.topic("sms/send"), func(payload []byte) {
+		go b.sendSms(ctx, payload)
+	}); err != nil {
+		return err
+	}
+	if err := c.Publish(b.topic("availability"), []byte("online"), true); err != nil {
+		return err
+	}
+	// republish last state after reconnecting
+	if b.last != nil {
+		if err := c.Publish(b.topic("state"), b.last, true); err != nil {
+			return err
+		}
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			c.Publish(b.topic("availability"), []byte("offline"), true)
+			return ctx.Err()
+		case <-c.Done():
+			return c.Err()
+		case snap, ok := <-snapshots:
+			if !ok {
+				return nil
+			}
+			buf, err := json.Marshal(state(snap))
+			if err != nil {
+				return err
+			}
+			if err := c.Publish(b.topic("state"), buf, true); err != nil {
+				return err
+			}
+			b.last = buf
+		}
+	}
+}
+
+// sendSms sends a SMS message published to the sms topic.
+func (b *bridge) sendSms(ctx context.Context, payload []byte) {
+	msg, to := string(payload), b.smsTo
+	var v struct {
+		To      interface{} `json:"to"`
+		Message string      `json:"message"`
+	}
+	if err := json.Unmarshal(payload, &v); err == nil && v.Message != "" {
+		msg, to = v.Message, nil
+		switch x := v.To.(type) {
+		case string:
+			to = append(to, x)
+		case []interface{}:
+			for _, z := range x {
+				if s, ok := z.(string); ok {
+					to = append(to, s)
+				}
+			}
+		}
+	}
+	if len(to) == 0 {
+		log.Printf("sms: no recipients for message %q", msg)
+		return
+	}
+	ok, err := b.cl.SmsSend(ctx, msg, to...)
+	switch {
+	case err != nil:
+		log.Printf("sms: unable to send to %s: %v", strings.Join(to, ","), err)
+	case !ok:
+		log.Printf("sms: unable to send to %s", strings.Join(to, ","))
+	default:
+		log.Printf("sms: sent to %s", strings.Join(to, ","))
+	}
+}
+
+// state returns the state values for a snapshot.
+func state(snap hilink.Snapshot) map[string]interface{} {
+	m := map[string]interface{}{
+		"time": snap.Time.Format(time.RFC3339),
+	}
+	if s := snap.Signal; s != nil {
+		m["rsrp"], m["rsrq"], m["sinr"], m["rssi"] = s.RSRP, s.RSRQ, s.SINR, s.RSSI
+		m["band"], m["cell_id"], m["plmn"] = s.Band, s.CellID, s.PLMN
+	}
+	if s := snap.Status; s != nil {
+		m["connected"] = onOff(s.Connected())
+		m["roaming"] = onOff(s.Roaming)
+		m["network_type"] = s.NetworkType
+		m["signal_icon"] = s.SignalIcon
+		m["wan_ip"] = s.WanIPAddress
+	}
+	if s := snap.Month; s != nil {
+		m["month_download"], m["month_upload"], m["month_total"] = s.Download, s.Upload, s.Total()
+	}
+	if s := snap.Sms; s != nil {
+		m["sms_unread"] = s.LocalUnread + s.SimUnread
+	}
+	return m
+}
+
+// onOff returns ON or OFF for b.
+func onOff(b bool) string {
+	if b {
+		return "ON"
+	}
+	return "OFF"
+}
+
+// entity is a Home Assistant entity.
+type entity struct {
+	component   string
+	key         string
+	name        string
+	unit        string
+	deviceClass string
+	stateClass  string
+	icon        string
+}
+
+// entities are the published Home Assistant entities.
+var entities = []entity{
+	{"sensor", "rsrp", "RSRP", "dBm", "signal_strength", "measurement", ""},
+	{"sensor", "rsrq", "RSRQ", "dB", "", "measurement", "mdi:signal"},
+	{"sensor", "sinr", "SINR", "dB", "", "measurement", "mdi:signal"},
+	{"sensor", "rssi", "RSSI", "dBm", "signal_strength", "measurement", ""},
+	{"sensor", "band", "Band", "", "", "", "mdi:radio-tower"},
+	{"sensor", "signal_icon", "Signal bars", "", "", "measurement", "mdi:signal-cellular-3"},
+	{"sensor", "wan_ip", "WAN IP address", "", "", "", "mdi:ip-network"},
+	{"sensor", "month_download", "Month download", "B", "data_size", "total_increasing", ""},
+	{"sensor", "month_upload", "Month upload", "B", "data_size", "total_increasing", ""},
+	{"sensor", "month_total", "Month usage", "B", "data_size", "total_increasing", ""},
+	{"sensor", "sms_unread", "Unread SMS", "", "", "measurement", "mdi:message-text"},
+	{"binary_sensor", "connected", "Connected", "", "connectivity", "", ""},
+	{"binary_sensor", "roaming", "Roaming", "", "", "", "mdi:earth"},
+	{"notify", "sms", "SMS", "", "", "", "mdi:message-arrow-right"},
+}
+
+// discoveryTopic returns the discovery config topic for the entity.
+func (b *bridge) discoveryTopic(e entity) string {
+	return b.discoveryPrefix + "/" + e.component + "/hilink_" + b.id + "/" + e.key + "/config"
+}
+
+// discoveryConfig returns the discovery config for the entity.
+func (b *bridge) discoveryConfig(e entity) []byte {
+	m := map[string]interface{}{
+		"name":               e.name,
+		"unique_id":          "hilink_" + b.id + "_" + e.key,
+		"availability_topic": b.topic("availability"),
+		"device":             b.device,
+	}
+	if e.component == "notify" {
+		m["command_topic"] = b.topic("sms/send")
+	} else {
+		m["state_topic"] = b.topic("state")
+		m["value_template"] = "{{ value_json." + e.key + " }}"
+	}
+	for k, v := range map[string]string{
+		"unit_of_measurement": e.unit,
+		"device_class":        e.deviceClass,
+		"state_class":         e.stateClass,
+		"icon":                e.icon,
+	} {
+		if v != "" {
+			m[k] = v
+		}
+	}
+	buf, _ := json.Marshal(m)
+	return buf
+}
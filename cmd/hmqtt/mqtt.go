@@ -0,0 +1,280 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// MQTT 3.1.1 packet types.
+const (
+	mqttConnect    = 1
+	mqttConnack    = 2
+	mqttPublish    = 3
+	mqttSubscribe  = 8
+	mqttSuback     = 9
+	mqttPingreq    = 12
+	mqttPingresp   = 13
+	mqttDisconnect = 14
+)
+
+// mqttOptions are the MQTT connection options.
+type mqttOptions struct {
+	clientID  string
+	username  string
+	password  string
+	keepAlive time.Duration
+	// willTopic and willPayload are the retained last will message, published
+	// by the broker when the connection is lost.
+	willTopic   string
+	willPayload string
+}
+
+// mqttClient is a minimal MQTT 3.1.1 client, supporting QoS 0 publish and
+// subscribe only.
+type mqttClient struct {
+	conn     net.Conn
+	r        *bufio.Reader
+	handlers map[string]func([]byte)
+	done     chan struct{}
+	err      error
+	sync.Mutex
+}
+
+// dialMQTT connects to the broker (ie, tcp://localhost:1883 or
+// tls://broker:8883).
+func dialMQTT(ctx context.Context, broker string, opts mqttOptions) (*mqttClient, error) {
+	u, err := url.Parse(broker)
+	if err != nil {
+		return nil, err
+	}
+	d := &net.Dialer{Timeout: 10 * time.Second}
+	var conn net.Conn
+	switch u.Scheme {
+	case "tcp", "mqtt":
+		conn, err = d.DialContext(ctx, "tcp", hostPort(u.Host, "1883"))
+	case "tls", "ssl", "mqtts":
+		conn, err = tls.DialWithDialer(d, "tcp", hostPort(u.Host, "8883"), &tls.Config{
+			ServerName: u.Hostname(),
+		})
+	default:
+		return nil, fmt.Errorf("unsupported broker scheme %q", u.Scheme)
+	}
+	if err != nil {
+		return nil, err
+	}
+	c := &mqttClient{
+		conn:     conn,
+		r:        bufio.NewReader(conn),
+		handlers: make(map[string]func([]byte)),
+		done:     make(chan struct{}),
+	}
+	if err := c.connect(opts); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	go c.readLoop()
+	go c.pingLoop(opts.keepAlive)
+	return c, nil
+}
+
+// hostPort adds the default port to host when it does not have one.
+func hostPort(host, port string) string {
+	if _, _, err := net.SplitHostPort(host); err == nil {
+		return host
+	}
+	return net.JoinHostPort(host, port)
+}
+
+// connect sends the connect packet, and waits for the broker to acknowledge
+// it.
+func (c *mqttClient) connect(opts mqttOptions) error {
+	var flags byte = 0x02 // clean session
+	payload := mqttString(opts.clientID)
+	if opts.willTopic != "" {
+		flags |= 0x04 | 0x20 // will, will retain
+		payload = append(payload, mqttString(opts.willTopic)...)
+		payload = append(payload, mqttString(opts.willPayload)...)
+	}
+	if opts.username != "" {
+		flags |= 0x80
+		payload = append(payload, mqttString(opts.username)...)
+	}
+	if opts.password != "" {
+		flags |= 0x40
+		payload = append(payload, mqttString(opts.password)...)
+	}
+	buf := append(mqttString("MQTT"), 4, flags, 0, 0)
+	binary.BigEndian.PutUint16(buf[len(buf)-2:], uint16(opts.keepAlive/time.Second))
+	if err := c.write(mqttConnect<<4, append(buf, payload...)); err != nil {
+		return err
+	}
+	// read connack
+	c.conn.SetReadDeadline(time.Now().Add(10 * time.Second))
+	defer c.conn.SetReadDeadline(time.Time{})
+	typ, body, err := c.read()
+	switch {
+	case err != nil:
+		return err
+	case typ>>4 != mqttConnack || len(body) != 2:
+		return errors.New("mqtt: invalid connack")
+	case body[1] != 0:
+		return fmt.Errorf("mqtt: connection refused (code %d)", body[1])
+	}
+	return nil
+}
+
+// Publish publishes the payload to the topic.
+func (c *mqttClient) Publish(topic string, payload []byte, retain bool) error {
+	var flags byte
+	if retain {
+		flags = 0x01
+	}
+	return c.write(mqttPublish<<4|flags, append(mqttString(topic), payload...))
+}
+
+// Subscribe subscribes to the topic, calling f with the payload of received
+// messages. Wildcard topics are not supported.
+func (c *mqttClient) Subscribe(topic string, f func([]byte)) error {
+	c.Lock()
+	c.handlers[topic] = f
+	c.Unlock()
+	buf := append([]byte{0, 1}, mqttString(topic)...)
+	return c.write(mqttSubscribe<<4|0x02, append(buf, 0))
+}
+
+// Done returns a channel that is closed when the connection is lost.
+func (c *mqttClient) Done() <-chan struct{} {
+	return c.done
+}
+
+// Err returns the error that caused the connection to be lost.
+func (c *mqttClient) Err() error {
+	<-c.done
+	return c.err
+}
+
+// Close disconnects from the broker.
+func (c *mqttClient) Close() error {
+	c.write(mqttDisconnect<<4, nil)
+	return c.conn.Close()
+}
+
+// readLoop reads packets until the connection is lost, dispatching published
+// messages to the subscription handlers.
+func (c *mqttClient) readLoop() {
+	defer close(c.done)
+	for {
+		typ, body, err := c.read()
+		if err != nil {
+			c.err = err
+			return
+		}
+		if typ>>4 != mqttPublish || len(body) < 2 {
+			continue
+		}
+		n := int(binary.BigEndian.Uint16(body))
+		if len(body) < 2+n {
+			continue
+		}
+		topic, payload := string(body[2:2+n]), body[2+n:]
+		if (typ>>1)&0x03 != 0 {
+			// skip packet id for qos > 0
+			if len(payload) < 2 {
+				continue
+			}
+			payload = payload[2:]
+		}
+		c.Lock()
+		f := c.handlers[topic]
+		c.Unlock()
+		if f != nil {
+			f(payload)
+		}
+	}
+}
+
+// pingLoop sends a ping request every half of the keep alive interval.
+func (c *mqttClient) pingLoop(keepAlive time.Duration) {
+	if keepAlive == 0 {
+		return
+	}
+	t := time.NewTicker(keepAlive / 2)
+	defer t.Stop()
+	for {
+		select {
+		case <-c.done:
+			return
+		case <-t.C:
+			if err := c.write(mqttPingreq<<4, nil); err != nil {
+				c.conn.Close()
+				return
+			}
+		}
+	}
+}
+
+// write writes a packet.
+func (c *mqttClient) write(typ byte, body []byte) error {
+	buf := []byte{typ}
+	// remaining length
+	n := len(body)
+	for {
+		b := byte(n % 128)
+		if n /= 128; n > 0 {
+			b |= 0x80
+		}
+		buf = append(buf, b)
+		if n == 0 {
+			break
+		}
+	}
+	c.Lock()
+	defer c.Unlock()
+	_, err := c.conn.Write(append(buf, body...))
+	return err
+}
+
+// read reads a packet, returning the packet type and flags, and the body.
+func (c *mqttClient) read() (byte, []byte, error) {
+	typ, err := c.r.ReadByte()
+	if err != nil {
+		return 0, nil, err
+	}
+	// remaining length
+	n, mul := 0, 1
+	for i := 0; ; i++ {
+		b, err := c.r.ReadByte()
+		if err != nil {
+			return 0, nil, err
+		}
+		if i == 4 {
+			return 0, nil, errors.New("mqtt: invalid remaining length")
+		}
+		n += int(b&0x7f) * mul
+		if b&0x80 == 0 {
+			break
+		}
+		mul *= 128
+	}
+	body := make([]byte, n)
+	if _, err := io.ReadFull(c.r, body); err != nil {
+		return 0, nil, err
+	}
+	return typ, body, nil
+}
+
+// mqttString encodes a length prefixed string.
+func mqttString(s string) []byte {
+	buf := make([]byte, 2, 2+len(s))
+	binary.BigEndian.PutUint16(buf, uint16(len(s)))
+	return append(buf, s...)
+}
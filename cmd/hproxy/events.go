@@ -0,0 +1,156 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kenshaw/hilink"
+)
+
+// event is a server-sent event.
+type event struct {
+	Type string                 `json:"type"`
+	Time time.Time              `json:"time"`
+	Data map[string]interface{} `json:"data"`
+}
+
+// hub polls the device using a monitor, broadcasting events to the /events
+// clients.
+type hub struct {
+	m       *hilink.Monitor
+	clients map[chan event]map[string]bool
+	sync.Mutex
+}
+
+// newHub creates a new event hub, polling the device every interval.
+func newHub(cl *hilink.Client, interval time.Duration) *hub {
+	return &hub{
+		m: hilink.NewMonitor(cl, interval, hilink.WithSources(
+			hilink.MonitorSignal|hilink.MonitorStatus|hilink.MonitorSms,
+		)),
+		clients: make(map[chan event]map[string]bool),
+	}
+}
+
+// run runs the monitor, broadcasting signal events for each snapshot, and
+// typed events for each monitor event.
+func (h *hub) run(ctx context.Context) {
+	events, _ := h.m.Subscribe()
+	go h.m.Run(ctx)
+	snapshots := h.m.Snapshots()
+	for {
+		select {
+		case snap, ok := <-snapshots:
+			if !ok {
+				return
+			}
+			if s := snap.Signal; s != nil {
+				h.broadcast(event{Type: "signal", Time: snap.Time, Data: map[string]interface{}{
+					"rsrp": s.RSRP,
+					"rsrq": s.RSRQ,
+					"sinr": s.SINR,
+					"rssi": s.RSSI,
+					"band": s.Band,
+					"pci":  s.PCI,
+				}})
+			}
+		case ev, ok := <-events:
+			if !ok {
+				return
+			}
+			h.broadcast(convertEvent(ev))
+		}
+	}
+}
+
+// convertEvent converts a monitor event.
+func convertEvent(ev hilink.Event) event {
+	curr := ev.Current
+	e := event{Time: curr.Time, Data: make(map[string]interface{})}
+	switch ev.Type {
+	case hilink.EventConnectionUp, hilink.EventConnectionDown:
+		e.Type = "connection"
+		e.Data["connected"] = curr.Status.Connected()
+		e.Data["connection_status"] = curr.Status.ConnectionStatus
+		e.Data["wan_ip"] = curr.Status.WanIPAddress
+	case hilink.EventNetworkTypeChanged:
+		e.Type = "network"
+		e.Data["network_type"] = curr.Status.NetworkType
+		e.Data["previous"] = ev.Previous.Status.NetworkType
+	case hilink.EventRoamingChanged:
+		e.Type = "roaming"
+		e.Data["roaming"] = curr.Status.Roaming
+	case hilink.EventSmsReceived:
+		e.Type = "sms"
+		e.Data["unread"] = curr.Sms.LocalUnread
+		e.Data["inbox"] = curr.Sms.LocalInbox
+	}
+	return e
+}
+
+// broadcast sends the event to the clients subscribed to the event type,
+// dropping it for clients that are not keeping up.
+func (h *hub) broadcast(e event) {
+	h.Lock()
+	defer h.Unlock()
+	for ch, types := range h.clients {
+		if len(types) != 0 && !types[e.Type] {
+			continue
+		}
+		select {
+		case ch <- e:
+		default:
+		}
+	}
+}
+
+// ServeHTTP streams events to the client as server-sent events. The types
+// query param (ie, types=sms,connection) restricts the streamed event types.
+func (h *hub) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	f, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, fmt.Errorf("streaming not supported"))
+		return
+	}
+	types := make(map[string]bool)
+	for _, typ := range strings.Split(req.URL.Query().Get("types"), ",") {
+		if typ = strings.TrimSpace(typ); typ != "" {
+			types[typ] = true
+		}
+	}
+	ch := make(chan event, 16)
+	h.Lock()
+	h.clients[ch] = types
+	h.Unlock()
+	defer func() {
+		h.Lock()
+		delete(h.clients, ch)
+		h.Unlock()
+	}()
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	f.Flush()
+	ping := time.NewTicker(30 * time.Second)
+	defer ping.Stop()
+	for {
+		select {
+		case <-req.Context().Done():
+			return
+		case <-ping.C:
+			fmt.Fprint(w, ": ping\n\n")
+		case e := <-ch:
+			buf, err := json.Marshal(e)
+			if err != nil {
+				return
+			}
+			fmt.Fprintf(w, "event: %s\ndata: %s\n\n", e.Type, buf)
+		}
+		f.Flush()
+	}
+}
@@ -0,0 +1,287 @@
+// Command hproxy is a JSON REST proxy for Huawei Hilink devices.
+//
+// hproxy exposes the device API as JSON REST endpoints, handling the device
+// session, CSRF token and XML encoding internally:
+//
+//	GET  /status  connection status
+//	GET  /signal  signal information
+//	GET  /sms     SMS messages (query params: box=inbox|outbox|draft, page, count)
+//	POST /sms     send a SMS message ({"to": ["+62..."], "message": "..."})
+//	POST /reboot  reboot the device
+//	GET  /events  server-sent events (query params: types=signal,connection,network,roaming,sms)
+//
+// When -token (or $HPROXY_TOKEN) is set, requests must provide it as a bearer
+// token (Authorization: Bearer <token>).
+package main
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kenshaw/hilink"
+)
+
+func main() {
+	endpoint := flag.String("endpoint", "http://192.168.8.1/", "api endpoint")
+	debug := flag.Bool("v", false, "enable verbose")
+	listen := flag.String("l", "localhost:9771", "listen address")
+	token := flag.String("token", os.Getenv("HPROXY_TOKEN"), "bearer token required for requests")
+	username := flag.String("username", "", "device username")
+	password := flag.String("password", "", "device password")
+	timeout := flag.Duration("timeout", 30*time.Second, "device request timeout")
+	eventsInterval := flag.Duration("events-interval", 10*time.Second, "device poll interval for /events (0 disables)")
+	flag.Parse()
+	// options
+	opts := []hilink.ClientOption{
+		hilink.WithURL(*endpoint),
+		hilink.WithAuth(*username, *password),
+		hilink.WithTimeout(*timeout),
+		// start the session and log in on the first request
+		hilink.WithNoStart(true),
+	}
+	if *debug {
+		opts = append(opts, hilink.WithLogf(log.Printf))
+	}
+	p := &proxy{
+		cl:    hilink.NewClient(opts...),
+		token: *token,
+	}
+	if *eventsInterval != 0 {
+		p.hub = newHub(p.cl, *eventsInterval)
+	}
+	if err := run(context.Background(), *listen, p); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, listen string, p *proxy) error {
+	if p.hub != nil {
+		go p.hub.run(ctx)
+	}
+	if p.token == "" {
+		log.Printf("warning: no -token set, requests will not be authenticated")
+	}
+	log.Printf("listening on %s", listen)
+	return http.ListenAndServe(listen, p.handler())
+}
+
+// proxy is the JSON proxy.
+type proxy struct {
+	cl    *hilink.Client
+	token string
+	hub   *hub
+	// mu serializes logging in again after the device session has expired.
+	mu sync.Mutex
+}
+
+// handler returns the proxy http handler.
+func (p *proxy) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/status", p.handle("GET", func(ctx context.Context, req *http.Request) (interface{}, error) {
+		return p.cl.Status(ctx)
+	}))
+	mux.HandleFunc("/signal", p.handle("GET", func(ctx context.Context, req *http.Request) (interface{}, error) {
+		return p.cl.ServingCell(ctx)
+	}))
+	mux.HandleFunc("/sms", func(w http.ResponseWriter, req *http.Request) {
+		if req.Method == "POST" {
+			p.handle("POST", p.smsSend)(w, req)
+			return
+		}
+		p.handle("GET", p.smsList)(w, req)
+	})
+	mux.HandleFunc("/reboot", p.handle("POST", func(ctx context.Context, req *http.Request) (interface{}, error) {
+		return p.check(p.cl.DeviceReboot(ctx))
+	}))
+	if p.hub != nil {
+		mux.Handle("/events", p.authorize(p.hub))
+	}
+	return mux
+}
+
+// authorize wraps h, checking the request token.
+func (p *proxy) authorize(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if !p.authorized(req) {
+			writeError(w, http.StatusUnauthorized, errors.New("unauthorized"))
+			return
+		}
+		h.ServeHTTP(w, req)
+	})
+}
+
+// authorized returns true when the request has the token.
+func (p *proxy) authorized(req *http.Request) bool {
+	if p.token == "" {
+		return true
+	}
+	tok := strings.TrimPrefix(req.Header.Get("Authorization"), "Bearer ")
+	return subtle.ConstantTimeCompare([]byte(tok), []byte(p.token)) == 1
+}
+
+// handle wraps f, checking the request method and token, and writing the
+// result as JSON. When the device session has expired, a new session is
+// started (logging in again) and f is retried once.
+func (p *proxy) handle(method string, f func(context.Context, *http.Request) (interface{}, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		if !p.authorized(req) {
+			writeError(w, http.StatusUnauthorized, errors.New("unauthorized"))
+			return
+		}
+		if req.Method != method {
+			w.Header().Set("Allow", method)
+			writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", req.Method))
+			return
+		}
+		ctx := req.Context()
+		v, err := f(ctx, req)
+		if sessionExpired(err) {
+			if err = p.relogin(ctx); err == nil {
+				v, err = f(ctx, req)
+			}
+		}
+		var reqErr requestError
+		switch {
+		case errors.As(err, &reqErr):
+			writeError(w, http.StatusBadRequest, err)
+		case err != nil:
+			writeError(w, http.StatusBadGateway, err)
+		default:
+			writeJSON(w, http.StatusOK, v)
+		}
+	}
+}
+
+// relogin starts a new device session, logging in again when credentials
+// were provided. The session is shared with the /events monitor.
+func (p *proxy) relogin(ctx context.Context) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.cl.Relogin(ctx)
+}
+
+// smsList handles GET /sms.
+func (p *proxy) smsList(ctx context.Context, req *http.Request) (interface{}, error) {
+	q := req.URL.Query()
+	boxType := hilink.SmsBoxTypeInbox
+	switch q.Get("box") {
+	case "", "inbox":
+	case "outbox":
+		boxType = hilink.SmsBoxTypeOutbox
+	case "draft":
+		boxType = hilink.SmsBoxTypeDraft
+	default:
+		return nil, requestError(fmt.Sprintf("invalid box %q", q.Get("box")))
+	}
+	page, err := queryUint(q.Get("page"), 1)
+	if err != nil {
+		return nil, err
+	}
+	count, err := queryUint(q.Get("count"), 20)
+	if err != nil {
+		return nil, err
+	}
+	l, err := p.cl.SmsMessages(ctx, boxType, page, count)
+	if err != nil {
+		return nil, err
+	}
+	if l == nil {
+		l = []hilink.SmsMessage{}
+	}
+	return l, nil
+}
+
+// smsSend handles POST /sms.
+func (p *proxy) smsSend(ctx context.Context, req *http.Request) (interface{}, error) {
+	var v struct {
+		To      []string `json:"to"`
+		Message string   `json:"message"`
+	}
+	if err := json.NewDecoder(req.Body).Decode(&v); err != nil {
+		return nil, requestError("invalid request body: " + err.Error())
+	}
+	if len(v.To) == 0 || v.Message == "" {
+		return nil, requestError("must specify to and message")
+	}
+	ok, err := p.cl.SmsSend(ctx, v.Message, v.To...)
+	if errors.Is(err, hilink.ErrMessageTooLong) {
+		return nil, requestError(err.Error())
+	}
+	return p.check(ok, err)
+}
+
+// check converts a bool result.
+func (p *proxy) check(ok bool, err error) (interface{}, error) {
+	if err != nil {
+		return nil, err
+	}
+	return map[string]bool{"ok": ok}, nil
+}
+
+// queryUint parses a uint query param.
+func queryUint(s string, def uint) (uint, error) {
+	if s == "" {
+		return def, nil
+	}
+	i, err := strconv.ParseUint(s, 10, 32)
+	if err != nil {
+		return 0, requestError(fmt.Sprintf("invalid value %q", s))
+	}
+	return uint(i), nil
+}
+
+// sessionExpired returns true when err indicates the device session has
+// expired.
+func sessionExpired(err error) bool {
+	var apiErr *hilink.APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	switch apiErr.Code {
+	case 100003, 108007, 125001, 125002, 125003:
+		return true
+	}
+	return false
+}
+
+// requestError is an invalid request error.
+type requestError string
+
+// Error satisfies the error interface.
+func (err requestError) Error() string {
+	return string(err)
+}
+
+// writeJSON writes v as JSON.
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.Encode(v)
+}
+
+// writeError writes err as a JSON error, including the device error code for
+// device errors.
+func writeError(w http.ResponseWriter, status int, err error) {
+	v := map[string]interface{}{
+		"error": err.Error(),
+	}
+	var apiErr *hilink.APIError
+	if errors.As(err, &apiErr) {
+		v["code"] = apiErr.Code
+	}
+	writeJSON(w, status, v)
+}
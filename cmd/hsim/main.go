@@ -0,0 +1,55 @@
+// Command hsim is a simulator for Huawei Hilink devices.
+//
+// hsim serves an emulation of the WebUI API of an E3372 (USB modem) or E5186
+// (router) device, with sessions and CSRF tokens, optional login, an in
+// memory SMS store, and status and signal values with jitter. It is useful
+// for demos, CI, and developing the cmd tools without a device:
+//
+//	$ hsim -l localhost:8080 &
+//	$ hlcli statusinfo -endpoint http://localhost:8080/
+//
+// Messages sent to the simulated device's own number (-msisdn) are received
+// in the inbox, and with -incoming, a message is received every interval.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"time"
+)
+
+func main() {
+	listen := flag.String("l", "localhost:8080", "listen address")
+	model := flag.String("model", "E3372", "device model (E3372, E5186)")
+	username := flag.String("username", "admin", "login username")
+	password := flag.String("password", "", "login password (login is not required when empty)")
+	msisdn := flag.String("msisdn", "+10005550100", "device phone number")
+	incoming := flag.Duration("incoming", 0, "receive a sms message every interval (0 disables)")
+	rebootTime := flag.Duration("reboot-time", 20*time.Second, "time the device is unavailable when rebooting")
+	lax := flag.Bool("lax", false, "do not check sessions and tokens")
+	verbose := flag.Bool("v", false, "log requests")
+	flag.Parse()
+	s, err := newSim(*model, *username, *password, *msisdn, *rebootTime, *lax)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+	if *incoming != 0 {
+		go s.receiveEvery(*incoming)
+	}
+	var h http.Handler = s
+	if *verbose {
+		h = http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			log.Printf("%s %s", req.Method, req.URL.Path)
+			s.ServeHTTP(w, req)
+		})
+	}
+	log.Printf("simulating %s on %s", s.model.name, *listen)
+	if err := http.ListenAndServe(*listen, h); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,810 @@
+package main
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"log"
+	mrand "math/rand"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// API error codes.
+const (
+	errNotSupported = 100002
+	errNoRights     = 100003
+	errParameter    = 100006
+	errPassword     = 108006
+	errSession      = 125002
+	errToken        = 125003
+)
+
+// model is a simulated device model.
+type model struct {
+	name     string
+	family   string
+	classify string
+	hardware string
+	software string
+	webui    string
+	wifi     bool
+}
+
+// models are the simulated device models.
+var models = map[string]model{
+	"E3372": {
+		name:     "E3372h-153",
+		family:   "LTE",
+		classify: "hilink",
+		hardware: "CL2E3372HM",
+		software: "22.200.09.01.161",
+		webui:    "17.100.11.00.03",
+	},
+	"E5186": {
+		name:     "E5186s-22a",
+		family:   "LTE",
+		classify: "cpe",
+		hardware: "WL1E5186SM",
+		software: "21.290.23.00.00",
+		webui:    "21.100.32.00.03",
+		wifi:     true,
+	},
+}
+
+// sms is a stored SMS message.
+type sms struct {
+	index   int
+	box     int
+	status  int
+	phone   string
+	content string
+	date    time.Time
+}
+
+// session is a WebUI session.
+type session struct {
+	token    string
+	loggedIn bool
+}
+
+// sim is the device simulator.
+type sim struct {
+	model      model
+	username   string
+	password   string
+	msisdn     string
+	rebootTime time.Duration
+	lax        bool
+	sessions   map[string]*session
+	smsIndex   int
+	messages   []*sms
+	connected  bool
+	since      time.Time
+	upload     uint64
+	download   uint64
+	month      uint64
+	monthUp    uint64
+	rebooted   time.Time
+	ussd       string
+	rsrp       float64
+	sinr       float64
+	sync.Mutex
+}
+
+// newSim creates a new simulator.
+func newSim(name, username, password, msisdn string, rebootTime time.Duration, lax bool) (*sim, error) {
+	m, ok := models[strings.ToUpper(name)]
+	if !ok {
+		return nil, fmt.Errorf("unknown model %q", name)
+	}
+	s := &sim{
+		model:      m,
+		username:   username,
+		password:   password,
+		msisdn:     msisdn,
+		rebootTime: rebootTime,
+		lax:        lax,
+		sessions:   make(map[string]*session),
+		smsIndex:   40000,
+		connected:  true,
+		since:      time.Now(),
+		month:      3 << 30,
+		monthUp:    300 << 20,
+		rsrp:       -95,
+		sinr:       12,
+	}
+	s.receive("+10005550123", "Welcome to the hsim network!")
+	return s, nil
+}
+
+// ServeHTTP satisfies the http.Handler interface.
+func (s *sim) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	s.Lock()
+	defer s.Unlock()
+	if time.Now().Before(s.rebooted.Add(s.rebootTime)) {
+		http.Error(w, "rebooting", http.StatusServiceUnavailable)
+		return
+	}
+	s.tick()
+	path := strings.TrimPrefix(req.URL.Path, "/")
+	if path == "api/webserver/SesTokInfo" {
+		s.newSession(w)
+		return
+	}
+	// check session and token
+	var sess *session
+	if c, err := req.Cookie("SessionID"); err == nil {
+		sess = s.sessions[c.Value]
+	}
+	var body map[string]interface{}
+	if req.Method == "POST" {
+		switch {
+		case sess == nil && !s.lax:
+			writeError(w, errSession)
+			return
+		case sess != nil && req.Header.Get(tokenHeader) != sess.token && !s.lax:
+			writeError(w, errToken)
+			return
+		}
+		buf, err := ioutil.ReadAll(req.Body)
+		if err != nil {
+			writeError(w, errParameter)
+			return
+		}
+		body, err = decodeRequest(buf)
+		if err != nil {
+			writeError(w, errParameter)
+			return
+		}
+		if body == nil {
+			body = make(map[string]interface{})
+		}
+		// rotate the token, checking the login password against the
+		// previous token
+		var token string
+		if sess != nil {
+			token, sess.token = sess.token, randomString(32)
+			w.Header().Set(tokenHeader, sess.token)
+		}
+		if path == "api/user/login" {
+			s.login(w, sess, token, body)
+			return
+		}
+	}
+	// check login
+	if s.password != "" && !unprotected[path] && (sess == nil || !sess.loggedIn) {
+		writeError(w, errNoRights)
+		return
+	}
+	f, ok := handlers[req.Method+" "+path]
+	if !ok {
+		writeError(w, errNotSupported)
+		return
+	}
+	f(s, w, sess, body)
+}
+
+// tokenHeader is the header used for CSRF tokens.
+const tokenHeader = "__RequestVerificationToken"
+
+// unprotected are the paths available without logging in.
+var unprotected = map[string]bool{
+	"api/user/state-login":         true,
+	"api/monitoring/status":        true,
+	"api/device/basic_information": true,
+}
+
+// handlers are the request handlers, keyed by method and path.
+var handlers = map[string]func(*sim, http.ResponseWriter, *session, map[string]interface{}){
+	"GET api/user/state-login":               (*sim).stateLogin,
+	"POST api/user/logout":                   (*sim).logout,
+	"GET api/device/information":             (*sim).deviceInformation,
+	"GET api/device/basic_information":       (*sim).basicInformation,
+	"GET api/device/signal":                  (*sim).signal,
+	"POST api/device/control":                (*sim).control,
+	"GET api/monitoring/status":              (*sim).status,
+	"GET api/monitoring/traffic-statistics":  (*sim).traffic,
+	"POST api/monitoring/clear-traffic":      (*sim).clearTraffic,
+	"GET api/monitoring/month_statistics":    (*sim).monthStatistics,
+	"GET api/monitoring/check-notifications": (*sim).notifications,
+	"POST api/monitoring/clear-notifications": func(s *sim, w http.ResponseWriter, _ *session, _ map[string]interface{}) {
+		writeOK(w)
+	},
+	"GET api/net/current-plmn":          (*sim).currentPlmn,
+	"GET api/dialup/mobile-dataswitch":  (*sim).dataswitch,
+	"POST api/dialup/mobile-dataswitch": (*sim).setDataswitch,
+	"POST api/dialup/dial":              (*sim).dial,
+	"GET api/sms/sms-count":             (*sim).smsCount,
+	"POST api/sms/sms-list":             (*sim).smsList,
+	"POST api/sms/send-sms":             (*sim).sendSms,
+	"POST api/sms/set-read":             (*sim).setRead,
+	"POST api/sms/delete-sms":           (*sim).deleteSms,
+	"POST api/ussd/send":                (*sim).ussdSend,
+	"GET api/ussd/status": func(s *sim, w http.ResponseWriter, _ *session, _ map[string]interface{}) {
+		writeResponse(w, "result", "0")
+	},
+	"GET api/ussd/get": func(s *sim, w http.ResponseWriter, _ *session, _ map[string]interface{}) {
+		writeResponse(w, "content", s.ussd)
+	},
+	"GET api/ussd/release": func(s *sim, w http.ResponseWriter, _ *session, _ map[string]interface{}) {
+		writeOK(w)
+	},
+}
+
+// tick updates the simulated traffic and signal values.
+func (s *sim) tick() {
+	s.rsrp = clamp(s.rsrp+mrand.Float64()*4-2, -115, -75)
+	s.sinr = clamp(s.sinr+mrand.Float64()*2-1, 0, 25)
+	if !s.connected {
+		return
+	}
+	// roughly 50KiB/s down, and 5KiB/s up, since the last request
+	n := uint64(time.Since(s.since).Seconds()*50*1024) - s.download
+	if n > 1<<30 {
+		n = 0
+	}
+	s.download += n
+	s.upload += n / 10
+	s.month += n
+	s.monthUp += n / 10
+}
+
+// clamp clamps v to [min, max].
+func clamp(v, min, max float64) float64 {
+	switch {
+	case v < min:
+		return min
+	case v > max:
+		return max
+	}
+	return v
+}
+
+// newSession starts a new session.
+func (s *sim) newSession(w http.ResponseWriter) {
+	id := randomString(32)
+	sess := &session{token: randomString(32)}
+	s.sessions[id] = sess
+	http.SetCookie(w, &http.Cookie{Name: "SessionID", Value: id, Path: "/", HttpOnly: true})
+	writeResponse(w, "SesInfo", "SessionID="+id, "TokInfo", sess.token)
+}
+
+// login handles api/user/login, checking the password hashed with the
+// username and the current token, as with password_type 4.
+func (s *sim) login(w http.ResponseWriter, sess *session, token string, body map[string]interface{}) {
+	if sess == nil {
+		writeError(w, errSession)
+		return
+	}
+	pw := sha256.Sum256([]byte(s.password))
+	h := sha256.Sum256([]byte(s.username + base64.StdEncoding.EncodeToString([]byte(hex.EncodeToString(pw[:]))) + token))
+	want := base64.RawStdEncoding.EncodeToString([]byte(hex.EncodeToString(h[:])))
+	if str(body, "Username") != s.username || str(body, "Password") != want {
+		writeError(w, errPassword)
+		return
+	}
+	sess.loggedIn = true
+	writeOK(w)
+}
+
+func (s *sim) stateLogin(w http.ResponseWriter, sess *session, _ map[string]interface{}) {
+	state, username := "-1", ""
+	if sess != nil && sess.loggedIn {
+		state, username = "0", s.username
+	}
+	writeResponse(w, "State", state, "Username", username, "password_type", "4")
+}
+
+func (s *sim) logout(w http.ResponseWriter, sess *session, _ map[string]interface{}) {
+	if sess != nil {
+		sess.loggedIn = false
+	}
+	writeOK(w)
+}
+
+func (s *sim) deviceInformation(w http.ResponseWriter, _ *session, _ map[string]interface{}) {
+	writeResponse(w,
+		"DeviceName", s.model.name,
+		"SerialNumber", "SIM0000000000001",
+		"Imei", "860000000000001",
+		"Imsi", "001010000000001",
+		"Iccid", "8900100000000000001",
+		"Msisdn", s.msisdn,
+		"HardwareVersion", s.model.hardware,
+		"SoftwareVersion", s.model.software,
+		"WebUIVersion", s.model.webui,
+		"MacAddress1", "00:1E:10:1F:00:01",
+		"MacAddress2", "",
+		"ProductFamily", s.model.family,
+		"Classify", s.model.classify,
+		"supportmode", "LTE|WCDMA|GSM",
+		"workmode", "LTE",
+	)
+}
+
+func (s *sim) basicInformation(w http.ResponseWriter, _ *session, _ map[string]interface{}) {
+	writeResponse(w,
+		"productfamily", s.model.family,
+		"classify", s.model.classify,
+		"devicename", s.model.name,
+		"SoftwareVersion", s.model.software,
+		"WebUIVersion", s.model.webui,
+	)
+}
+
+func (s *sim) signal(w http.ResponseWriter, _ *session, _ map[string]interface{}) {
+	writeResponse(w,
+		"pci", "101",
+		"sc", "",
+		"cell_id", "25601793",
+		"enodeb_id", "0100007",
+		"rsrq", fmt.Sprintf("%.1fdB", clamp(-8+(s.rsrp+95)/5, -20, -3)),
+		"rsrp", fmt.Sprintf("%.0fdBm", s.rsrp),
+		"rssi", fmt.Sprintf("%.0fdBm", s.rsrp+28),
+		"sinr", fmt.Sprintf("%.0fdB", s.sinr),
+		"mode", "7",
+		"band", "3",
+		"earfcn", "1300",
+		"dlbandwidth", "20MHz",
+		"ulbandwidth", "20MHz",
+		"tac", "1234",
+		"plmn", "00101",
+	)
+}
+
+// control handles api/device/control, simulating a reboot.
+func (s *sim) control(w http.ResponseWriter, _ *session, body map[string]interface{}) {
+	switch str(body, "Control") {
+	case "1", "4":
+		writeOK(w)
+		log.Printf("rebooting")
+		s.rebooted = time.Now()
+		s.sessions = make(map[string]*session)
+		s.connected, s.since, s.download, s.upload = true, time.Now().Add(s.rebootTime), 0, 0
+	default:
+		writeError(w, errParameter)
+	}
+}
+
+func (s *sim) status(w http.ResponseWriter, _ *session, _ map[string]interface{}) {
+	connStatus, ip := "902", ""
+	if s.connected {
+		connStatus, ip = "901", "10.64.0.2"
+	}
+	icon := 1 + int((s.rsrp+115)/10)
+	wifiStatus, wifiUsers := "0", "0"
+	if s.model.wifi {
+		wifiStatus, wifiUsers = "1", "2"
+	}
+	writeResponse(w,
+		"ConnectionStatus", connStatus,
+		"WifiConnectionStatus", "",
+		"SignalStrength", "",
+		"SignalIcon", strconv.Itoa(icon),
+		"CurrentNetworkType", "19",
+		"CurrentServiceDomain", "3",
+		"RoamingStatus", "0",
+		"BatteryStatus", "",
+		"BatteryLevel", "",
+		"BatteryPercent", "",
+		"simlockStatus", "0",
+		"PrimaryDns", dnsIf(s.connected, "10.64.64.64"),
+		"SecondaryDns", dnsIf(s.connected, "10.64.64.65"),
+		"WanIPAddress", ip,
+		"CurrentWifiUser", wifiUsers,
+		"TotalWifiUser", "32",
+		"currenttotalwifiuser", wifiUsers,
+		"ServiceStatus", "2",
+		"SimStatus", "1",
+		"WifiStatus", wifiStatus,
+		"CurrentNetworkTypeEx", "101",
+		"maxsignal", "5",
+		"wifiindooronly", "0",
+		"wififrequence", "0",
+		"classify", s.model.classify,
+		"flymode", "0",
+		"cellroam", "1",
+	)
+}
+
+// dnsIf returns ip when connected.
+func dnsIf(connected bool, ip string) string {
+	if connected {
+		return ip
+	}
+	return ""
+}
+
+func (s *sim) traffic(w http.ResponseWriter, _ *session, _ map[string]interface{}) {
+	var connectTime int64
+	var upRate, downRate uint64
+	if s.connected {
+		connectTime = int64(time.Since(s.since).Seconds())
+		downRate = uint64(50*1024 + mrand.Intn(10*1024))
+		upRate = downRate / 10
+	}
+	writeResponse(w,
+		"CurrentConnectTime", strconv.FormatInt(connectTime, 10),
+		"CurrentUpload", strconv.FormatUint(s.upload, 10),
+		"CurrentDownload", strconv.FormatUint(s.download, 10),
+		"CurrentDownloadRate", strconv.FormatUint(downRate, 10),
+		"CurrentUploadRate", strconv.FormatUint(upRate, 10),
+		"TotalUpload", strconv.FormatUint(s.monthUp*3, 10),
+		"TotalDownload", strconv.FormatUint(s.month*3, 10),
+		"TotalConnectTime", strconv.FormatInt(connectTime+86400*42, 10),
+		"showtraffic", "1",
+	)
+}
+
+func (s *sim) clearTraffic(w http.ResponseWriter, _ *session, _ map[string]interface{}) {
+	s.since, s.download, s.upload, s.month, s.monthUp = time.Now(), 0, 0, 0, 0
+	writeOK(w)
+}
+
+func (s *sim) monthStatistics(w http.ResponseWriter, _ *session, _ map[string]interface{}) {
+	writeResponse(w,
+		"CurrentMonthDownload", strconv.FormatUint(s.month, 10),
+		"CurrentMonthUpload", strconv.FormatUint(s.monthUp, 10),
+		"MonthDuration", strconv.Itoa(86400*12),
+		"MonthLastClearTime", time.Now().Format("2006-1-")+"1",
+	)
+}
+
+func (s *sim) notifications(w http.ResponseWriter, _ *session, _ map[string]interface{}) {
+	writeResponse(w,
+		"UnreadMessage", strconv.Itoa(s.count(1, true)),
+		"SmsStorageFull", "0",
+		"OnlineUpdateStatus", "10",
+	)
+}
+
+func (s *sim) currentPlmn(w http.ResponseWriter, _ *session, _ map[string]interface{}) {
+	writeResponse(w,
+		"State", "0",
+		"FullName", "hsim Mobile",
+		"ShortName", "hsim",
+		"Numeric", "00101",
+		"Rat", "7",
+		"Spn", "",
+	)
+}
+
+func (s *sim) dataswitch(w http.ResponseWriter, _ *session, _ map[string]interface{}) {
+	writeResponse(w, "dataswitch", boolString(s.connected))
+}
+
+func (s *sim) setDataswitch(w http.ResponseWriter, _ *session, body map[string]interface{}) {
+	s.connect(str(body, "dataswitch") == "1")
+	writeOK(w)
+}
+
+func (s *sim) dial(w http.ResponseWriter, _ *session, body map[string]interface{}) {
+	s.connect(str(body, "Action") == "1")
+	writeOK(w)
+}
+
+// connect connects or disconnects the simulated connection.
+func (s *sim) connect(connected bool) {
+	if connected && !s.connected {
+		s.since, s.download, s.upload = time.Now(), 0, 0
+	}
+	s.connected = connected
+}
+
+// count returns the count of messages in the box.
+func (s *sim) count(box int, unread bool) int {
+	var n int
+	for _, m := range s.messages {
+		if m.box == box && (!unread || m.status == 0) {
+			n++
+		}
+	}
+	return n
+}
+
+func (s *sim) smsCount(w http.ResponseWriter, _ *session, _ map[string]interface{}) {
+	writeResponse(w,
+		"LocalUnread", strconv.Itoa(s.count(1, true)),
+		"LocalInbox", strconv.Itoa(s.count(1, false)),
+		"LocalOutbox", strconv.Itoa(s.count(2, false)),
+		"LocalDraft", strconv.Itoa(s.count(3, false)),
+		"LocalDeleted", "0",
+		"SimUnread", "0",
+		"SimInbox", "0",
+		"SimOutbox", "0",
+		"SimDraft", "0",
+		"LocalMax", "500",
+		"SimMax", "50",
+		"SimUsed", "0",
+		"NewMsg", "0",
+	)
+}
+
+func (s *sim) smsList(w http.ResponseWriter, _ *session, body map[string]interface{}) {
+	box, _ := strconv.Atoi(str(body, "BoxType"))
+	page, _ := strconv.Atoi(str(body, "PageIndex"))
+	count, _ := strconv.Atoi(str(body, "ReadCount"))
+	if page < 1 || count < 1 || count > 50 {
+		writeError(w, errParameter)
+		return
+	}
+	var l []*sms
+	for _, m := range s.messages {
+		if m.box == box {
+			l = append(l, m)
+		}
+	}
+	ascending, unreadFirst := str(body, "Ascending") == "1", str(body, "UnreadPreferred") == "1"
+	sort.SliceStable(l, func(i, j int) bool {
+		if unreadFirst && (l[i].status == 0) != (l[j].status == 0) {
+			return l[i].status == 0
+		}
+		if ascending {
+			return l[i].index < l[j].index
+		}
+		return l[i].index > l[j].index
+	})
+	start := (page - 1) * count
+	if start > len(l) {
+		start = len(l)
+	}
+	end := start + count
+	if end > len(l) {
+		end = len(l)
+	}
+	var buf strings.Builder
+	fmt.Fprintf(&buf, "<Count>%d</Count>\n<Messages>\n", end-start)
+	for _, m := range l[start:end] {
+		buf.WriteString("<Message>\n")
+		buf.WriteString(pairs(
+			"Smstat", strconv.Itoa(m.status),
+			"Index", strconv.Itoa(m.index),
+			"Phone", m.phone,
+			"Content", m.content,
+			"Date", m.date.Format("2006-01-02 15:04:05"),
+			"Sca", "",
+			"SaveType", "4",
+			"Priority", "0",
+			"SmsType", "1",
+		))
+		buf.WriteString("</Message>\n")
+	}
+	buf.WriteString("</Messages>\n")
+	writeRaw(w, buf.String())
+}
+
+func (s *sim) sendSms(w http.ResponseWriter, _ *session, body map[string]interface{}) {
+	content := str(body, "Content")
+	var phones []string
+	if p, ok := body["Phones"].(map[string]interface{}); ok {
+		switch x := p["Phone"].(type) {
+		case string:
+			phones = append(phones, x)
+		case []interface{}:
+			for _, z := range x {
+				if s, ok := z.(string); ok {
+					phones = append(phones, s)
+				}
+			}
+		}
+	}
+	if len(phones) == 0 || content == "" {
+		writeError(w, errParameter)
+		return
+	}
+	for _, phone := range phones {
+		s.smsIndex++
+		s.messages = append(s.messages, &sms{
+			index:   s.smsIndex,
+			box:     2,
+			status:  3,
+			phone:   phone,
+			content: content,
+			date:    time.Now(),
+		})
+		if phone == s.msisdn {
+			s.receive(s.msisdn, content)
+		}
+	}
+	writeOK(w)
+}
+
+func (s *sim) setRead(w http.ResponseWriter, _ *session, body map[string]interface{}) {
+	ids := make(map[string]bool)
+	switch x := body["Index"].(type) {
+	case string:
+		ids[x] = true
+	case []interface{}:
+		for _, z := range x {
+			if s, ok := z.(string); ok {
+				ids[s] = true
+			}
+		}
+	}
+	for _, m := range s.messages {
+		if ids[strconv.Itoa(m.index)] && m.status == 0 {
+			m.status = 1
+		}
+	}
+	writeOK(w)
+}
+
+func (s *sim) deleteSms(w http.ResponseWriter, _ *session, body map[string]interface{}) {
+	index := str(body, "Index")
+	for i, m := range s.messages {
+		if strconv.Itoa(m.index) == index {
+			s.messages = append(s.messages[:i], s.messages[i+1:]...)
+			writeOK(w)
+			return
+		}
+	}
+	writeError(w, errParameter)
+}
+
+func (s *sim) ussdSend(w http.ResponseWriter, _ *session, body map[string]interface{}) {
+	switch code := str(body, "content"); {
+	case code == "":
+		writeError(w, errParameter)
+		return
+	case strings.Contains(code, "100"):
+		s.ussd = fmt.Sprintf("Your balance is 12.34. Data remaining: %.2fGB.", float64(20<<30-s.month)/(1<<30))
+	default:
+		s.ussd = "Unknown request " + code
+	}
+	writeOK(w)
+}
+
+// receive adds a received message to the inbox.
+func (s *sim) receive(phone, content string) {
+	s.smsIndex++
+	s.messages = append(s.messages, &sms{
+		index:   s.smsIndex,
+		box:     1,
+		phone:   phone,
+		content: content,
+		date:    time.Now(),
+	})
+}
+
+// receiveEvery receives a message every interval.
+func (s *sim) receiveEvery(interval time.Duration) {
+	for range time.Tick(interval) {
+		s.Lock()
+		s.receive("+10005550199", fmt.Sprintf("Your verification code is %06d", mrand.Intn(1000000)))
+		s.Unlock()
+	}
+}
+
+// str returns the string value of the key in m.
+func str(m map[string]interface{}, key string) string {
+	s, _ := m[key].(string)
+	return strings.TrimSpace(s)
+}
+
+// boolString returns 1 or 0 for b.
+func boolString(b bool) string {
+	if b {
+		return "1"
+	}
+	return "0"
+}
+
+// randomString returns a random hex string of length n.
+func randomString(n int) string {
+	buf := make([]byte, n/2)
+	if _, err := rand.Read(buf); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(buf)
+}
+
+// pairs encodes the key/value pairs as XML elements.
+func pairs(vals ...string) string {
+	var buf strings.Builder
+	for i := 0; i < len(vals); i += 2 {
+		fmt.Fprintf(&buf, "<%s>%s</%s>\n", vals[i], escape(vals[i+1]), vals[i])
+	}
+	return buf.String()
+}
+
+// escape escapes s for use in XML.
+func escape(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(s)
+}
+
+// writeRaw writes a response containing the raw XML.
+func writeRaw(w http.ResponseWriter, body string) {
+	w.Header().Set("Content-Type", "text/html")
+	fmt.Fprintf(w, "<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n<response>\n%s</response>\n", body)
+}
+
+// writeResponse writes a response containing the key/value pairs.
+func writeResponse(w http.ResponseWriter, vals ...string) {
+	writeRaw(w, pairs(vals...))
+}
+
+// writeOK writes an OK response.
+func writeOK(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "text/html")
+	fmt.Fprint(w, "<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n<response>OK</response>\n")
+}
+
+// writeError writes an error response.
+func writeError(w http.ResponseWriter, code int) {
+	w.Header().Set("Content-Type", "text/html")
+	fmt.Fprintf(w, "<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n<error>\n<code>%d</code>\n<message></message>\n</error>\n", code)
+}
+
+// decodeRequest decodes the child elements of a <request> body. Simple
+// elements are decoded as strings, elements with child elements as maps, and
+// repeated elements as a []interface{}, as decoded by the hilink package.
+func decodeRequest(buf []byte) (map[string]interface{}, error) {
+	dec := xml.NewDecoder(bytes.NewReader(buf))
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		if start, ok := tok.(xml.StartElement); ok {
+			if start.Name.Local != "request" {
+				return nil, fmt.Errorf("unexpected root element %q", start.Name.Local)
+			}
+			v, err := decodeElement(dec)
+			if err != nil {
+				return nil, err
+			}
+			m, _ := v.(map[string]interface{})
+			if m == nil {
+				m = make(map[string]interface{})
+			}
+			return m, nil
+		}
+	}
+}
+
+// decodeElement decodes the value of an element, after its start element.
+func decodeElement(dec *xml.Decoder) (interface{}, error) {
+	var m map[string]interface{}
+	var text []byte
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			v, err := decodeElement(dec)
+			if err != nil {
+				return nil, err
+			}
+			if m == nil {
+				m = make(map[string]interface{})
+			}
+			switch prev := m[t.Name.Local].(type) {
+			case nil:
+				m[t.Name.Local] = v
+			case []interface{}:
+				m[t.Name.Local] = append(prev, v)
+			default:
+				m[t.Name.Local] = []interface{}{prev, v}
+			}
+		case xml.CharData:
+			text = append(text, t...)
+		case xml.EndElement:
+			if m != nil {
+				return m, nil
+			}
+			return strings.TrimSpace(string(text)), nil
+		}
+	}
+}
@@ -0,0 +1,165 @@
+// Command hsmshook forwards incoming SMS messages received by a Huawei Hilink
+// device to a webhook.
+//
+// Each newly received message is POSTed as JSON to the webhook URL. When a
+// secret is provided (-secret or $HSMSHOOK_SECRET), requests are signed with
+// a HMAC-SHA256 of the timestamp and the body, joined by a period, and sent
+// in the X-Hilink-Signature header (sha256=<hex>), along with the timestamp
+// in the X-Hilink-Timestamp header. Failed deliveries are retried with
+// exponential backoff.
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/kenshaw/hilink"
+)
+
+func main() {
+	endpoint := flag.String("endpoint", "http://192.168.8.1/", "api endpoint")
+	username := flag.String("username", "", "device username")
+	password := flag.String("password", "", "device password")
+	debug := flag.Bool("v", false, "enable verbose")
+	webhook := flag.String("webhook", "", "webhook url")
+	secret := flag.String("secret", os.Getenv("HSMSHOOK_SECRET"), "webhook signing secret")
+	interval := flag.Duration("interval", 10*time.Second, "poll interval")
+	retries := flag.Int("retries", 5, "delivery retries")
+	timeout := flag.Duration("timeout", 10*time.Second, "webhook request timeout")
+	flag.Parse()
+	f := &forwarder{
+		url:     *webhook,
+		secret:  []byte(*secret),
+		retries: *retries,
+		cl:      &http.Client{Timeout: *timeout},
+	}
+	if err := run(context.Background(), *endpoint, *username, *password, *debug, *interval, f); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, endpoint, username, password string, debug bool, interval time.Duration, f *forwarder) error {
+	if f.url == "" {
+		return errors.New("must specify webhook")
+	}
+	// options
+	opts := []hilink.ClientOption{
+		hilink.WithURL(endpoint),
+		hilink.WithAuth(username, password),
+		// start the session and log in on the first request
+		hilink.WithNoStart(true),
+	}
+	if debug {
+		opts = append(opts, hilink.WithLogf(log.Printf))
+	}
+	// create client
+	cl := hilink.NewClient(opts...)
+	f.device = endpoint
+	log.Printf("forwarding sms received by %s to %s", endpoint, f.url)
+	// messages are only marked read once forwarded, and are forwarded again
+	// on the next poll when the webhook fails
+	return cl.WatchSmsFunc(ctx, interval, func(ctx context.Context, m hilink.SmsMessage) error {
+		if err := f.forward(ctx, m); err != nil {
+			return err
+		}
+		log.Printf("forwarded message %d from %s", m.Index, m.Phone)
+		return nil
+	}, func(err error) {
+		log.Printf("watch failed: %v", err)
+	})
+}
+
+// message is the webhook payload.
+type message struct {
+	Device  string    `json:"device"`
+	Index   int       `json:"index"`
+	Phone   string    `json:"phone"`
+	Content string    `json:"content"`
+	Date    time.Time `json:"date"`
+}
+
+// forwarder forwards messages to a webhook.
+type forwarder struct {
+	url     string
+	secret  []byte
+	retries int
+	device  string
+	cl      *http.Client
+}
+
+// forward posts the message to the webhook, retrying with exponential backoff
+// on failure.
+func (f *forwarder) forward(ctx context.Context, m hilink.SmsMessage) error {
+	body, err := json.Marshal(message{
+		Device:  f.device,
+		Index:   m.Index,
+		Phone:   m.Phone,
+		Content: m.Content,
+		Date:    m.Date,
+	})
+	if err != nil {
+		return err
+	}
+	backoff := time.Second
+	for i := 0; ; i++ {
+		err = f.post(ctx, body)
+		if err == nil || i >= f.retries {
+			return err
+		}
+		log.Printf("delivery attempt %d failed: %v, retrying in %v", i+1, err, backoff)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// post posts the body to the webhook.
+func (f *forwarder) post(ctx context.Context, body []byte) error {
+	req, err := http.NewRequest("POST", f.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if len(f.secret) != 0 {
+		ts := strconv.FormatInt(time.Now().Unix(), 10)
+		req.Header.Set("X-Hilink-Timestamp", ts)
+		req.Header.Set("X-Hilink-Signature", "sha256="+sign(f.secret, ts, body))
+	}
+	res, err := f.cl.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	io.Copy(ioutil.Discard, res.Body)
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return fmt.Errorf("webhook returned status %d", res.StatusCode)
+	}
+	return nil
+}
+
+// sign returns the hex encoded HMAC-SHA256 of the timestamp and body.
+func sign(secret []byte, ts string, body []byte) string {
+	h := hmac.New(sha256.New, secret)
+	h.Write([]byte(ts))
+	h.Write([]byte{'.'})
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,204 @@
+// Command hsmtp is a SMS to email, and email to SMS gateway for Huawei Hilink
+// devices.
+//
+// hsmtp forwards incoming SMS messages to an email address using a SMTP
+// server. When -listen is provided, hsmtp also runs a SMTP listener that
+// sends accepted mail as SMS messages, where the local part of each recipient
+// address is the phone number (ie, +6212345678@sms).
+//
+// The SMTP listener does not support SMTP AUTH. Connections are only accepted
+// from the client addresses in the -allow-ip list (by default, the loopback
+// addresses), and the listener binds to the loopback address when -listen
+// has no host (ie, :2525). Mail is then only accepted from senders in the
+// -allow list (addresses, or domains such as @example.com). As the sender is
+// not authenticated, and can be forged by any allowed client, the -allow list
+// is not a security measure on its own: -allow-ip should only contain
+// trusted hosts (ie, a local mail server relaying authenticated mail).
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net"
+	"net/smtp"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/kenshaw/hilink"
+)
+
+func main() {
+	endpoint := flag.String("endpoint", "http://192.168.8.1/", "api endpoint")
+	username := flag.String("username", "", "device username")
+	password := flag.String("password", "", "device password")
+	debug := flag.Bool("v", false, "enable verbose")
+	interval := flag.Duration("interval", 10*time.Second, "sms poll interval")
+	smtpServer := flag.String("smtp", "", "smtp server (host:port) for forwarding sms")
+	smtpUser := flag.String("smtp-user", "", "smtp username")
+	smtpPass := flag.String("smtp-pass", os.Getenv("HSMTP_PASSWORD"), "smtp password")
+	from := flag.String("from", "", "from address for forwarded sms")
+	to := flag.String("to", "", "to address(es) for forwarded sms, comma separated")
+	listen := flag.String("listen", "", "smtp listen address for sending sms (ie, :2525 for localhost:2525)")
+	allow := flag.String("allow", "", "allowed senders for -listen (addresses or @domains), comma separated")
+	allowIP := flag.String("allow-ip", "127.0.0.0/8,::1/128", "allowed client addresses for -listen (addresses or networks), comma separated")
+	flag.Parse()
+	allowNets, err := parseNets(split(*allowIP))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+	g := &gateway{
+		smtpServer: *smtpServer,
+		from:       *from,
+		to:         split(*to),
+		allow:      split(*allow),
+		allowNets:  allowNets,
+	}
+	if *smtpUser != "" {
+		host, _, _ := net.SplitHostPort(*smtpServer)
+		g.auth = smtp.PlainAuth("", *smtpUser, *smtpPass, host)
+	}
+	if err := run(context.Background(), *endpoint, *username, *password, *debug, *interval, *listen, g); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, endpoint, username, password string, debug bool, interval time.Duration, listen string, g *gateway) error {
+	if g.smtpServer == "" && listen == "" {
+		return errors.New("must specify smtp or listen")
+	}
+	if g.smtpServer != "" && (g.from == "" || len(g.to) == 0) {
+		return errors.New("must specify from and to with smtp")
+	}
+	if listen != "" && len(g.allow) == 0 {
+		return errors.New("must specify allow with listen")
+	}
+	// options
+	opts := []hilink.ClientOption{
+		hilink.WithURL(endpoint),
+		hilink.WithAuth(username, password),
+		// start the session and log in on the first request
+		hilink.WithNoStart(true),
+	}
+	if debug {
+		opts = append(opts, hilink.WithLogf(log.Printf))
+	}
+	// create client
+	g.cl = hilink.NewClient(opts...)
+	errs := make(chan error, 2)
+	if listen != "" {
+		go func() {
+			errs <- g.listen(ctx, listen)
+		}()
+	}
+	if g.smtpServer != "" {
+		go func() {
+			errs <- g.forward(ctx, interval)
+		}()
+	}
+	return <-errs
+}
+
+// gateway is the SMS/email gateway.
+type gateway struct {
+	cl         *hilink.Client
+	smtpServer string
+	auth       smtp.Auth
+	from       string
+	to         []string
+	allow      []string
+	allowNets  []*net.IPNet
+}
+
+// forward forwards incoming SMS messages to the to addresses.
+func (g *gateway) forward(ctx context.Context, interval time.Duration) error {
+	log.Printf("forwarding sms to %s", strings.Join(g.to, ", "))
+	// messages are only marked read once forwarded
+	return g.cl.WatchSmsFunc(ctx, interval, func(ctx context.Context, m hilink.SmsMessage) error {
+		if err := smtp.SendMail(g.smtpServer, g.auth, g.from, g.to, g.mail(m)); err != nil {
+			return err
+		}
+		log.Printf("forwarded message %d from %s", m.Index, m.Phone)
+		return nil
+	}, func(err error) {
+		log.Printf("watch failed: %v", err)
+	})
+}
+
+// mail builds the mail for a SMS message.
+func (g *gateway) mail(m hilink.SmsMessage) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", g.from)
+	fmt.Fprintf(&b, "To: %s\r\n", strings.Join(g.to, ", "))
+	fmt.Fprintf(&b, "Subject: SMS from %s\r\n", m.Phone)
+	fmt.Fprintf(&b, "Date: %s\r\n", m.Date.Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	b.WriteString("Content-Transfer-Encoding: 8bit\r\n\r\n")
+	b.WriteString(strings.Replace(m.Content, "\n", "\r\n", -1))
+	b.WriteString("\r\n")
+	return []byte(b.String())
+}
+
+// allowed returns true when the sender address is allowed.
+func (g *gateway) allowed(addr string) bool {
+	addr = strings.ToLower(addr)
+	for _, a := range g.allow {
+		a = strings.ToLower(a)
+		if addr == a || (strings.HasPrefix(a, "@") && strings.HasSuffix(addr, a)) {
+			return true
+		}
+	}
+	return false
+}
+
+// allowedIP returns true when the client address is allowed.
+func (g *gateway) allowedIP(addr net.Addr) bool {
+	a, ok := addr.(*net.TCPAddr)
+	if !ok {
+		return false
+	}
+	for _, n := range g.allowNets {
+		if n.Contains(a.IP) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseNets parses a list of addresses and networks (in CIDR notation).
+func parseNets(l []string) ([]*net.IPNet, error) {
+	var nets []*net.IPNet
+	for _, s := range l {
+		if ip := net.ParseIP(s); ip != nil {
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 8*net.IPv4len
+			}
+			nets = append(nets, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, n, err := net.ParseCIDR(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid allowed address %q", s)
+		}
+		nets = append(nets, n)
+	}
+	return nets, nil
+}
+
+// split splits a comma separated list.
+func split(s string) []string {
+	var l []string
+	for _, z := range strings.Split(s, ",") {
+		if z = strings.TrimSpace(z); z != "" {
+			l = append(l, z)
+		}
+	}
+	return l
+}
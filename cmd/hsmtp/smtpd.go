@@ -0,0 +1,265 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"time"
+)
+
+// maxMessageSize is the maximum accepted mail size.
+const maxMessageSize = 64 * 1024
+
+// listen runs the SMTP listener, sending accepted mail as SMS messages. The
+// listener binds to the loopback address when addr has no host.
+func (g *gateway) listen(ctx context.Context, addr string) error {
+	if host, port, err := net.SplitHostPort(addr); err == nil && host == "" {
+		addr = net.JoinHostPort("localhost", port)
+	}
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	go func() {
+		<-ctx.Done()
+		l.Close()
+	}()
+	log.Printf("listening for mail on %s", addr)
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return err
+		}
+		go g.serve(ctx, conn)
+	}
+}
+
+// serve handles a SMTP connection.
+func (g *gateway) serve(ctx context.Context, conn net.Conn) {
+	defer conn.Close()
+	r, w := bufio.NewReader(conn), bufio.NewWriter(conn)
+	reply := func(code int, msg string) {
+		fmt.Fprintf(w, "%d %s\r\n", code, msg)
+		w.Flush()
+	}
+	if !g.allowedIP(conn.RemoteAddr()) {
+		log.Printf("rejected connection from %s", conn.RemoteAddr())
+		reply(554, "client not allowed")
+		return
+	}
+	reply(220, "hsmtp ready")
+	var from string
+	var to []string
+	for {
+		conn.SetDeadline(time.Now().Add(5 * time.Minute))
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		cmd, arg := strings.ToUpper(line), ""
+		if i := strings.IndexByte(line, ' '); i != -1 {
+			cmd, arg = strings.ToUpper(line[:i]), strings.TrimSpace(line[i+1:])
+		}
+		switch cmd {
+		case "HELO", "EHLO":
+			reply(250, "hsmtp")
+		case "MAIL":
+			addr, ok := smtpAddr(arg, "FROM:")
+			if !ok {
+				reply(501, "invalid sender")
+				continue
+			}
+			if !g.allowed(addr) {
+				log.Printf("rejected mail from %s", addr)
+				reply(550, "sender not allowed")
+				continue
+			}
+			from, to = addr, nil
+			reply(250, "ok")
+		case "RCPT":
+			addr, ok := smtpAddr(arg, "TO:")
+			if !ok || from == "" {
+				reply(503, "need valid MAIL before RCPT")
+				continue
+			}
+			phone := addr
+			if i := strings.IndexByte(addr, '@'); i != -1 {
+				phone = addr[:i]
+			}
+			if !validPhone(phone) {
+				reply(553, "recipient is not a phone number")
+				continue
+			}
+			to = append(to, phone)
+			reply(250, "ok")
+		case "DATA":
+			if from == "" || len(to) == 0 {
+				reply(503, "need MAIL and RCPT before DATA")
+				continue
+			}
+			reply(354, "end data with <CR><LF>.<CR><LF>")
+			buf, err := readData(r)
+			if err != nil {
+				reply(552, err.Error())
+				return
+			}
+			if err := g.send(ctx, from, to, buf); err != nil {
+				log.Printf("unable to send mail from %s as sms: %v", from, err)
+				reply(554, "unable to send sms")
+			} else {
+				reply(250, "ok")
+			}
+			from, to = "", nil
+		case "RSET":
+			from, to = "", nil
+			reply(250, "ok")
+		case "NOOP":
+			reply(250, "ok")
+		case "QUIT":
+			reply(221, "bye")
+			return
+		default:
+			reply(502, "command not implemented")
+		}
+	}
+}
+
+// send sends the mail body as a SMS message.
+func (g *gateway) send(ctx context.Context, from string, to []string, buf []byte) error {
+	msg, err := mailText(buf)
+	if err != nil {
+		return err
+	}
+	ok, err := g.cl.SmsSend(ctx, msg, to...)
+	switch {
+	case err != nil:
+		return err
+	case !ok:
+		return fmt.Errorf("could not send message")
+	}
+	log.Printf("sent mail from %s as sms to %s", from, strings.Join(to, ","))
+	return nil
+}
+
+// smtpAddr parses the address from a MAIL FROM or RCPT TO argument.
+func smtpAddr(arg, prefix string) (string, bool) {
+	if !strings.HasPrefix(strings.ToUpper(arg), prefix) {
+		return "", false
+	}
+	arg = strings.TrimSpace(arg[len(prefix):])
+	// strip parameters (ie, SIZE=1000)
+	if i := strings.IndexByte(arg, '>'); i != -1 {
+		arg = arg[:i+1]
+	}
+	arg = strings.TrimSuffix(strings.TrimPrefix(arg, "<"), ">")
+	return arg, arg != ""
+}
+
+// validPhone returns true when s is a phone number.
+func validPhone(s string) bool {
+	s = strings.TrimPrefix(s, "+")
+	if s == "" {
+		return false
+	}
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// readData reads the mail data, up to the terminating period.
+func readData(r *bufio.Reader) ([]byte, error) {
+	var buf []byte
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		if line == ".\r\n" || line == ".\n" {
+			return buf, nil
+		}
+		if len(buf) > maxMessageSize {
+			continue
+		}
+		buf = append(buf, strings.TrimPrefix(line, ".")...)
+	}
+}
+
+// mailText returns the text of the mail, using the first text/plain part of
+// multipart mail, or the subject when the mail has no text.
+func mailText(buf []byte) (string, error) {
+	if len(buf) > maxMessageSize {
+		return "", fmt.Errorf("message too large")
+	}
+	m, err := mail.ReadMessage(strings.NewReader(string(buf)))
+	if err != nil {
+		return "", err
+	}
+	text, err := partText(textproto.MIMEHeader(m.Header), m.Body)
+	if err != nil {
+		return "", err
+	}
+	if text = strings.TrimSpace(text); text == "" {
+		dec := new(mime.WordDecoder)
+		if text, err = dec.DecodeHeader(m.Header.Get("Subject")); err != nil {
+			return "", err
+		}
+	}
+	if text == "" {
+		return "", fmt.Errorf("empty message")
+	}
+	return text, nil
+}
+
+// partText returns the text of a mail part, decoding the part's content
+// transfer encoding.
+func partText(h textproto.MIMEHeader, r io.Reader) (string, error) {
+	typ, params, err := mime.ParseMediaType(h.Get("Content-Type"))
+	if err != nil {
+		typ = "text/plain"
+	}
+	// multipart.Reader decodes quoted-printable parts, removing the header
+	switch strings.ToLower(strings.TrimSpace(h.Get("Content-Transfer-Encoding"))) {
+	case "base64":
+		r = base64.NewDecoder(base64.StdEncoding, r)
+	case "quoted-printable":
+		r = quotedprintable.NewReader(r)
+	}
+	switch {
+	case typ == "text/plain":
+		buf, err := ioutil.ReadAll(r)
+		return strings.Replace(string(buf), "\r\n", "\n", -1), err
+	case strings.HasPrefix(typ, "multipart/"):
+		mr := multipart.NewReader(r, params["boundary"])
+		for {
+			p, err := mr.NextPart()
+			switch {
+			case err == io.EOF:
+				return "", nil
+			case err != nil:
+				return "", err
+			}
+			if text, err := partText(p.Header, p); err != nil || text != "" {
+				return text, err
+			}
+		}
+	}
+	return "", nil
+}
@@ -0,0 +1,212 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// BER tags.
+const (
+	tagInteger     = 0x02
+	tagOctetString = 0x04
+	tagNull        = 0x05
+	tagOID         = 0x06
+	tagSequence    = 0x30
+	tagIPAddress   = 0x40
+	tagCounter32   = 0x41
+	tagGauge32     = 0x42
+	tagTimeTicks   = 0x43
+	tagCounter64   = 0x46
+	// exceptions
+	tagNoSuchObject = 0x80
+	tagEndOfMibView = 0x82
+	// pdus
+	tagGetRequest     = 0xa0
+	tagGetNextRequest = 0xa1
+	tagResponse       = 0xa2
+	tagGetBulkRequest = 0xa5
+)
+
+// errInvalidBER is the invalid BER encoding error.
+var errInvalidBER = errors.New("invalid ber encoding")
+
+// oid is an object identifier.
+type oid []uint32
+
+// parseOID parses a dotted oid.
+func parseOID(s string) (oid, error) {
+	var o oid
+	for _, z := range strings.Split(strings.TrimPrefix(s, "."), ".") {
+		i, err := strconv.ParseUint(z, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid oid %q", s)
+		}
+		o = append(o, uint32(i))
+	}
+	if len(o) < 2 {
+		return nil, fmt.Errorf("invalid oid %q", s)
+	}
+	return o, nil
+}
+
+// String satisfies the fmt.Stringer interface.
+func (o oid) String() string {
+	l := make([]string, len(o))
+	for i, z := range o {
+		l[i] = strconv.FormatUint(uint64(z), 10)
+	}
+	return strings.Join(l, ".")
+}
+
+// append returns a copy of o with the sub identifiers appended.
+func (o oid) append(ids ...uint32) oid {
+	return append(append(oid(nil), o...), ids...)
+}
+
+// compare compares o to p, returning -1, 0 or 1.
+func (o oid) compare(p oid) int {
+	for i := 0; i < len(o) && i < len(p); i++ {
+		switch {
+		case o[i] < p[i]:
+			return -1
+		case o[i] > p[i]:
+			return 1
+		}
+	}
+	switch {
+	case len(o) < len(p):
+		return -1
+	case len(o) > len(p):
+		return 1
+	}
+	return 0
+}
+
+// tlv encodes a tag, length and value.
+func tlv(tag byte, value []byte) []byte {
+	buf := []byte{tag}
+	switch n := len(value); {
+	case n < 0x80:
+		buf = append(buf, byte(n))
+	default:
+		var l []byte
+		for ; n > 0; n >>= 8 {
+			l = append([]byte{byte(n)}, l...)
+		}
+		buf = append(append(buf, 0x80|byte(len(l))), l...)
+	}
+	return append(buf, value...)
+}
+
+// encodeInt encodes a signed integer.
+func encodeInt(tag byte, i int64) []byte {
+	var buf []byte
+	for {
+		buf = append([]byte{byte(i)}, buf...)
+		i >>= 8
+		if (i == 0 && buf[0]&0x80 == 0) || (i == -1 && buf[0]&0x80 != 0) {
+			break
+		}
+	}
+	return tlv(tag, buf)
+}
+
+// encodeUint encodes an unsigned integer.
+func encodeUint(tag byte, i uint64) []byte {
+	var buf []byte
+	for {
+		buf = append([]byte{byte(i)}, buf...)
+		if i >>= 8; i == 0 {
+			break
+		}
+	}
+	if buf[0]&0x80 != 0 {
+		buf = append([]byte{0}, buf...)
+	}
+	return tlv(tag, buf)
+}
+
+// encodeOID encodes an object identifier.
+func encodeOID(o oid) []byte {
+	buf := []byte{byte(o[0]*40 + o[1])}
+	for _, z := range o[2:] {
+		b := []byte{byte(z & 0x7f)}
+		for z >>= 7; z > 0; z >>= 7 {
+			b = append([]byte{byte(z&0x7f) | 0x80}, b...)
+		}
+		buf = append(buf, b...)
+	}
+	return tlv(tagOID, buf)
+}
+
+// element is a decoded BER element.
+type element struct {
+	tag   byte
+	value []byte
+}
+
+// decode decodes the next element in buf, returning the remaining bytes.
+func decode(buf []byte) (element, []byte, error) {
+	if len(buf) < 2 {
+		return element{}, nil, errInvalidBER
+	}
+	tag, n, i := buf[0], int(buf[1]), 2
+	if n&0x80 != 0 {
+		c := n & 0x7f
+		if c == 0 || c > 4 || len(buf) < 2+c {
+			return element{}, nil, errInvalidBER
+		}
+		n = 0
+		for _, b := range buf[2 : 2+c] {
+			n = n<<8 | int(b)
+		}
+		i += c
+	}
+	if n < 0 || len(buf) < i+n {
+		return element{}, nil, errInvalidBER
+	}
+	return element{tag: tag, value: buf[i : i+n]}, buf[i+n:], nil
+}
+
+// decodeAll decodes all the elements in buf.
+func decodeAll(buf []byte) ([]element, error) {
+	var l []element
+	for len(buf) != 0 {
+		e, rest, err := decode(buf)
+		if err != nil {
+			return nil, err
+		}
+		l, buf = append(l, e), rest
+	}
+	return l, nil
+}
+
+// int decodes the element as a signed integer.
+func (e element) int() (int64, error) {
+	if e.tag != tagInteger || len(e.value) == 0 || len(e.value) > 8 {
+		return 0, errInvalidBER
+	}
+	i := int64(int8(e.value[0]))
+	for _, b := range e.value[1:] {
+		i = i<<8 | int64(b)
+	}
+	return i, nil
+}
+
+// oid decodes the element as an object identifier.
+func (e element) oid() (oid, error) {
+	if e.tag != tagOID || len(e.value) == 0 {
+		return nil, errInvalidBER
+	}
+	o := oid{uint32(e.value[0]) / 40, uint32(e.value[0]) % 40}
+	var z uint32
+	for _, b := range e.value[1:] {
+		z = z<<7 | uint32(b&0x7f)
+		if b&0x80 == 0 {
+			o, z = append(o, z), 0
+		}
+	}
+	return o, nil
+}
@@ -0,0 +1,320 @@
+// Command hsnmp is a SNMP agent for Huawei Hilink devices.
+//
+// hsnmp periodically polls the device, and answers SNMPv2c get, get-next and
+// get-bulk requests for the signal, connection status and traffic statistics,
+// as described by the HILINK-MIB (see HILINK-MIB.txt). By default, the MIB
+// is rooted at the NET-SNMP experimental netSnmpPlaypen subtree
+// (1.3.6.1.4.1.8072.9999.9999), and can be changed with -base.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/kenshaw/hilink"
+)
+
+func main() {
+	endpoint := flag.String("endpoint", "http://192.168.8.1/", "api endpoint")
+	username := flag.String("username", "", "device username")
+	password := flag.String("password", "", "device password")
+	debug := flag.Bool("v", false, "enable verbose")
+	listen := flag.String("l", ":161", "udp listen address")
+	community := flag.String("community", "public", "read community")
+	base := flag.String("base", "1.3.6.1.4.1.8072.9999.9999", "mib base oid")
+	interval := flag.Duration("interval", 30*time.Second, "poll interval")
+	flag.Parse()
+	if err := run(context.Background(), *endpoint, *username, *password, *debug, *listen, *community, *base, *interval); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, endpoint, username, password string, debug bool, listen, community, base string, interval time.Duration) error {
+	b, err := parseOID(base)
+	if err != nil {
+		return err
+	}
+	// options
+	opts := []hilink.ClientOption{
+		hilink.WithURL(endpoint),
+		hilink.WithAuth(username, password),
+		// start the session and log in on the first request
+		hilink.WithNoStart(true),
+	}
+	if debug {
+		opts = append(opts, hilink.WithLogf(log.Printf))
+	}
+	// create client
+	cl := hilink.NewClient(opts...)
+	a := &agent{
+		community: community,
+		base:      b,
+	}
+	// poll
+	m := hilink.NewMonitor(cl, interval, hilink.WithSources(
+		hilink.MonitorSignal|hilink.MonitorStatus|hilink.MonitorTraffic|hilink.MonitorMonth,
+	))
+	go func() {
+		for err := range m.Errors() {
+			log.Printf("poll failed: %v", err)
+		}
+	}()
+	go func() {
+		for snap := range m.Snapshots() {
+			a.update(snap)
+		}
+	}()
+	go m.Run(ctx)
+	// serve
+	conn, err := net.ListenPacket("udp", listen)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	log.Printf("listening on %s", listen)
+	buf := make([]byte, 65535)
+	for {
+		n, addr, err := conn.ReadFrom(buf)
+		if err != nil {
+			return err
+		}
+		res, err := a.handle(buf[:n])
+		if err != nil {
+			if debug {
+				log.Printf("invalid request from %s: %v", addr, err)
+			}
+			continue
+		}
+		if _, err := conn.WriteTo(res, addr); err != nil {
+			log.Printf("unable to respond to %s: %v", addr, err)
+		}
+	}
+}
+
+// variable is a MIB variable.
+type variable struct {
+	oid   oid
+	value []byte
+}
+
+// agent is the SNMP agent.
+type agent struct {
+	community string
+	base      oid
+	vars      []variable
+	sync.Mutex
+}
+
+// update updates the MIB variables from the snapshot.
+func (a *agent) update(snap hilink.Snapshot) {
+	var vars []variable
+	add := func(group, id uint32, value []byte) {
+		vars = append(vars, variable{a.base.append(group, id, 0), value})
+	}
+	if s := snap.Signal; s != nil {
+		add(1, 1, encodeInt(tagInteger, int64(s.RSRP)))
+		add(1, 2, encodeInt(tagInteger, int64(s.RSRQ*10)))
+		add(1, 3, encodeInt(tagInteger, int64(s.SINR*10)))
+		add(1, 4, encodeInt(tagInteger, int64(s.RSSI)))
+		add(1, 5, encodeInt(tagInteger, int64(s.Band)))
+		add(1, 6, encodeUint(tagGauge32, s.CellID&0xffffffff))
+		add(1, 7, tlv(tagOctetString, []byte(s.PLMN)))
+	}
+	if s := snap.Status; s != nil {
+		add(2, 1, encodeInt(tagInteger, int64(s.ConnectionStatus)))
+		add(2, 2, truthValue(s.Connected()))
+		add(2, 3, encodeInt(tagInteger, int64(s.NetworkType)))
+		add(2, 4, encodeInt(tagInteger, int64(s.SignalIcon)))
+		add(2, 5, truthValue(s.Roaming))
+		ip := net.ParseIP(s.WanIPAddress).To4()
+		if ip == nil {
+			ip = net.IPv4zero.To4()
+		}
+		add(2, 6, tlv(tagIPAddress, ip))
+	}
+	if s := snap.Traffic; s != nil {
+		add(3, 1, encodeUint(tagCounter64, s.Upload))
+		add(3, 2, encodeUint(tagCounter64, s.Download))
+		add(3, 3, encodeUint(tagGauge32, s.UploadRate&0xffffffff))
+		add(3, 4, encodeUint(tagGauge32, s.DownloadRate&0xffffffff))
+		add(3, 5, encodeUint(tagTimeTicks, uint64(s.ConnectTime/(10*time.Millisecond))&0xffffffff))
+		add(3, 6, encodeUint(tagCounter64, s.TotalUpload))
+		add(3, 7, encodeUint(tagCounter64, s.TotalDownload))
+	}
+	if s := snap.Month; s != nil {
+		add(3, 8, encodeUint(tagCounter64, s.Upload))
+		add(3, 9, encodeUint(tagCounter64, s.Download))
+	}
+	sort.Slice(vars, func(i, j int) bool {
+		return vars[i].oid.compare(vars[j].oid) < 0
+	})
+	a.Lock()
+	defer a.Unlock()
+	a.vars = vars
+}
+
+// truthValue encodes a SNMPv2-TC TruthValue.
+func truthValue(b bool) []byte {
+	if b {
+		return encodeInt(tagInteger, 1)
+	}
+	return encodeInt(tagInteger, 2)
+}
+
+// handle handles a request message, returning the response message.
+func (a *agent) handle(buf []byte) ([]byte, error) {
+	msg, _, err := decode(buf)
+	if err != nil {
+		return nil, err
+	}
+	if msg.tag != tagSequence {
+		return nil, errInvalidBER
+	}
+	l, err := decodeAll(msg.value)
+	if err != nil {
+		return nil, err
+	}
+	if len(l) != 3 || l[1].tag != tagOctetString {
+		return nil, errInvalidBER
+	}
+	// only v2c is supported
+	if version, err := l[0].int(); err != nil || version != 1 {
+		return nil, fmt.Errorf("unsupported version")
+	}
+	if string(l[1].value) != a.community {
+		return nil, fmt.Errorf("invalid community")
+	}
+	pdu := l[2]
+	fields, err := decodeAll(pdu.value)
+	if err != nil {
+		return nil, err
+	}
+	if len(fields) != 4 || fields[3].tag != tagSequence {
+		return nil, errInvalidBER
+	}
+	reqID, err := fields[0].int()
+	if err != nil {
+		return nil, err
+	}
+	varbinds, err := decodeAll(fields[3].value)
+	if err != nil {
+		return nil, err
+	}
+	var oids []oid
+	for _, vb := range varbinds {
+		z, err := decodeAll(vb.value)
+		if err != nil || len(z) != 2 {
+			return nil, errInvalidBER
+		}
+		o, err := z[0].oid()
+		if err != nil {
+			return nil, err
+		}
+		oids = append(oids, o)
+	}
+	a.Lock()
+	vars := a.vars
+	a.Unlock()
+	var res []byte
+	switch pdu.tag {
+	case tagGetRequest:
+		for _, o := range oids {
+			res = append(res, get(vars, o)...)
+		}
+	case tagGetNextRequest:
+		for _, o := range oids {
+			res = append(res, getNext(vars, o)...)
+		}
+	case tagGetBulkRequest:
+		nonRepeaters, err := fields[1].int()
+		if err != nil {
+			return nil, err
+		}
+		maxRepetitions, err := fields[2].int()
+		if err != nil {
+			return nil, err
+		}
+		res = getBulk(vars, oids, int(nonRepeaters), int(maxRepetitions))
+	default:
+		return nil, fmt.Errorf("unsupported pdu type %#x", pdu.tag)
+	}
+	// build response
+	body := encodeInt(tagInteger, reqID)
+	body = append(body, encodeInt(tagInteger, 0)...)
+	body = append(body, encodeInt(tagInteger, 0)...)
+	body = append(body, tlv(tagSequence, res)...)
+	out := encodeInt(tagInteger, 1)
+	out = append(out, tlv(tagOctetString, []byte(a.community))...)
+	out = append(out, tlv(tagResponse, body)...)
+	return tlv(tagSequence, out), nil
+}
+
+// varbind encodes a variable binding.
+func varbind(o oid, value []byte) []byte {
+	return tlv(tagSequence, append(encodeOID(o), value...))
+}
+
+// get returns the varbind for o.
+func get(vars []variable, o oid) []byte {
+	i := sort.Search(len(vars), func(i int) bool {
+		return vars[i].oid.compare(o) >= 0
+	})
+	if i < len(vars) && vars[i].oid.compare(o) == 0 {
+		return varbind(o, vars[i].value)
+	}
+	return varbind(o, []byte{tagNoSuchObject, 0})
+}
+
+// next returns the index of the first variable after o.
+func next(vars []variable, o oid) int {
+	return sort.Search(len(vars), func(i int) bool {
+		return vars[i].oid.compare(o) > 0
+	})
+}
+
+// getNext returns the varbind for the variable following o.
+func getNext(vars []variable, o oid) []byte {
+	if i := next(vars, o); i < len(vars) {
+		return varbind(vars[i].oid, vars[i].value)
+	}
+	return varbind(o, []byte{tagEndOfMibView, 0})
+}
+
+// getBulk returns the varbinds for a get-bulk request.
+func getBulk(vars []variable, oids []oid, nonRepeaters, maxRepetitions int) []byte {
+	if nonRepeaters < 0 {
+		nonRepeaters = 0
+	}
+	if nonRepeaters > len(oids) {
+		nonRepeaters = len(oids)
+	}
+	if maxRepetitions > 100 {
+		maxRepetitions = 100
+	}
+	var res []byte
+	for _, o := range oids[:nonRepeaters] {
+		res = append(res, getNext(vars, o)...)
+	}
+	repeaters := append([]oid(nil), oids[nonRepeaters:]...)
+	for r, more := 0, true; r < maxRepetitions && len(repeaters) != 0 && more; r++ {
+		// stop after a repetition where every repeater reached the end
+		more = false
+		for j, o := range repeaters {
+			if i := next(vars, o); i < len(vars) {
+				res = append(res, varbind(vars[i].oid, vars[i].value)...)
+				repeaters[j], more = vars[i].oid, true
+			} else {
+				res = append(res, varbind(o, []byte{tagEndOfMibView, 0})...)
+			}
+		}
+	}
+	return res
+}
@@ -0,0 +1,168 @@
+// Command htgbot is a Telegram bot for Huawei Hilink devices.
+//
+// htgbot forwards incoming SMS messages to a Telegram chat, and accepts the
+// following commands from the chat:
+//
+//	/send <number> <text>  send a SMS message
+//	/status                show the connection status and signal
+//	/reboot                reboot the device
+//
+// Commands from other chats are ignored.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/kenshaw/hilink"
+)
+
+func main() {
+	endpoint := flag.String("endpoint", "http://192.168.8.1/", "api endpoint")
+	username := flag.String("username", "", "device username")
+	password := flag.String("password", "", "device password")
+	debug := flag.Bool("v", false, "enable verbose")
+	api := flag.String("api", "https://api.telegram.org", "telegram bot api server url")
+	token := flag.String("token", os.Getenv("HTGBOT_TOKEN"), "telegram bot token")
+	chat := flag.Int64("chat", 0, "telegram chat id")
+	interval := flag.Duration("interval", 10*time.Second, "sms poll interval")
+	flag.Parse()
+	if err := run(context.Background(), *endpoint, *username, *password, *debug, *api, *token, *chat, *interval); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, endpoint, username, password string, debug bool, api, token string, chat int64, interval time.Duration) error {
+	if token == "" {
+		return errors.New("must specify token")
+	}
+	if chat == 0 {
+		return errors.New("must specify chat")
+	}
+	// options
+	opts := []hilink.ClientOption{
+		hilink.WithURL(endpoint),
+		hilink.WithAuth(username, password),
+		// start the session and log in on the first request
+		hilink.WithNoStart(true),
+	}
+	if debug {
+		opts = append(opts, hilink.WithLogf(log.Printf))
+	}
+	// create client
+	b := &bot{
+		cl:   hilink.NewClient(opts...),
+		tg:   newTelegram(api, token),
+		chat: chat,
+	}
+	go b.forward(ctx, interval)
+	log.Printf("handling commands from chat %d", chat)
+	return b.tg.updates(ctx, b.handle)
+}
+
+// bot is the Telegram bot.
+type bot struct {
+	cl   *hilink.Client
+	tg   *telegram
+	chat int64
+}
+
+// forward forwards incoming SMS messages to the chat.
+func (b *bot) forward(ctx context.Context, interval time.Duration) {
+	// messages are only marked read once forwarded
+	_ = b.cl.WatchSmsFunc(ctx, interval, func(ctx context.Context, m hilink.SmsMessage) error {
+		text := fmt.Sprintf("SMS from %s (%s):\n%s", m.Phone, m.Date.Format("2006-01-02 15:04"), m.Content)
+		return b.tg.send(ctx, b.chat, text)
+	}, func(err error) {
+		log.Printf("watch failed: %v", err)
+	})
+}
+
+// handle handles a message from the chat.
+func (b *bot) handle(ctx context.Context, chat int64, text string) {
+	if chat != b.chat {
+		log.Printf("ignoring message from chat %d", chat)
+		return
+	}
+	fields := strings.Fields(text)
+	if len(fields) == 0 || !strings.HasPrefix(fields[0], "/") {
+		return
+	}
+	// strip bot name (ie, /status@mybot)
+	cmd := strings.SplitN(fields[0], "@", 2)[0]
+	var reply string
+	var err error
+	switch cmd {
+	case "/send":
+		reply, err = b.sendSms(ctx, text)
+	case "/status":
+		reply, err = b.status(ctx)
+	case "/reboot":
+		reply, err = b.reboot(ctx)
+	case "/start", "/help":
+		reply = "/send <number> <text> - send a SMS message\n/status - show the connection status\n/reboot - reboot the device"
+	default:
+		reply = "unknown command " + cmd
+	}
+	if err != nil {
+		reply = "error: " + err.Error()
+	}
+	if err := b.tg.send(ctx, b.chat, reply); err != nil {
+		log.Printf("unable to reply: %v", err)
+	}
+}
+
+// sendSms handles the /send command.
+func (b *bot) sendSms(ctx context.Context, text string) (string, error) {
+	// split into command, number and text, keeping the text as is
+	parts := strings.SplitN(strings.TrimSpace(text), " ", 3)
+	if len(parts) != 3 || strings.TrimSpace(parts[2]) == "" {
+		return "", errors.New("usage: /send <number> <text>")
+	}
+	to, msg := parts[1], strings.TrimSpace(parts[2])
+	ok, err := b.cl.SmsSend(ctx, msg, to)
+	switch {
+	case err != nil:
+		return "", err
+	case !ok:
+		return "", errors.New("could not send message")
+	}
+	return "message sent to " + to, nil
+}
+
+// status handles the /status command.
+func (b *bot) status(ctx context.Context) (string, error) {
+	s, err := b.cl.Status(ctx)
+	if err != nil {
+		return "", err
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "connected: %t\n", s.Connected())
+	fmt.Fprintf(&sb, "network type: %d\n", s.NetworkType)
+	fmt.Fprintf(&sb, "signal: %d/5\n", s.SignalIcon)
+	fmt.Fprintf(&sb, "roaming: %t\n", s.Roaming)
+	fmt.Fprintf(&sb, "wan ip: %s", s.WanIPAddress)
+	if cell, err := b.cl.ServingCell(ctx); err == nil {
+		fmt.Fprintf(&sb, "\nrsrp: %g dBm\nrsrq: %g dB\nsinr: %g dB", cell.RSRP, cell.RSRQ, cell.SINR)
+	}
+	return sb.String(), nil
+}
+
+// reboot handles the /reboot command.
+func (b *bot) reboot(ctx context.Context) (string, error) {
+	ok, err := b.cl.DeviceReboot(ctx)
+	switch {
+	case err != nil:
+		return "", err
+	case !ok:
+		return "", errors.New("could not reboot")
+	}
+	return "rebooting", nil
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// telegram is a minimal Telegram Bot API client.
+type telegram struct {
+	url string
+	cl  *http.Client
+}
+
+// newTelegram creates a new Telegram Bot API client for the api server.
+func newTelegram(api, token string) *telegram {
+	return &telegram{
+		url: strings.TrimSuffix(api, "/") + "/bot" + token + "/",
+		cl:  &http.Client{Timeout: 60 * time.Second},
+	}
+}
+
+// call calls the API method, decoding the result into v.
+func (t *telegram) call(ctx context.Context, method string, params, v interface{}) error {
+	buf, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest("POST", t.url+method, bytes.NewReader(buf))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	res, err := t.cl.Do(req.WithContext(ctx))
+	if err != nil {
+		// strip the url, as it contains the token
+		var urlErr interface{ Unwrap() error }
+		if errors.As(err, &urlErr) {
+			err = urlErr.Unwrap()
+		}
+		return err
+	}
+	defer res.Body.Close()
+	var r struct {
+		OK          bool            `json:"ok"`
+		Description string          `json:"description"`
+		Result      json.RawMessage `json:"result"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&r); err != nil {
+		return err
+	}
+	if !r.OK {
+		return errors.New("telegram: " + r.Description)
+	}
+	if v == nil {
+		return nil
+	}
+	return json.Unmarshal(r.Result, v)
+}
+
+// send sends a text message to the chat.
+func (t *telegram) send(ctx context.Context, chat int64, text string) error {
+	return t.call(ctx, "sendMessage", map[string]interface{}{
+		"chat_id": chat,
+		"text":    text,
+	}, nil)
+}
+
+// updates long polls for updates until ctx is done, calling f with the chat
+// id and text of each received message.
+func (t *telegram) updates(ctx context.Context, f func(context.Context, int64, string)) error {
+	var offset int64
+	for {
+		var l []struct {
+			UpdateID int64 `json:"update_id"`
+			Message  *struct {
+				Chat struct {
+					ID int64 `json:"id"`
+				} `json:"chat"`
+				Text string `json:"text"`
+			} `json:"message"`
+		}
+		err := t.call(ctx, "getUpdates", map[string]interface{}{
+			"offset":          offset,
+			"timeout":         50,
+			"allowed_updates": []string{"message"},
+		}, &l)
+		switch {
+		case err != nil && ctx.Err() != nil:
+			return ctx.Err()
+		case err != nil:
+			log.Printf("unable to get updates: %v", err)
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(5 * time.Second):
+			}
+			continue
+		}
+		for _, u := range l {
+			offset = u.UpdateID + 1
+			if u.Message != nil && u.Message.Text != "" {
+				f(ctx, u.Message.Chat.ID, u.Message.Text)
+			}
+		}
+	}
+}
@@ -0,0 +1,173 @@
+// Command hwatch is a connection watchdog for Huawei Hilink devices.
+//
+// hwatch periodically checks connectivity to an external target through the
+// modem, and when connectivity is lost, escalates by first reconnecting the
+// mobile connection (Disconnect/Connect), and then rebooting the device.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"time"
+
+	"github.com/kenshaw/hilink"
+)
+
+func main() {
+	endpoint := flag.String("endpoint", "http://192.168.8.1/", "api endpoint")
+	username := flag.String("username", "", "device username")
+	password := flag.String("password", "", "device password")
+	debug := flag.Bool("v", false, "enable verbose")
+	target := flag.String("target", "1.1.1.1:53", "target address (host:port) to check connectivity")
+	bind := flag.String("bind", "", "local address to check connectivity from (ie, the address on the modem's network)")
+	interval := flag.Duration("interval", 30*time.Second, "check interval")
+	timeout := flag.Duration("timeout", 5*time.Second, "check timeout")
+	failures := flag.Int("failures", 3, "consecutive failed checks before escalating")
+	cooldown := flag.Duration("cooldown", 2*time.Minute, "wait after each escalation before checking again")
+	noReboot := flag.Bool("noreboot", false, "do not reboot the device")
+	flag.Parse()
+	w := &watchdog{
+		target:   *target,
+		interval: *interval,
+		timeout:  *timeout,
+		failures: *failures,
+		cooldown: *cooldown,
+		noReboot: *noReboot,
+	}
+	if err := run(context.Background(), *endpoint, *username, *password, *debug, *bind, w); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, endpoint, username, password string, debug bool, bind string, w *watchdog) error {
+	// options
+	opts := []hilink.ClientOption{
+		hilink.WithURL(endpoint),
+		hilink.WithAuth(username, password),
+		// start the session and log in on the first request
+		hilink.WithNoStart(true),
+	}
+	if debug {
+		opts = append(opts, hilink.WithLogf(log.Printf))
+	}
+	// create client
+	w.cl = hilink.NewClient(opts...)
+	// dialer
+	w.dialer = &net.Dialer{Timeout: w.timeout}
+	if bind != "" {
+		ip := net.ParseIP(bind)
+		if ip == nil {
+			return fmt.Errorf("invalid bind address %q", bind)
+		}
+		w.dialer.LocalAddr = &net.TCPAddr{IP: ip}
+	}
+	if w.failures < 1 {
+		return fmt.Errorf("invalid failures %d", w.failures)
+	}
+	return w.run(ctx)
+}
+
+// escalation levels.
+const (
+	levelNone = iota
+	levelReconnect
+	levelReboot
+)
+
+// watchdog is the connection watchdog.
+type watchdog struct {
+	cl       *hilink.Client
+	dialer   *net.Dialer
+	target   string
+	interval time.Duration
+	timeout  time.Duration
+	failures int
+	cooldown time.Duration
+	noReboot bool
+}
+
+// run runs the watchdog until the context is closed.
+func (w *watchdog) run(ctx context.Context) error {
+	log.Printf("watching connectivity to %s every %v", w.target, w.interval)
+	failed, level := 0, levelNone
+	for {
+		err := w.check(ctx)
+		switch {
+		case err == nil && failed != 0:
+			log.Printf("connectivity restored after %d failed checks", failed)
+			failed, level = 0, levelNone
+		case err != nil:
+			failed++
+			log.Printf("check %d/%d failed: %v", failed, w.failures, err)
+		}
+		delay := w.interval
+		if failed >= w.failures {
+			level++
+			if level > levelReboot || (level == levelReboot && w.noReboot) {
+				// already at the highest level, start over
+				level = levelReconnect
+			}
+			if err := w.escalate(ctx, level); err != nil {
+				log.Printf("escalation failed: %v", err)
+			}
+			failed, delay = 0, w.cooldown
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+}
+
+// check checks connectivity to the target.
+func (w *watchdog) check(ctx context.Context) error {
+	conn, err := w.dialer.DialContext(ctx, "tcp", w.target)
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
+
+// escalate performs the escalation action for the level.
+func (w *watchdog) escalate(ctx context.Context, level int) error {
+	// start a new session and log in, as the device may have rebooted or
+	// expired the previous session
+	if err := w.cl.Relogin(ctx); err != nil {
+		return err
+	}
+	switch level {
+	case levelReconnect:
+		log.Printf("reconnecting")
+		if _, err := w.cl.Disconnect(ctx); err != nil {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(5 * time.Second):
+		}
+		ok, err := w.cl.Connect(ctx)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return fmt.Errorf("could not connect")
+		}
+	case levelReboot:
+		log.Printf("rebooting device")
+		ok, err := w.cl.DeviceReboot(ctx)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return fmt.Errorf("could not reboot")
+		}
+	}
+	return nil
+}
@@ -19,14 +19,16 @@ func main() {
 	msg := flag.String("msg", "", "message")
 	list := flag.Bool("list", false, "list sms messages in inbox")
 	count := flag.Uint("c", 50, "message count for -list")
+	export := flag.String("export", "", "export all sms messages (csv, jsonl, mbox)")
+	box := flag.Uint("box", uint(hilink.SmsBoxTypeInbox), "box type for -list and -export (1-inbox, 2-outbox, 3-draft)")
 	flag.Parse()
-	if err := run(context.Background(), *endpoint, *debug, *to, *msg, *list, *count); err != nil {
+	if err := run(context.Background(), *endpoint, *debug, *to, *msg, *list, *count, *export, hilink.SmsBoxType(*box)); err != nil {
 		fmt.Fprintf(os.Stderr, "error: %v\n", err)
 		os.Exit(1)
 	}
 }
 
-func run(ctx context.Context, endpoint string, debug bool, to, msg string, list bool, count uint) error {
+func run(ctx context.Context, endpoint string, debug bool, to, msg string, list bool, count uint, export string, box hilink.SmsBoxType) error {
 	// options
 	opts := []hilink.ClientOption{
 		hilink.WithURL(endpoint),
@@ -38,7 +40,11 @@ func run(ctx context.Context, endpoint string, debug bool, to, msg string, list
 	cl := hilink.NewClient(opts...)
 	// handle list
 	if list {
-		return doList(ctx, cl, hilink.SmsBoxTypeInbox, count)
+		return doList(ctx, cl, box, count)
+	}
+	// handle export
+	if export != "" {
+		return cl.SmsExport(ctx, os.Stdout, box, hilink.ExportFormat(export))
 	}
 	// check flags
 	if msg == "" {
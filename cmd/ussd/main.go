@@ -1,33 +1,39 @@
 package main
 
 import (
+	"bufio"
 	"context"
 	"errors"
 	"flag"
 	"fmt"
+	"io/ioutil"
 	"log"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/kenshaw/hilink"
+	"gopkg.in/yaml.v3"
 )
 
 func main() {
-	sleep := flag.Duration("t", 1*time.Second, "sleep duration between ussd api calls")
+	timeout := flag.Duration("t", 30*time.Second, "timeout waiting for ussd content")
 	endpoint := flag.String("endpoint", "http://192.168.8.1/", "api endpoint")
 	debug := flag.Bool("v", false, "enable verbose")
 	check := flag.Bool("check", false, "check ussd status")
 	code := flag.String("code", "", "ussd code to send")
 	noWait := flag.Bool("nowait", false, "exit immediately after sending ussd code")
 	release := flag.Bool("r", false, "release ussd session")
+	script := flag.String("script", "", "ussd script (yaml) to run")
+	interactive := flag.Bool("i", false, "interactive ussd session")
 	flag.Parse()
-	if err := run(context.Background(), *sleep, *endpoint, *debug, *check, *code, *noWait, *release); err != nil {
+	if err := run(context.Background(), *timeout, *endpoint, *debug, *check, *code, *noWait, *release, *script, *interactive); err != nil {
 		fmt.Fprintf(os.Stderr, "%v\n", err)
 		os.Exit(1)
 	}
 }
 
-func run(ctx context.Context, sleep time.Duration, endpoint string, debug, check bool, code string, noWait, release bool) error {
+func run(ctx context.Context, timeout time.Duration, endpoint string, debug, check bool, code string, noWait, release bool, script string, interactive bool) error {
 	// options
 	opts := []hilink.ClientOption{
 		hilink.WithURL(endpoint),
@@ -58,27 +64,104 @@ func run(ctx context.Context, sleep time.Duration, endpoint string, debug, check
 		fmt.Fprintf(os.Stdout, "received: %v\n", v)
 		return nil
 	}
+	if script != "" {
+		return doScript(ctx, cl, timeout, script)
+	}
+	if interactive {
+		return doInteractive(ctx, cl, timeout, code)
+	}
 	if code == "" {
 		return errors.New("no code provided")
 	}
-	// send ussd code
-	ok, err := cl.UssdCode(ctx, code)
+	// send ussd code and bail if not waiting
+	if noWait {
+		ok, err := cl.UssdCode(ctx, code)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return errors.New("could not send ussd code")
+		}
+		return nil
+	}
+	// send ussd code and wait for content
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	content, err := cl.UssdSendAndWait(ctx, code)
 	if err != nil {
 		return err
 	}
-	if !ok {
-		return errors.New("could not send ussd code")
+	_, err = os.Stdout.WriteString(content + "\n")
+	return err
+}
+
+// doScript runs a ussd script, writing the content of each step.
+func doScript(ctx context.Context, cl *hilink.Client, timeout time.Duration, name string) error {
+	buf, err := ioutil.ReadFile(name)
+	if err != nil {
+		return err
+	}
+	var script hilink.UssdScript
+	if err := yaml.Unmarshal(buf, &script); err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(len(script.Steps))*timeout)
+	defer cancel()
+	contents, err := cl.UssdRun(ctx, script)
+	for _, content := range contents {
+		fmt.Fprintln(os.Stdout, content)
 	}
-	// bail if not waiting
-	if !noWait {
-		time.Sleep(sleep)
+	return err
+}
+
+// doInteractive runs an interactive ussd session, displaying the ussd content
+// and reading replies from stdin until the session is released.
+func doInteractive(ctx context.Context, cl *hilink.Client, timeout time.Duration, code string) error {
+	in := bufio.NewScanner(os.Stdin)
+	// read code
+	if code == "" {
+		fmt.Fprint(os.Stdout, "code> ")
+		if !in.Scan() {
+			return in.Err()
+		}
+		if code = strings.TrimSpace(in.Text()); code == "" {
+			return errors.New("no code provided")
+		}
 	}
-	time.Sleep(sleep)
-	// grab content
-	content, err := cl.UssdContent(ctx)
+	// start session
+	sendCtx, cancel := context.WithTimeout(ctx, timeout)
+	sess, content, err := cl.UssdStart(sendCtx, code)
+	cancel()
 	if err != nil {
 		return err
 	}
-	_, err = os.Stdout.WriteString(content + "\n")
-	return err
+	defer sess.Close(ctx)
+	for {
+		fmt.Fprintln(os.Stdout, content)
+		// check if the network ended the session
+		state, err := cl.UssdStatus(ctx)
+		if err != nil {
+			return err
+		}
+		if state == hilink.UssdStateNone {
+			fmt.Fprintln(os.Stdout, "-- session ended --")
+			return nil
+		}
+		// read reply
+		fmt.Fprint(os.Stdout, "reply (empty to release)> ")
+		if !in.Scan() {
+			return in.Err()
+		}
+		reply := strings.TrimSpace(in.Text())
+		if reply == "" {
+			fmt.Fprintln(os.Stdout, "-- session released --")
+			return nil
+		}
+		sendCtx, cancel := context.WithTimeout(ctx, timeout)
+		content, err = sess.Reply(sendCtx, reply)
+		cancel()
+		if err != nil {
+			return err
+		}
+	}
 }
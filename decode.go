@@ -0,0 +1,89 @@
+//go:build !hilink_mxj
+// +build !hilink_mxj
+
+package hilink
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strings"
+)
+
+// xmlDecodeMap decodes the first element in buf into a map, keyed by the
+// element name, using a streaming encoding/xml decoder.
+//
+// Decoded values are always one of string (simple elements), XMLData-style
+// map[string]interface{} (elements with child elements or attributes), or
+// []interface{} (repeated elements), the same as the mxj package (see the
+// hilink_mxj build tag) with type casting disabled: attributes are keyed by
+// their name prefixed with a -, the text of elements with child elements is
+// keyed as #text, and empty elements are decoded as an empty string.
+func xmlDecodeMap(buf []byte) (map[string]interface{}, error) {
+	dec := xml.NewDecoder(bytes.NewReader(buf))
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		// skip everything preceding the root element
+		if start, ok := tok.(xml.StartElement); ok {
+			v, err := xmlDecodeElement(dec, start)
+			if err != nil {
+				return nil, err
+			}
+			return map[string]interface{}{start.Name.Local: v}, nil
+		}
+	}
+}
+
+// xmlDecodeElement decodes the element start, returning the element value.
+func xmlDecodeElement(dec *xml.Decoder, start xml.StartElement) (interface{}, error) {
+	var m map[string]interface{}
+	for _, a := range start.Attr {
+		if m == nil {
+			m = make(map[string]interface{})
+		}
+		m["-"+a.Name.Local] = a.Value
+	}
+	var text []byte
+	for {
+		tok, err := dec.Token()
+		switch {
+		case err == io.EOF:
+			return nil, io.ErrUnexpectedEOF
+		case err != nil:
+			return nil, err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			v, err := xmlDecodeElement(dec, t)
+			if err != nil {
+				return nil, err
+			}
+			if m == nil {
+				m = make(map[string]interface{})
+			}
+			// repeated elements are a list
+			switch prev := m[t.Name.Local].(type) {
+			case nil:
+				m[t.Name.Local] = v
+			case []interface{}:
+				m[t.Name.Local] = append(prev, v)
+			default:
+				m[t.Name.Local] = []interface{}{prev, v}
+			}
+		case xml.CharData:
+			text = append(text, t...)
+		case xml.EndElement:
+			s := strings.Trim(string(text), "\t\r\b\n ")
+			switch {
+			case m == nil:
+				return s, nil
+			case s != "":
+				m["#text"] = s
+			}
+			return m, nil
+		}
+	}
+}
@@ -0,0 +1,21 @@
+//go:build hilink_mxj
+// +build hilink_mxj
+
+package hilink
+
+import (
+	"github.com/clbanning/mxj/v2"
+)
+
+// xmlDecodeMap decodes the first element in buf into a map, keyed by the
+// element name, using the mxj package.
+//
+// The mxj based decoder is only used when building with the hilink_mxj build
+// tag, and is kept for compatibility.
+func xmlDecodeMap(buf []byte) (map[string]interface{}, error) {
+	m, err := mxj.NewMapXml(buf)
+	if err != nil {
+		return nil, err
+	}
+	return m, nil
+}
@@ -0,0 +1,256 @@
+package hilink
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DiscoverWait is the default time Discover waits for devices to respond,
+// when the context has no earlier deadline.
+const DiscoverWait = 3 * time.Second
+
+// DiscoverProtocol is a discovery protocol.
+type DiscoverProtocol string
+
+// DiscoverProtocol values.
+const (
+	DiscoverSSDP DiscoverProtocol = "ssdp"
+	DiscoverMDNS DiscoverProtocol = "mdns"
+	DiscoverScan DiscoverProtocol = "scan"
+)
+
+// DiscoveredDevice is a device found by Discover.
+type DiscoveredDevice struct {
+	// URL is the API endpoint of the device (ie, http://192.168.8.1/),
+	// usable with WithURL.
+	URL string
+	// Name is the device's friendly name (or mDNS instance name), and Model
+	// is the device's model name.
+	Name  string
+	Model string
+	// Firmware is the device's software version, when reported without
+	// logging in.
+	Firmware string
+	// Protocols are the protocols the device was found by.
+	Protocols []DiscoverProtocol
+}
+
+// DiscoverOption is a Discover option.
+type DiscoverOption func(*discoverer)
+
+// WithSSDP is a discover option that toggles SSDP (UPnP) discovery. Enabled
+// by default.
+func WithSSDP(enabled bool) DiscoverOption {
+	return func(d *discoverer) {
+		d.ssdp = enabled
+	}
+}
+
+// WithMDNS is a discover option that toggles mDNS discovery. Enabled by
+// default.
+func WithMDNS(enabled bool) DiscoverOption {
+	return func(d *discoverer) {
+		d.mdns = enabled
+	}
+}
+
+// WithMDNSServices is a discover option that sets the mDNS services queried
+// (default _http._tcp.local.).
+func WithMDNSServices(services ...string) DiscoverOption {
+	return func(d *discoverer) {
+		d.mdnsServices = services
+	}
+}
+
+// WithScan is a discover option that probes each address of the networks
+// (in CIDR notation, ie 192.168.8.0/24) for the HiLink API, as a fallback when
+// multicast discovery is blocked. The scan runs until all addresses are
+// probed or the context is closed. Networks larger than /16 are not allowed.
+func WithScan(networks ...string) DiscoverOption {
+	return func(d *discoverer) {
+		for _, network := range networks {
+			_, ipnet, err := net.ParseCIDR(network)
+			if err != nil || ipnet.IP.To4() == nil {
+				d.err = fmt.Errorf("%w: %q", ErrInvalidAddress, network)
+				return
+			}
+			if ones, _ := ipnet.Mask.Size(); ones < 16 {
+				d.err = fmt.Errorf("%w: network %q too large", ErrInvalidAddress, network)
+				return
+			}
+			d.scan = append(d.scan, ipnet)
+		}
+	}
+}
+
+// discoverer holds the discover options, and the devices found.
+type discoverer struct {
+	ssdp         bool
+	mdns         bool
+	mdnsServices []string
+	scan         []*net.IPNet
+	// err is the first invalid option error.
+	err     error
+	devices map[string]*DiscoveredDevice
+	sync.Mutex
+}
+
+// Discover finds the devices on the local network using SSDP (UPnP) and
+// mDNS, and by scanning the networks passed with WithScan, merging the
+// devices found by each protocol. Waits for responses until the deadline of
+// ctx, or DiscoverWait, returning the devices found.
+func Discover(ctx context.Context, opts ...DiscoverOption) ([]DiscoveredDevice, error) {
+	d := &discoverer{
+		ssdp:         true,
+		mdns:         true,
+		mdnsServices: []string{"_http._tcp.local."},
+		devices:      make(map[string]*DiscoveredDevice),
+	}
+	for _, o := range opts {
+		o(d)
+	}
+	if d.err != nil {
+		return nil, d.err
+	}
+	wait := DiscoverWait
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < wait {
+		wait = time.Until(deadline)
+	}
+	var searches []func(context.Context, time.Duration) error
+	if d.ssdp {
+		searches = append(searches, d.searchSSDP)
+	}
+	if d.mdns && len(d.mdnsServices) != 0 {
+		searches = append(searches, d.searchMDNS)
+	}
+	if len(d.scan) != 0 {
+		searches = append(searches, d.searchScan)
+	}
+	errs := make(chan error, len(searches))
+	for _, f := range searches {
+		go func(f func(context.Context, time.Duration) error) {
+			errs <- f(ctx, wait)
+		}(f)
+	}
+	// only fail when every protocol failed (ie, no network)
+	var err error
+	failed := 0
+	for range searches {
+		if e := <-errs; e != nil {
+			err, failed = e, failed+1
+		}
+	}
+	// the deadline of ctx only ends the search
+	if e := ctx.Err(); e == context.Canceled {
+		return nil, e
+	}
+	if failed != 0 && failed == len(searches) {
+		return nil, err
+	}
+	devices := make([]DiscoveredDevice, 0, len(d.devices))
+	for _, z := range d.devices {
+		devices = append(devices, *z)
+	}
+	sort.Slice(devices, func(i, j int) bool {
+		return devices[i].URL < devices[j].URL
+	})
+	return devices, nil
+}
+
+// add adds a found device, merging it with a device previously found with
+// the same URL.
+func (d *discoverer) add(dev DiscoveredDevice, protocol DiscoverProtocol) {
+	d.Lock()
+	defer d.Unlock()
+	z, ok := d.devices[dev.URL]
+	if !ok {
+		dev.Protocols = []DiscoverProtocol{protocol}
+		d.devices[dev.URL] = &dev
+		return
+	}
+	if z.Name == "" {
+		z.Name = dev.Name
+	}
+	if z.Model == "" {
+		z.Model = dev.Model
+	}
+	if z.Firmware == "" {
+		z.Firmware = dev.Firmware
+	}
+	for _, p := range z.Protocols {
+		if p == protocol {
+			return
+		}
+	}
+	z.Protocols = append(z.Protocols, protocol)
+}
+
+// search sends req to the multicast address, calling f with each response
+// until wait elapses or ctx is closed. Waits for any funcs f adds to the wait
+// group before returning.
+func search(ctx context.Context, wait time.Duration, addr string, req []byte, f func(*sync.WaitGroup, []byte, net.Addr)) error {
+	conn, err := net.ListenPacket("udp4", ":0")
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	a, err := net.ResolveUDPAddr("udp4", addr)
+	if err != nil {
+		return err
+	}
+	// send request, twice as udp is unreliable
+	for i := 0; i < 2; i++ {
+		if _, err := conn.WriteTo(req, a); err != nil {
+			return err
+		}
+	}
+	if err := conn.SetReadDeadline(time.Now().Add(wait)); err != nil {
+		return err
+	}
+	// close the connection when the context is closed
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.Close()
+		case <-done:
+		}
+	}()
+	var wg sync.WaitGroup
+	buf := make([]byte, 9000)
+	for {
+		n, from, err := conn.ReadFrom(buf)
+		if err != nil {
+			break
+		}
+		f(&wg, append([]byte(nil), buf[:n]...), from)
+	}
+	wg.Wait()
+	return nil
+}
+
+// probeDevice probes the endpoint for the HiLink API, returning an error when
+// the endpoint does not respond with a session and token (the HiLink
+// signature). The model and firmware are retrieved from the basic device
+// information.
+func probeDevice(ctx context.Context, endpoint string, timeout time.Duration) (*DiscoveredDevice, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	cl := NewClient(WithURL(endpoint), WithTimeout(timeout))
+	if _, _, err := cl.NewSessionAndTokenID(ctx); err != nil {
+		return nil, err
+	}
+	dev := &DiscoveredDevice{
+		URL: endpoint,
+	}
+	if res, err := cl.DeviceBasicInfo(ctx); err == nil {
+		dev.Model = xmlStr(res, "devicename", "DeviceName")
+		dev.Firmware = xmlStr(res, "SoftwareVersion")
+	}
+	return dev, nil
+}
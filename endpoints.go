@@ -0,0 +1,107 @@
+// Code generated by internal/endpointgen. DO NOT EDIT.
+
+package hilink
+
+import (
+	"context"
+)
+
+// WlanBasicSettings is the basic Wi-Fi settings.
+type WlanBasicSettings struct {
+	Ssid    string
+	Hidden  bool
+	Channel int
+	Country string
+	Mode    string
+	Enabled bool
+}
+
+// WlanBasicSettings retrieves the basic Wi-Fi settings.
+func (cl *Client) WlanBasicSettings(ctx context.Context) (*WlanBasicSettings, error) {
+	res, err := cl.Do(ctx, "api/wlan/basic-settings", nil)
+	if err != nil {
+		return nil, err
+	}
+	return &WlanBasicSettings{
+		Ssid:    xmlStr(res, "WifiSsid"),
+		Hidden:  xmlStr(res, "WifiHide") == "1",
+		Channel: xmlInt(res, "WifiChannel"),
+		Country: xmlStr(res, "WifiCountry"),
+		Mode:    xmlStr(res, "WifiMode"),
+		Enabled: xmlStr(res, "WifiEnable") == "1",
+	}, nil
+}
+
+// BasicSettings retrieves the basic Wi-Fi settings.
+func (svc *WlanService) BasicSettings(ctx context.Context) (*WlanBasicSettings, error) {
+	return svc.cl.WlanBasicSettings(ctx)
+}
+
+// wlanBasicSettingsSetRequest is the WlanBasicSettingsSet request (order matters below!).
+type wlanBasicSettingsSetRequest struct {
+	WifiSsid    string `hilink:"WifiSsid"`
+	WifiHide    bool   `hilink:"WifiHide"`
+	WifiRestart string `hilink:"WifiRestart"`
+}
+
+// WlanBasicSettingsSet sets the Wi-Fi SSID and whether the SSID is hidden, restarting Wi-Fi.
+func (cl *Client) WlanBasicSettingsSet(ctx context.Context, ssid string, hidden bool) (bool, error) {
+	return cl.doReqCheckOK(ctx, "api/wlan/basic-settings", wlanBasicSettingsSetRequest{
+		WifiSsid:    ssid,
+		WifiHide:    hidden,
+		WifiRestart: "1",
+	})
+}
+
+// BasicSettingsSet sets the Wi-Fi SSID and whether the SSID is hidden, restarting Wi-Fi.
+func (svc *WlanService) BasicSettingsSet(ctx context.Context, ssid string, hidden bool) (bool, error) {
+	return svc.cl.WlanBasicSettingsSet(ctx, ssid, hidden)
+}
+
+// AutoApn retrieves whether the APN is automatically selected.
+func (cl *Client) AutoApn(ctx context.Context) (bool, error) {
+	res, err := cl.Do(ctx, "api/dialup/auto-apn", nil)
+	if err != nil {
+		return false, err
+	}
+	return xmlStr(res, "AutoAPN") == "1", nil
+}
+
+// AutoApn retrieves whether the APN is automatically selected.
+func (svc *NetService) AutoApn(ctx context.Context) (bool, error) {
+	return svc.cl.AutoApn(ctx)
+}
+
+// autoApnSetRequest is the AutoApnSet request (order matters below!).
+type autoApnSetRequest struct {
+	AutoAPN bool `hilink:"AutoAPN"`
+}
+
+// AutoApnSet enables or disables automatic APN selection.
+func (cl *Client) AutoApnSet(ctx context.Context, enabled bool) (bool, error) {
+	return cl.doReqCheckOK(ctx, "api/dialup/auto-apn", autoApnSetRequest{
+		AutoAPN: enabled,
+	})
+}
+
+// AutoApnSet enables or disables automatic APN selection.
+func (svc *NetService) AutoApnSet(ctx context.Context, enabled bool) (bool, error) {
+	return svc.cl.AutoApnSet(ctx, enabled)
+}
+
+// networkReconnectRequest is the NetworkReconnect request (order matters below!).
+type networkReconnectRequest struct {
+	ReconnectAction string `hilink:"ReconnectAction"`
+}
+
+// NetworkReconnect reconnects to the mobile network.
+func (cl *Client) NetworkReconnect(ctx context.Context) (bool, error) {
+	return cl.doReqCheckOK(ctx, "api/net/reconnect", networkReconnectRequest{
+		ReconnectAction: "1",
+	})
+}
+
+// Reconnect reconnects to the mobile network.
+func (svc *NetService) Reconnect(ctx context.Context) (bool, error) {
+	return svc.cl.NetworkReconnect(ctx)
+}
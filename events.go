@@ -0,0 +1,127 @@
+package hilink
+
+// EventType represents the different monitor event types.
+type EventType int
+
+// EventType values.
+const (
+	EventConnectionUp EventType = iota
+	EventConnectionDown
+	EventNetworkTypeChanged
+	EventRoamingChanged
+	EventSmsReceived
+)
+
+// String satisfies the fmt.Stringer interface.
+func (typ EventType) String() string {
+	switch typ {
+	case EventConnectionUp:
+		return "ConnectionUp"
+	case EventConnectionDown:
+		return "ConnectionDown"
+	case EventNetworkTypeChanged:
+		return "NetworkTypeChanged"
+	case EventRoamingChanged:
+		return "RoamingChanged"
+	case EventSmsReceived:
+		return "SmsReceived"
+	}
+	return "Unknown"
+}
+
+// Event is a device state change event, determined by a Monitor by diffing
+// consecutive snapshots.
+type Event struct {
+	Type EventType
+	// Previous and Current are the snapshots from which the event was
+	// determined.
+	Previous *Snapshot
+	Current  *Snapshot
+}
+
+// diffSnapshots determines the events between consecutive snapshots.
+func diffSnapshots(prev, curr *Snapshot) []Event {
+	var events []Event
+	add := func(typ EventType) {
+		events = append(events, Event{Type: typ, Previous: prev, Current: curr})
+	}
+	if prev.Status != nil && curr.Status != nil {
+		switch {
+		case !prev.Status.Connected() && curr.Status.Connected():
+			add(EventConnectionUp)
+		case prev.Status.Connected() && !curr.Status.Connected():
+			add(EventConnectionDown)
+		}
+		if prev.Status.NetworkType != curr.Status.NetworkType {
+			add(EventNetworkTypeChanged)
+		}
+		if prev.Status.Roaming != curr.Status.Roaming {
+			add(EventRoamingChanged)
+		}
+	}
+	if prev.Sms != nil && curr.Sms != nil {
+		if curr.Sms.LocalInbox > prev.Sms.LocalInbox || curr.Sms.LocalUnread > prev.Sms.LocalUnread {
+			add(EventSmsReceived)
+		}
+	}
+	return events
+}
+
+// subscription is an event subscription.
+type subscription struct {
+	types  map[EventType]bool
+	events chan Event
+}
+
+// Subscribe subscribes to the events of the specified types, or all events
+// when no types are specified. Events are delivered on the returned channel,
+// which has a small buffer; events are dropped when a subscriber does not
+// keep up. The returned func cancels the subscription, and closes the
+// channel. The channel is also closed when Run returns.
+func (m *Monitor) Subscribe(types ...EventType) (<-chan Event, func()) {
+	sub := &subscription{
+		types:  make(map[EventType]bool),
+		events: make(chan Event, 16),
+	}
+	for _, typ := range types {
+		sub.types[typ] = true
+	}
+	m.Lock()
+	defer m.Unlock()
+	m.subs[sub] = true
+	return sub.events, func() {
+		m.Lock()
+		defer m.Unlock()
+		if m.subs[sub] {
+			delete(m.subs, sub)
+			close(sub.events)
+		}
+	}
+}
+
+// publish publishes the events to the subscribers.
+func (m *Monitor) publish(events []Event) {
+	m.Lock()
+	defer m.Unlock()
+	for _, ev := range events {
+		for sub := range m.subs {
+			if len(sub.types) != 0 && !sub.types[ev.Type] {
+				continue
+			}
+			select {
+			case sub.events <- ev:
+			default:
+			}
+		}
+	}
+}
+
+// closeSubs closes all subscriptions.
+func (m *Monitor) closeSubs() {
+	m.Lock()
+	defer m.Unlock()
+	for sub := range m.subs {
+		delete(m.subs, sub)
+		close(sub.events)
+	}
+}
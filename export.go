@@ -0,0 +1,143 @@
+package hilink
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// ExportFormat represents the different SMS export formats.
+type ExportFormat string
+
+// ExportFormat values.
+const (
+	ExportFormatCSV   ExportFormat = "csv"
+	ExportFormatJSONL ExportFormat = "jsonl"
+	ExportFormatMbox  ExportFormat = "mbox"
+)
+
+// exportMessage is the normalized form of an exported SMS message.
+type exportMessage struct {
+	Index   int    `json:"index"`
+	Phone   string `json:"phone"`
+	Date    string `json:"date"`
+	Unread  bool   `json:"unread"`
+	Type    int    `json:"type"`
+	Content string `json:"content"`
+}
+
+// NormalizePhone normalizes a phone number, removing all formatting except
+// for a leading + (ie, "+1 (555) 123-4567" becomes "+15551234567").
+func NormalizePhone(phone string) string {
+	phone = strings.TrimSpace(phone)
+	var b strings.Builder
+	for i, r := range phone {
+		if (r >= '0' && r <= '9') || (r == '+' && i == 0) {
+			b.WriteRune(r)
+		}
+	}
+	if b.Len() == 0 || b.String() == "+" {
+		// alphanumeric sender ids
+		return phone
+	}
+	return b.String()
+}
+
+// ExportSms writes msgs to w in the specified format. Timestamps are written
+// in RFC3339 format, and phone numbers are normalized with NormalizePhone.
+func ExportSms(w io.Writer, format ExportFormat, msgs []SmsMessage) error {
+	switch format {
+	case ExportFormatCSV:
+		return exportCSV(w, msgs)
+	case ExportFormatJSONL:
+		return exportJSONL(w, msgs)
+	case ExportFormatMbox:
+		return exportMbox(w, msgs)
+	}
+	return fmt.Errorf("unknown export format %q", format)
+}
+
+// SmsExport writes all SMS messages in an inbox to w in the specified format.
+func (cl *Client) SmsExport(ctx context.Context, w io.Writer, boxType SmsBoxType, format ExportFormat) error {
+	msgs, err := cl.SmsListAll(ctx, boxType)
+	if err != nil {
+		return err
+	}
+	return ExportSms(w, format, msgs)
+}
+
+// normalizeMessage converts a message to its normalized export form.
+func normalizeMessage(m SmsMessage) exportMessage {
+	return exportMessage{
+		Index:   m.Index,
+		Phone:   NormalizePhone(m.Phone),
+		Date:    m.Date.Format(time.RFC3339),
+		Unread:  m.Unread(),
+		Type:    int(m.Type),
+		Content: m.Content,
+	}
+}
+
+// exportCSV writes msgs as CSV with a header row.
+func exportCSV(w io.Writer, msgs []SmsMessage) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"index", "phone", "date", "unread", "type", "content"}); err != nil {
+		return err
+	}
+	for _, m := range msgs {
+		e := normalizeMessage(m)
+		if err := cw.Write([]string{
+			fmt.Sprintf("%d", e.Index),
+			e.Phone,
+			e.Date,
+			fmt.Sprintf("%t", e.Unread),
+			fmt.Sprintf("%d", e.Type),
+			e.Content,
+		}); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// exportJSONL writes msgs as JSON Lines.
+func exportJSONL(w io.Writer, msgs []SmsMessage) error {
+	enc := json.NewEncoder(w)
+	for _, m := range msgs {
+		if err := enc.Encode(normalizeMessage(m)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// exportMbox writes msgs in mbox (mboxrd) format.
+func exportMbox(w io.Writer, msgs []SmsMessage) error {
+	for _, m := range msgs {
+		e := normalizeMessage(m)
+		var b strings.Builder
+		fmt.Fprintf(&b, "From %s %s\n", e.Phone, m.Date.UTC().Format(time.ANSIC))
+		fmt.Fprintf(&b, "From: %s\n", e.Phone)
+		fmt.Fprintf(&b, "Date: %s\n", m.Date.Format(time.RFC1123Z))
+		fmt.Fprintf(&b, "Subject: SMS from %s\n", e.Phone)
+		fmt.Fprintf(&b, "X-Sms-Index: %d\n", e.Index)
+		b.WriteString("Content-Type: text/plain; charset=utf-8\n\n")
+		for _, line := range strings.Split(strings.Replace(m.Content, "\r\n", "\n", -1), "\n") {
+			// escape From lines (mboxrd)
+			if strings.HasPrefix(strings.TrimLeft(line, ">"), "From ") {
+				line = ">" + line
+			}
+			b.WriteString(line + "\n")
+		}
+		b.WriteString("\n")
+		if _, err := io.WriteString(w, b.String()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
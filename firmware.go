@@ -0,0 +1,56 @@
+package hilink
+
+import (
+	"context"
+	"time"
+)
+
+// FirmwareUpgradeStart acknowledges the new firmware version found by the
+// last firmware update check, starting the download and install of the
+// firmware.
+func (cl *Client) FirmwareUpgradeStart(ctx context.Context) (bool, error) {
+	return cl.doReqCheckOK(ctx, "api/online-update/ack-newversion", NewRequest(
+		"UserAckNewVersion", "1",
+	))
+}
+
+// FirmwareUpgradeCancel cancels the download of a firmware upgrade.
+func (cl *Client) FirmwareUpgradeCancel(ctx context.Context) (bool, error) {
+	return cl.doReqCheckOK(ctx, "api/online-update/cancel-downloading", NewRequest())
+}
+
+// FirmwareUpgradeWait polls the firmware update status every interval,
+// passing each status to progress (if not nil), until the download and
+// install of all components completes or ctx is done.
+//
+// As the device reboots to install the firmware, a failed request after the
+// final component has reached 100% is treated as completion.
+func (cl *Client) FirmwareUpgradeWait(ctx context.Context, interval time.Duration, progress func(FirmwareStatus)) (*FirmwareStatus, error) {
+	var last *FirmwareStatus
+	for {
+		status, err := cl.FirmwareUpdateStatus(ctx)
+		switch {
+		case err != nil && last != nil && last.complete():
+			return last, nil
+		case err != nil:
+			return last, err
+		}
+		if progress != nil {
+			progress(*status)
+		}
+		if status.complete() {
+			return status, nil
+		}
+		last = status
+		select {
+		case <-ctx.Done():
+			return last, ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
+
+// complete returns true when the last component has reached 100%.
+func (s *FirmwareStatus) complete() bool {
+	return s.Progress >= 100 && (s.Components == 0 || s.Component+1 >= s.Components)
+}
@@ -0,0 +1,238 @@
+package hilink
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// FixtureMode is the fixture transport mode.
+type FixtureMode int
+
+// FixtureMode values.
+const (
+	// FixtureRecord sends requests to the device, recording the responses.
+	FixtureRecord FixtureMode = iota
+	// FixtureReplay replays recorded responses, without sending requests.
+	FixtureReplay
+)
+
+// FixtureScrubElements are the XML elements whose values are replaced when
+// recording fixtures, so that recorded fixtures do not leak credentials,
+// session tokens, or device and subscriber identifiers.
+var FixtureScrubElements = []string{
+	"SesInfo", "TokInfo", "Username", "Password", "password", "password_type",
+	"Imei", "Imsi", "Iccid", "SerialNumber", "Msisdn", "MacAddress1",
+	"MacAddress2", "WifiMacAddrWl0", "WifiMacAddrWl1", "WanIPAddress",
+	"WanIPv6Address", "PrimaryDns", "SecondaryDns", "PrimaryIPv6Dns",
+	"SecondaryIPv6Dns", "WifiSsid", "WifiWpapsk", "WifiWepKey1",
+}
+
+// fixtureScrubbedValue is the value of scrubbed elements and headers.
+const fixtureScrubbedValue = "scrubbed"
+
+// fixture is a recorded request and response.
+type fixture struct {
+	Method      string `json:"method"`
+	Path        string `json:"path"`
+	Request     string `json:"request,omitempty"`
+	StatusCode  int    `json:"status_code"`
+	ContentType string `json:"content_type,omitempty"`
+	Token       string `json:"token,omitempty"`
+	Response    string `json:"response"`
+}
+
+// FixtureTransport is a http.RoundTripper that records device requests and
+// responses to fixture files in a directory, and replays them later, so that
+// integration tests and bug reports can be reproduced without the device.
+//
+// Fixtures are stored as JSON, one file per request, named by the request
+// method, path, and the sequence number of the request for the path. When
+// replaying, the n-th request for a path is answered by the n-th recorded
+// fixture for the path, with the last fixture reused once exhausted.
+//
+// When recording, the values of the FixtureScrubElements in requests and
+// responses, and the CSRF token and cookie headers, are scrubbed.
+type FixtureTransport struct {
+	dir       string
+	mode      FixtureMode
+	transport http.RoundTripper
+	scrub     *regexp.Regexp
+	counts    map[string]int
+	// base is the fixture transport the transport was copied from, that
+	// counts the requests for both (see wrap).
+	base *FixtureTransport
+	sync.Mutex
+}
+
+// NewFixtureTransport creates a new fixture transport for the directory. When
+// recording, requests are sent using transport (or http.DefaultTransport when
+// nil).
+func NewFixtureTransport(dir string, mode FixtureMode, transport http.RoundTripper) *FixtureTransport {
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	names := make([]string, len(FixtureScrubElements))
+	for i, name := range FixtureScrubElements {
+		names[i] = regexp.QuoteMeta(name)
+	}
+	return &FixtureTransport{
+		dir:       dir,
+		mode:      mode,
+		transport: transport,
+		scrub:     regexp.MustCompile(`<(` + strings.Join(names, "|") + `)>[^<]*</`),
+		counts:    make(map[string]int),
+	}
+}
+
+// RoundTrip satisfies the http.RoundTripper interface.
+func (t *FixtureTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	name := t.next(req)
+	if t.mode == FixtureReplay {
+		return t.replay(req, name)
+	}
+	return t.record(req, name)
+}
+
+// wrapped satisfies the transportWrapper interface.
+func (t *FixtureTransport) wrapped() http.RoundTripper {
+	return t.transport
+}
+
+// wrap satisfies the transportWrapper interface. The returned copy shares
+// the request counts with the transport.
+func (t *FixtureTransport) wrap(transport http.RoundTripper) http.RoundTripper {
+	base := t
+	if t.base != nil {
+		base = t.base
+	}
+	return &FixtureTransport{
+		dir:       t.dir,
+		mode:      t.mode,
+		transport: transport,
+		scrub:     t.scrub,
+		base:      base,
+	}
+}
+
+// next returns the base fixture name for the request, incrementing the
+// request count for the path.
+func (t *FixtureTransport) next(req *http.Request) string {
+	if t.base != nil {
+		return t.base.next(req)
+	}
+	t.Lock()
+	defer t.Unlock()
+	key := req.Method + " " + req.URL.Path
+	t.counts[key]++
+	path := strings.Trim(req.URL.Path, "/")
+	if path == "" {
+		path = "index"
+	}
+	path = strings.NewReplacer("/", "_", ".", "_").Replace(path)
+	return fmt.Sprintf("%s_%s_%03d.json", strings.ToLower(req.Method), path, t.counts[key])
+}
+
+// record sends the request, recording the scrubbed request and response.
+func (t *FixtureTransport) record(req *http.Request, name string) (*http.Response, error) {
+	f := fixture{
+		Method: req.Method,
+		Path:   req.URL.Path,
+	}
+	if req.Body != nil {
+		buf, err := ioutil.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		req.Body = ioutil.NopCloser(bytes.NewReader(buf))
+		f.Request = t.scrubBody(buf)
+	}
+	res, err := t.transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	buf, err := ioutil.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	res.Body = ioutil.NopCloser(bytes.NewReader(buf))
+	f.StatusCode = res.StatusCode
+	f.ContentType = res.Header.Get("Content-Type")
+	if res.Header.Get(TokenHeader) != "" {
+		f.Token = fixtureScrubbedValue
+	}
+	f.Response = t.scrubBody(buf)
+	out := new(bytes.Buffer)
+	enc := json.NewEncoder(out)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(f); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(t.dir, 0o755); err != nil {
+		return nil, err
+	}
+	if err := ioutil.WriteFile(filepath.Join(t.dir, name), out.Bytes(), 0o644); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// replay returns the recorded response for the request.
+func (t *FixtureTransport) replay(req *http.Request, name string) (*http.Response, error) {
+	if req.Body != nil {
+		req.Body.Close()
+	}
+	buf, err := ioutil.ReadFile(filepath.Join(t.dir, name))
+	if os.IsNotExist(err) {
+		// reuse the last recorded fixture for the path
+		prefix := name[:len(name)-len("000.json")]
+		l, _ := filepath.Glob(filepath.Join(t.dir, prefix+"[0-9][0-9][0-9].json"))
+		if len(l) == 0 {
+			return nil, fmt.Errorf("no fixture for %s %s", req.Method, req.URL.Path)
+		}
+		buf, err = ioutil.ReadFile(l[len(l)-1])
+	}
+	if err != nil {
+		return nil, err
+	}
+	var f fixture
+	if err := json.Unmarshal(buf, &f); err != nil {
+		return nil, err
+	}
+	res := &http.Response{
+		Status:        fmt.Sprintf("%d %s", f.StatusCode, http.StatusText(f.StatusCode)),
+		StatusCode:    f.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        make(http.Header),
+		Body:          ioutil.NopCloser(strings.NewReader(f.Response)),
+		ContentLength: int64(len(f.Response)),
+		Request:       req,
+	}
+	if f.ContentType != "" {
+		res.Header.Set("Content-Type", f.ContentType)
+	}
+	if f.Token != "" {
+		res.Header.Set(TokenHeader, f.Token)
+	}
+	return res, nil
+}
+
+// scrubBody scrubs the values of the FixtureScrubElements in buf.
+func (t *FixtureTransport) scrubBody(buf []byte) string {
+	return t.scrub.ReplaceAllStringFunc(string(buf), func(s string) string {
+		name := s[1:strings.IndexByte(s, '>')]
+		return "<" + name + ">" + fixtureScrubbedValue + "</"
+	})
+}
@@ -0,0 +1,198 @@
+package hilink
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// DefaultParallelism is the default number of devices a Fleet operates on
+// concurrently.
+const DefaultParallelism = 8
+
+// Fleet is a set of named clients (ie, the devices of an SMS gateway), that
+// runs operations across all clients concurrently, with bounded parallelism.
+type Fleet struct {
+	parallelism int
+	clients     map[string]*Client
+	mu          sync.RWMutex
+}
+
+// FleetOption is a fleet option.
+type FleetOption func(*Fleet)
+
+// WithParallelism is a fleet option to set the maximum number of devices
+// operated on concurrently.
+func WithParallelism(parallelism int) FleetOption {
+	return func(f *Fleet) {
+		if parallelism > 0 {
+			f.parallelism = parallelism
+		}
+	}
+}
+
+// NewFleet creates a new fleet.
+func NewFleet(opts ...FleetOption) *Fleet {
+	f := &Fleet{
+		parallelism: DefaultParallelism,
+		clients:     make(map[string]*Client),
+	}
+	for _, o := range opts {
+		o(f)
+	}
+	return f
+}
+
+// Add adds the client to the fleet, replacing any client with the same name.
+func (f *Fleet) Add(name string, cl *Client) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.clients[name] = cl
+}
+
+// Remove removes the named client from the fleet.
+func (f *Fleet) Remove(name string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.clients, name)
+}
+
+// Client returns the named client, or nil when not in the fleet.
+func (f *Fleet) Client(name string) *Client {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.clients[name]
+}
+
+// Names returns the sorted names of the clients in the fleet.
+func (f *Fleet) Names() []string {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	names := make([]string, 0, len(f.clients))
+	for name := range f.clients {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// FleetResult is the result of an operation on a client of a fleet.
+type FleetResult struct {
+	Name  string
+	Value interface{}
+	Err   error
+}
+
+// Map calls fn for each client of the fleet, with at most the fleet's
+// parallelism calls running concurrently, returning the results sorted by
+// name. Clients not yet operated on when ctx is closed have the context
+// error as their result.
+func (f *Fleet) Map(ctx context.Context, fn func(ctx context.Context, name string, cl *Client) (interface{}, error)) []FleetResult {
+	f.mu.RLock()
+	names := make([]string, 0, len(f.clients))
+	clients := make(map[string]*Client, len(f.clients))
+	for name, cl := range f.clients {
+		names, clients[name] = append(names, name), cl
+	}
+	f.mu.RUnlock()
+	sort.Strings(names)
+	res := make([]FleetResult, len(names))
+	sem := make(chan struct{}, f.parallelism)
+	var wg sync.WaitGroup
+	for i, name := range names {
+		res[i].Name = name
+		// check first, as select picks a ready case at random
+		if err := ctx.Err(); err != nil {
+			res[i].Err = err
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			res[i].Err = ctx.Err()
+			continue
+		case sem <- struct{}{}:
+		}
+		wg.Add(1)
+		go func(i int, name string) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			res[i].Value, res[i].Err = fn(ctx, name, clients[name])
+		}(i, name)
+	}
+	wg.Wait()
+	return res
+}
+
+// Do calls fn for each client of the fleet, as with Map, returning a
+// *FleetError with the failed clients' errors when any call fails.
+func (f *Fleet) Do(ctx context.Context, fn func(ctx context.Context, name string, cl *Client) error) error {
+	res := f.Map(ctx, func(ctx context.Context, name string, cl *Client) (interface{}, error) {
+		return nil, fn(ctx, name, cl)
+	})
+	var err *FleetError
+	for _, r := range res {
+		if r.Err == nil {
+			continue
+		}
+		if err == nil {
+			err = &FleetError{Errors: make(map[string]error)}
+		}
+		err.Errors[r.Name] = r.Err
+	}
+	if err == nil {
+		return nil
+	}
+	return err
+}
+
+// FleetError is the error returned by Fleet.Do, holding the errors of the
+// failed clients by name.
+type FleetError struct {
+	Errors map[string]error
+}
+
+// Error satisfies the error interface.
+func (err *FleetError) Error() string {
+	names := err.names()
+	s := make([]string, len(names))
+	for i, name := range names {
+		s[i] = name + ": " + err.Errors[name].Error()
+	}
+	return strings.Join(s, "; ")
+}
+
+// Is returns true when any of the failed clients' errors matches target, for
+// use with errors.Is.
+func (err *FleetError) Is(target error) bool {
+	for _, name := range err.names() {
+		if errors.Is(err.Errors[name], target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As finds the first of the failed clients' errors (sorted by name) matching
+// target, for use with errors.As.
+func (err *FleetError) As(target interface{}) bool {
+	for _, name := range err.names() {
+		if errors.As(err.Errors[name], target) {
+			return true
+		}
+	}
+	return false
+}
+
+// names returns the sorted names of the failed clients.
+func (err *FleetError) names() []string {
+	names := make([]string, 0, len(err.Errors))
+	for name := range err.Errors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
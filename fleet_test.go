@@ -0,0 +1,76 @@
+package hilink
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+)
+
+func TestFleetDo(t *testing.T) {
+	f := NewFleet(WithParallelism(2))
+	for _, name := range []string{"c", "a", "b"} {
+		f.Add(name, NewClient())
+	}
+	var running, max int32
+	err := f.Do(context.Background(), func(ctx context.Context, name string, cl *Client) error {
+		n := atomic.AddInt32(&running, 1)
+		defer atomic.AddInt32(&running, -1)
+		for {
+			m := atomic.LoadInt32(&max)
+			if n <= m || atomic.CompareAndSwapInt32(&max, m, n) {
+				break
+			}
+		}
+		switch name {
+		case "a":
+			return ErrNotSupported
+		case "c":
+			return &APIError{Code: 108006, Message: "invalid username or password"}
+		}
+		return nil
+	})
+	if max > 2 {
+		t.Errorf("expected at most 2 concurrent calls, got: %d", max)
+	}
+	var fleetErr *FleetError
+	if !errors.As(err, &fleetErr) {
+		t.Fatalf("expected *FleetError, got: %v", err)
+	}
+	if len(fleetErr.Errors) != 2 {
+		t.Errorf("expected 2 errors, got: %v", fleetErr.Errors)
+	}
+	if exp := "a: not supported; c: hilink error 108006: invalid username or password"; err.Error() != exp {
+		t.Errorf("expected %q, got: %q", exp, err.Error())
+	}
+	if !errors.Is(err, ErrNotSupported) {
+		t.Errorf("expected errors.Is ErrNotSupported")
+	}
+	if errors.Is(err, ErrInvalidXML) {
+		t.Errorf("expected errors.Is ErrInvalidXML to be false")
+	}
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.Code != 108006 {
+		t.Errorf("expected errors.As *APIError 108006, got: %v", apiErr)
+	}
+}
+
+func TestFleetCanceled(t *testing.T) {
+	f := NewFleet()
+	f.Add("a", NewClient())
+	f.Add("b", NewClient())
+	f.Remove("b")
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	for i := 0; i < 100; i++ {
+		res := f.Map(ctx, func(ctx context.Context, name string, cl *Client) (interface{}, error) {
+			return name, nil
+		})
+		if len(res) != 1 || res[0].Name != "a" {
+			t.Fatalf("unexpected results: %v", res)
+		}
+		if res[0].Value != nil || !errors.Is(res[0].Err, context.Canceled) {
+			t.Fatalf("expected context.Canceled, got: %v %v", res[0].Value, res[0].Err)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+//go:build go1.18
+// +build go1.18
+
+package hilink
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// FuzzDecodeXML checks that malformed device responses are only ever
+// returned as the decode errors, and never panic. Seeded from the responses
+// in testdata. Run with:
+//
+//	$ go test -fuzz FuzzDecodeXML
+func FuzzDecodeXML(f *testing.F) {
+	err := filepath.Walk("testdata", func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() || !strings.HasSuffix(path, ".xml") {
+			return err
+		}
+		buf, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		f.Add(buf)
+		return nil
+	})
+	if err != nil {
+		f.Fatal(err)
+	}
+	f.Add([]byte(`<error><code>100002</code><message></message></error>`))
+	f.Add([]byte(`<html><body>login</body></html>`))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		res, err := DecodeXML(data)
+		if err != nil {
+			var apiErr *APIError
+			switch {
+			case errors.As(err, &apiErr),
+				errors.Is(err, ErrInvalidXML),
+				errors.Is(err, ErrInvalidError),
+				errors.Is(err, ErrMissingRootElement):
+			default:
+				t.Fatalf("unexpected decode error: %v", err)
+			}
+			return
+		}
+		// exercise the value helpers used by the typed decoders
+		for k := range res {
+			xmlStr(res, k)
+			xmlInt(res, k)
+			xmlUint(res, k)
+			xmlFloat(res, k)
+			xmlEarfcn(res, k)
+			xmlList(res[k])
+		}
+		smsMessages(res)
+	})
+}
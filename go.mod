@@ -5,4 +5,5 @@ go 1.15
 require (
 	github.com/clbanning/mxj/v2 v2.5.5
 	github.com/kenshaw/httplog v0.4.0
+	gopkg.in/yaml.v3 v3.0.1
 )
@@ -0,0 +1,223 @@
+package hilink_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kenshaw/hilink"
+)
+
+// update regenerates the golden.json files. When a decoder is intentionally
+// changed, run with -update, and review the diff:
+//
+//	$ go test -run TestGolden -update
+var update = flag.Bool("update", false, "update the golden.json files")
+
+func TestMain(m *testing.M) {
+	// dates are decoded in the local time zone
+	time.Local = time.UTC
+	os.Exit(m.Run())
+}
+
+// goldenTests are the decode test cases run against each firmware
+// generation's corpus in testdata. Each directory in testdata contains the
+// raw XML responses of the device at the same path as the API endpoint (ie,
+// testdata/E5186s-22a/api/monitoring/status.xml), and a golden.json file
+// containing the expected decoded values for each case. Cases are skipped
+// when the corpus does not contain the response.
+//
+// New firmware generations are added by capturing the responses with curl
+// (removing identifiers), and running with -update.
+var goldenTests = []struct {
+	name   string
+	path   string
+	decode func(context.Context, *hilink.Client) (interface{}, error)
+}{
+	{"DeviceInfo", "api/device/information", func(ctx context.Context, cl *hilink.Client) (interface{}, error) {
+		return cl.DeviceInfo(ctx)
+	}},
+	{"Status", "api/monitoring/status", func(ctx context.Context, cl *hilink.Client) (interface{}, error) {
+		return cl.Status(ctx)
+	}},
+	{"Traffic", "api/monitoring/traffic-statistics", func(ctx context.Context, cl *hilink.Client) (interface{}, error) {
+		return cl.Traffic(ctx)
+	}},
+	{"MonthTraffic", "api/monitoring/month_statistics", func(ctx context.Context, cl *hilink.Client) (interface{}, error) {
+		return cl.MonthTraffic(ctx)
+	}},
+	{"NotificationInfo", "api/monitoring/check-notifications", func(ctx context.Context, cl *hilink.Client) (interface{}, error) {
+		return cl.NotificationInfo(ctx)
+	}},
+	{"NetworkInfo", "api/net/current-plmn", func(ctx context.Context, cl *hilink.Client) (interface{}, error) {
+		return cl.NetworkInfo(ctx)
+	}},
+	{"ServingCell", "api/device/signal", func(ctx context.Context, cl *hilink.Client) (interface{}, error) {
+		return cl.ServingCell(ctx)
+	}},
+	{"NeighborCells", "api/net/cell-info", func(ctx context.Context, cl *hilink.Client) (interface{}, error) {
+		return cl.NeighborCells(ctx)
+	}},
+	{"SmsCounts", "api/sms/sms-count", func(ctx context.Context, cl *hilink.Client) (interface{}, error) {
+		return cl.SmsCounts(ctx)
+	}},
+	{"SmsMessages", "api/sms/sms-list", func(ctx context.Context, cl *hilink.Client) (interface{}, error) {
+		return cl.SmsMessages(ctx, hilink.SmsBoxTypeInbox, 1, 20)
+	}},
+}
+
+func TestGolden(t *testing.T) {
+	entries, err := ioutil.ReadDir("testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range entries {
+		// firmware generations have the api responses
+		dir := filepath.Join("testdata", e.Name())
+		if _, err := os.Stat(filepath.Join(dir, "api")); !e.IsDir() || err != nil {
+			continue
+		}
+		t.Run(e.Name(), func(t *testing.T) {
+			testGolden(t, dir)
+		})
+	}
+}
+
+// testGolden runs the golden tests against a firmware generation's corpus.
+func testGolden(t *testing.T, dir string) {
+	goldenFile := filepath.Join(dir, "golden.json")
+	golden := make(map[string]json.RawMessage)
+	if !*update {
+		buf, err := ioutil.ReadFile(goldenFile)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := json.Unmarshal(buf, &golden); err != nil {
+			t.Fatalf("unable to read %s: %v", goldenFile, err)
+		}
+	}
+	cl := hilink.NewClient(
+		hilink.WithURL("http://golden/"),
+		hilink.WithTransport(corpusTransport(dir)),
+	)
+	for _, test := range goldenTests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := os.Stat(filepath.Join(dir, filepath.FromSlash(test.path)+".xml")); os.IsNotExist(err) {
+				t.Skipf("%s not in corpus", test.path)
+			}
+			v, err := test.decode(context.Background(), cl)
+			if err != nil {
+				t.Fatalf("expected no error, got: %v", err)
+			}
+			buf, err := json.MarshalIndent(v, "", "  ")
+			if err != nil {
+				t.Fatal(err)
+			}
+			switch exp, ok := golden[test.name]; {
+			case *update:
+				golden[test.name] = buf
+			case !ok:
+				t.Fatalf("missing from %s (run with -update)", goldenFile)
+			case !jsonEqual(exp, buf):
+				t.Errorf("expected: %s\n     got: %s", compact(exp), compact(buf))
+			}
+		})
+	}
+	if *update {
+		if err := writeGolden(goldenFile, golden); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// corpusTransport returns a transport serving the responses in dir, with a
+// not supported error for missing responses, and a fixed session for session
+// requests.
+func corpusTransport(dir string) http.RoundTripper {
+	return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		path := strings.TrimPrefix(req.URL.Path, "/")
+		buf, err := ioutil.ReadFile(filepath.Join(dir, filepath.FromSlash(path)+".xml"))
+		switch {
+		case os.IsNotExist(err) && path == "api/webserver/SesTokInfo":
+			buf, err = []byte(`<?xml version="1.0" encoding="UTF-8"?><response><SesInfo>SessionID=golden</SesInfo><TokInfo>golden</TokInfo></response>`), nil
+		case os.IsNotExist(err):
+			buf, err = []byte(`<?xml version="1.0" encoding="UTF-8"?><error><code>100002</code><message></message></error>`), nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		return &http.Response{
+			Status:        "200 OK",
+			StatusCode:    http.StatusOK,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        http.Header{"Content-Type": []string{"text/html"}},
+			Body:          ioutil.NopCloser(bytes.NewReader(buf)),
+			ContentLength: int64(len(buf)),
+			Request:       req,
+		}, nil
+	})
+}
+
+// roundTripperFunc wraps a func as a http.RoundTripper.
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+// RoundTrip satisfies the http.RoundTripper interface.
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// writeGolden writes the golden values to the file, with sorted keys.
+func writeGolden(name string, golden map[string]json.RawMessage) error {
+	var keys []string
+	for k := range golden {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var buf bytes.Buffer
+	buf.WriteString("{\n")
+	for i, k := range keys {
+		var z bytes.Buffer
+		if err := json.Indent(&z, golden[k], "  ", "  "); err != nil {
+			return err
+		}
+		fmt.Fprintf(&buf, "  %q: %s", k, z.String())
+		if i != len(keys)-1 {
+			buf.WriteString(",")
+		}
+		buf.WriteString("\n")
+	}
+	buf.WriteString("}\n")
+	return ioutil.WriteFile(name, buf.Bytes(), 0o644)
+}
+
+// jsonEqual returns true when a and b are equivalent JSON values.
+func jsonEqual(a, b []byte) bool {
+	var x, y interface{}
+	if json.Unmarshal(a, &x) != nil || json.Unmarshal(b, &y) != nil {
+		return false
+	}
+	return reflect.DeepEqual(x, y)
+}
+
+// compact compacts the JSON value.
+func compact(v []byte) string {
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, v); err != nil {
+		return string(v)
+	}
+	return buf.String()
+}
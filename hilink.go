@@ -1,11 +1,17 @@
 // Package hilink provides a Hilink WebUI client.
 package hilink
 
+//go:generate go run ./internal/endpointgen
+//go:generate go run ./cmd/hlcli/gen.go -dir . -o cmd/hlcli/doc.go
+
 import (
 	"bytes"
+	"fmt"
+	"math/big"
+	"net"
 	"strconv"
-
-	"github.com/clbanning/mxj/v2"
+	"strings"
+	"time"
 )
 
 // Error is the error type.
@@ -27,6 +33,24 @@ const (
 	ErrMissingRootElement Error = "missing root element"
 	// ErrMessageTooLong is the message too long error.
 	ErrMessageTooLong Error = "message too long"
+	// ErrUnexpectedContent is the unexpected content error.
+	ErrUnexpectedContent Error = "unexpected content"
+	// ErrMacFilterFull is the mac filter full error.
+	ErrMacFilterFull Error = "mac filter full"
+	// ErrInvalidAddress is the invalid address error.
+	ErrInvalidAddress Error = "invalid address"
+	// ErrDhcpRangeOutsideSubnet is the dhcp range outside subnet error.
+	ErrDhcpRangeOutsideSubnet Error = "dhcp range outside subnet"
+	// ErrInvalidURL is the invalid url error.
+	ErrInvalidURL Error = "invalid url"
+	// ErrInvalidAuth is the invalid auth error.
+	ErrInvalidAuth Error = "invalid auth"
+	// ErrInvalidTimeout is the invalid timeout error.
+	ErrInvalidTimeout Error = "invalid timeout"
+	// ErrNotSupported is the not supported error, returned for API paths not
+	// supported by the device model (see DeviceProfile). Not supported by
+	// firmware (100002) API errors also match ErrNotSupported with errors.Is.
+	ErrNotSupported Error = "not supported"
 )
 
 // Error satisfies the error interface.
@@ -34,6 +58,23 @@ func (err Error) Error() string {
 	return string(err)
 }
 
+// APIError is an error returned by the Hilink API.
+type APIError struct {
+	Code    int
+	Message string
+}
+
+// Error satisfies the error interface.
+func (err *APIError) Error() string {
+	return fmt.Sprintf("hilink error %d: %s", err.Code, err.Message)
+}
+
+// Is returns true when target is ErrNotSupported and the error is the not
+// supported by firmware (100002) error.
+func (err *APIError) Is(target error) bool {
+	return target == ErrNotSupported && err.Code == 100002
+}
+
 // SmsBoxType represents the different inbox types available on a hilink
 // device.
 type SmsBoxType uint
@@ -45,6 +86,80 @@ const (
 	SmsBoxTypeDraft
 )
 
+// SmsType represents the different SMS types.
+type SmsType int
+
+// SmsType values.
+const (
+	SmsTypeSingle       SmsType = 1
+	SmsTypeMulti        SmsType = 2
+	SmsTypeStatusReport SmsType = 7
+)
+
+// SmsSaveType represents the different locations a sent SMS is saved to.
+type SmsSaveType int
+
+// SmsSaveType values.
+const (
+	SmsSaveTypeDefault SmsSaveType = iota
+	SmsSaveTypeLocal
+	SmsSaveTypeSim
+)
+
+// SmsPriority represents the different SMS priorities.
+type SmsPriority int
+
+// SmsPriority values.
+const (
+	SmsPriorityNormal SmsPriority = iota
+	SmsPriorityInteractive
+	SmsPriorityUrgent
+	SmsPriorityEmergency
+)
+
+// SmsOptions are the options for sending a SMS.
+type SmsOptions struct {
+	// Sca is the service center address. When empty, the device's
+	// configured service center is used.
+	Sca string
+	// SaveType is the location the sent SMS is saved to. When default, the
+	// device's configured save location is used.
+	SaveType SmsSaveType
+	// Priority is the requested priority.
+	Priority SmsPriority
+}
+
+// SmsMessage is a SMS message.
+type SmsMessage struct {
+	Index    int
+	Status   int
+	Phone    string
+	Content  string
+	Date     time.Time
+	Sca      string
+	SaveType int
+	Priority int
+	Type     SmsType
+}
+
+// Unread returns true when the message has not been read.
+func (m SmsMessage) Unread() bool {
+	return m.Status == 0
+}
+
+// DeliveryReport is a SMS delivery (status) report.
+type DeliveryReport struct {
+	// Index is the index of the status report message.
+	Index     int
+	Phone     string
+	Date      time.Time
+	Content   string
+	Delivered bool
+	// MessageIndex is the index of the original outbox message the report
+	// was matched to, or 0 when no matching message was found.
+	MessageIndex int
+}
+
 // PinType are the PIN types for a PIN command.
 type PinType int
 
@@ -67,27 +182,662 @@ const (
 	UssdStateWaiting
 )
 
+// NetworkMode represents the different network modes.
+type NetworkMode string
+
+// NetworkMode values.
+const (
+	NetworkModeAuto  NetworkMode = "00"
+	NetworkMode2G    NetworkMode = "01"
+	NetworkMode3G    NetworkMode = "02"
+	NetworkMode4G    NetworkMode = "03"
+	NetworkMode4G3G  NetworkMode = "0302"
+	NetworkMode5G    NetworkMode = "08"
+	NetworkMode5G4G  NetworkMode = "0803"
+	NetworkModeAllNR NetworkMode = "080302"
+)
+
+// NRMode represents the different NR (5G) SA/NSA mode preferences.
+type NRMode int
+
+// NRMode values.
+const (
+	NRModeAuto NRMode = iota
+	NRModeSA
+	NRModeNSA
+)
+
+// Rat represents the different radio access technologies.
+type Rat int
+
+// Rat values.
+const (
+	RatGSM  Rat = 0
+	RatUMTS Rat = 2
+	RatLTE  Rat = 7
+)
+
+// ConnectMode represents the different dialup connection modes.
+type ConnectMode int
+
+// ConnectMode values.
+const (
+	ConnectModeAuto ConnectMode = iota
+	ConnectModeManual
+)
+
+// ConnectionSettings are the dialup connection settings.
+type ConnectionSettings struct {
+	ConnectMode       ConnectMode
+	AutoReconnect     bool
+	RoamAutoConnect   bool
+	RoamAutoReconnect bool
+	ReconnectInterval time.Duration
+	MaxIdleTime       time.Duration
+	MTU               int
+}
+
+// AuthMode represents the different APN profile authentication modes.
+type AuthMode int
+
+// AuthMode values.
+const (
+	AuthModeAuto AuthMode = iota
+	AuthModePAP
+	AuthModeCHAP
+)
+
+// IPType represents the different APN profile IP types.
+type IPType int
+
+// IPType values.
+const (
+	IPTypeIPv4 IPType = iota
+	IPTypeIPv6
+	IPTypeIPv4v6
+)
+
+// IPv6ConnectionType represents the different WAN IPv6 address assignment
+// types.
+type IPv6ConnectionType int
+
+// IPv6ConnectionType values.
+const (
+	IPv6ConnectionTypeAuto IPv6ConnectionType = iota
+	IPv6ConnectionTypeSLAAC
+	IPv6ConnectionTypeDHCPv6
+)
+
+// IPv6Settings holds the IPv6 configuration.
+type IPv6Settings struct {
+	Enabled        bool
+	ConnectionType IPv6ConnectionType
+	// PrefixDelegation toggles requesting a delegated prefix (DHCPv6-PD) for
+	// the LAN.
+	PrefixDelegation bool
+	// DnsManual toggles handing out PrimaryDns and SecondaryDns instead of the
+	// carrier provided DNS servers.
+	DnsManual    bool
+	PrimaryDns   string
+	SecondaryDns string
+}
+
+// Profile is a dialup (APN) profile.
+type Profile struct {
+	Index    int
+	Name     string
+	ApnName  string
+	Username string
+	Password string
+	AuthMode AuthMode
+	IPType   IPType
+	ReadOnly bool
+	Default  bool
+}
+
+// ConnectionStatus represents the different connection statuses.
+type ConnectionStatus int
+
+// ConnectionStatus values.
+const (
+	ConnectionStatusConnecting    ConnectionStatus = 900
+	ConnectionStatusConnected     ConnectionStatus = 901
+	ConnectionStatusDisconnected  ConnectionStatus = 902
+	ConnectionStatusDisconnecting ConnectionStatus = 903
+)
+
+// Status is the general device status.
+type Status struct {
+	ConnectionStatus ConnectionStatus
+	// NetworkType is the current network type (ie, 19 for LTE), as reported
+	// by CurrentNetworkTypeEx, or CurrentNetworkType when not available.
+	NetworkType  int
+	SignalIcon   int
+	Roaming      bool
+	SimStatus    int
+	WanIPAddress string
+	PrimaryDns   string
+	SecondaryDns string
+	WifiEnabled  bool
+	WifiUsers    int
+}
+
+// Connected returns true when the status is connected.
+func (s Status) Connected() bool {
+	return s.ConnectionStatus == ConnectionStatusConnected
+}
+
+// Traffic holds the traffic statistics.
+type Traffic struct {
+	// ConnectTime, Upload and Download are for the current connection.
+	ConnectTime time.Duration
+	Upload      uint64
+	Download    uint64
+	// UploadRate and DownloadRate are in bytes per second.
+	UploadRate       uint64
+	DownloadRate     uint64
+	TotalConnectTime time.Duration
+	TotalUpload      uint64
+	TotalDownload    uint64
+}
+
+// MonthTraffic holds the current month traffic statistics.
+type MonthTraffic struct {
+	Upload    uint64
+	Download  uint64
+	Duration  time.Duration
+	LastClear string
+}
+
+// Total returns the total month traffic.
+func (m MonthTraffic) Total() uint64 {
+	return m.Upload + m.Download
+}
+
+// DataPlan holds the monthly data plan settings used by the device's usage
+// meter.
+type DataPlan struct {
+	// Enabled toggles the monthly usage meter.
+	Enabled bool
+	// StartDay is the billing cycle start day of month (1-31).
+	StartDay int
+	// DataLimit is the monthly data limit in bytes, with megabyte precision.
+	DataLimit uint64
+	// Threshold is the percentage of DataLimit at which the device warns
+	// (0-100).
+	Threshold int
+}
+
+// Notifications holds the device notifications.
+type Notifications struct {
+	UnreadMessage      int
+	SmsStorageFull     bool
+	OnlineUpdateStatus int
+}
+
+// SmsCounts holds the SMS message counts.
+type SmsCounts struct {
+	LocalUnread int
+	LocalInbox  int
+	LocalOutbox int
+	LocalDraft  int
+	LocalMax    int
+	SimUnread   int
+	SimInbox    int
+	SimOutbox   int
+	SimMax      int
+	NewMsg      int
+}
+
+// NetworkOperator is the current network operator information.
+type NetworkOperator struct {
+	State     int
+	FullName  string
+	ShortName string
+	Spn       string
+	Numeric   string
+	Plmn      Plmn
+	Rat       Rat
+}
+
+// Carrier is the radio information of a serving or secondary (carrier
+// aggregation) component carrier.
+type Carrier struct {
+	Band        int
+	EARFCN      int
+	PCI         int
+	DLBandwidth float64
+	ULBandwidth float64
+	RSRP        float64
+	RSRQ        float64
+	SINR        float64
+}
+
+// ServingCell is the extended serving cell information.
+type ServingCell struct {
+	Carrier
+	Mode     string
+	CellID   uint64
+	ENodeBID uint64
+	Sector   int
+	TAC      string
+	PLMN     string
+	RSSI     float64
+	// SecondaryCells are the secondary component carriers, when carrier
+	// aggregation is active and reported by the device.
+	SecondaryCells []Carrier
+}
+
+// NeighborCell is a neighbor cell entry.
+type NeighborCell struct {
+	EARFCN int
+	PCI    int
+	RSRP   float64
+	RSRQ   float64
+}
+
+// Band is a bitmask of frequency bands, as used by the device for band
+// locking (ie, the LTEBand value of a network mode), where band N is bit N-1.
+type Band uint64
+
+// Band values.
+const (
+	Band1  Band = 1 << 0
+	Band2  Band = 1 << 1
+	Band3  Band = 1 << 2
+	Band4  Band = 1 << 3
+	Band5  Band = 1 << 4
+	Band7  Band = 1 << 6
+	Band8  Band = 1 << 7
+	Band12 Band = 1 << 11
+	Band13 Band = 1 << 12
+	Band17 Band = 1 << 16
+	Band19 Band = 1 << 18
+	Band20 Band = 1 << 19
+	Band25 Band = 1 << 24
+	Band26 Band = 1 << 25
+	Band28 Band = 1 << 27
+	Band32 Band = 1 << 31
+	Band38 Band = 1 << 37
+	Band39 Band = 1 << 38
+	Band40 Band = 1 << 39
+	Band41 Band = 1 << 40
+	Band42 Band = 1 << 41
+	Band43 Band = 1 << 42
+	// BandAll is the mask of all bands.
+	BandAll Band = 0x7fffffffffffffff
+)
+
+// BandN returns the band mask for band number n.
+func BandN(n int) Band {
+	if n < 1 || n > 63 {
+		return 0
+	}
+	return 1 << uint(n-1)
+}
+
+// Bands combines bands into a single mask.
+func Bands(bands ...Band) Band {
+	var b Band
+	for _, z := range bands {
+		b |= z
+	}
+	return b
+}
+
+// ParseBand parses a hex band mask as returned by the device.
+func ParseBand(s string) (Band, error) {
+	i, err := strconv.ParseUint(s, 16, 64)
+	if err != nil {
+		return 0, ErrInvalidValue
+	}
+	return Band(i), nil
+}
+
+// Has returns true when all of the bands in o are in the mask.
+func (b Band) Has(o Band) bool {
+	return b&o == o
+}
+
+// Numbers returns the band numbers in the mask.
+func (b Band) Numbers() []int {
+	var n []int
+	for i := 1; i < 64; i++ {
+		if b.Has(BandN(i)) {
+			n = append(n, i)
+		}
+	}
+	return n
+}
+
+// String satisfies the fmt.Stringer interface, returning the hex mask as
+// expected by the device.
+func (b Band) String() string {
+	return fmt.Sprintf("%X", uint64(b))
+}
+
+// Network is a network (PLMN) found by a network scan.
+type Network struct {
+	Index     int
+	State     int
+	FullName  string
+	ShortName string
+	Numeric   string
+	Rat       Rat
+}
+
+// NRBand is a NR (5G) band number.
+type NRBand int
+
+// NRBand values.
+const (
+	NRBand1  NRBand = 1
+	NRBand3  NRBand = 3
+	NRBand5  NRBand = 5
+	NRBand7  NRBand = 7
+	NRBand8  NRBand = 8
+	NRBand20 NRBand = 20
+	NRBand28 NRBand = 28
+	NRBand38 NRBand = 38
+	NRBand41 NRBand = 41
+	NRBand77 NRBand = 77
+	NRBand78 NRBand = 78
+	NRBand79 NRBand = 79
+)
+
+// NRBands combines NR bands into the hex mask expected by the device, where
+// band N is bit N-1. Unlike Band, the NR band mask is not limited to 64 bits.
+// When no bands are specified, the mask of all bands is returned.
+func NRBands(bands ...NRBand) string {
+	if len(bands) == 0 {
+		return BandAll.String()
+	}
+	b := new(big.Int)
+	for _, n := range bands {
+		if n > 0 {
+			b.SetBit(b, int(n)-1, 1)
+		}
+	}
+	return strings.ToUpper(b.Text(16))
+}
+
+// UssdCodeType represents the different USSD code types.
+type UssdCodeType string
+
+// UssdCodeType values.
+const (
+	// UssdCodeTypeText sends the code as text (the WebUI default).
+	UssdCodeTypeText UssdCodeType = "CodeType"
+	// UssdCodeTypeRaw sends the code as hex encoded, packed GSM-7 (ie, data
+	// coding scheme 15).
+	UssdCodeTypeRaw UssdCodeType = "15"
+)
+
+// FirmwareVersion is the result of a firmware update check.
+type FirmwareVersion struct {
+	Available bool
+	Version   string
+	Size      uint64
+	Notes     string
+}
+
+// FirmwareStatus is the status of the firmware update process.
+type FirmwareStatus struct {
+	// Status is the raw firmware update status code.
+	Status int
+	// Progress is the download or install progress percentage.
+	Progress int
+	// Component is the index of the component being updated.
+	Component int
+	// Components is the number of components being updated.
+	Components int
+}
+
+// Protocol represents the different IP protocols for firewall and port
+// forwarding rules.
+type Protocol int
+
+// Protocol values.
+const (
+	ProtocolAll Protocol = 0
+	ProtocolTCP Protocol = 6
+	ProtocolUDP Protocol = 17
+)
+
+// VirtualServer is a port forwarding (virtual server) rule.
+type VirtualServer struct {
+	Name     string
+	Enabled  bool
+	Protocol Protocol
+	// RemoteIP restricts the rule to a remote IP address, when not empty.
+	RemoteIP   string
+	WanPort    int
+	WanEndPort int
+	LanIP      string
+	LanPort    int
+	LanEndPort int
+}
+
+// LanSettings holds the router LAN (DHCP) settings.
+type LanSettings struct {
+	// IPAddress is the router's LAN IP address.
+	IPAddress string
+	Netmask   string
+	// DhcpEnabled toggles the DHCP server.
+	DhcpEnabled bool
+	DhcpStart   string
+	DhcpEnd     string
+	LeaseTime   time.Duration
+	// DnsEnabled toggles handing out PrimaryDns and SecondaryDns to DHCP
+	// clients instead of the router's address.
+	DnsEnabled   bool
+	PrimaryDns   string
+	SecondaryDns string
+}
+
+// Validate validates the LAN settings, checking that the DHCP range is within
+// the router's subnet and does not include the router's address.
+func (s LanSettings) Validate() error {
+	ip := net.ParseIP(s.IPAddress).To4()
+	mask := net.ParseIP(s.Netmask).To4()
+	if ip == nil || mask == nil {
+		return ErrInvalidAddress
+	}
+	subnet := &net.IPNet{IP: ip.Mask(net.IPMask(mask)), Mask: net.IPMask(mask)}
+	if ones, bits := subnet.Mask.Size(); bits == 0 || ones == 0 {
+		return ErrInvalidAddress
+	}
+	start, end := net.ParseIP(s.DhcpStart).To4(), net.ParseIP(s.DhcpEnd).To4()
+	if start == nil || end == nil {
+		return ErrInvalidAddress
+	}
+	if !subnet.Contains(start) || !subnet.Contains(end) {
+		return ErrDhcpRangeOutsideSubnet
+	}
+	if bytes.Compare(start, end) > 0 {
+		return ErrInvalidValue
+	}
+	if bytes.Compare(start, ip) <= 0 && bytes.Compare(ip, end) <= 0 {
+		return ErrInvalidValue
+	}
+	if s.LeaseTime < time.Second {
+		return ErrInvalidValue
+	}
+	for _, dns := range []string{s.PrimaryDns, s.SecondaryDns} {
+		if dns != "" && net.ParseIP(dns) == nil {
+			return ErrInvalidAddress
+		}
+	}
+	return nil
+}
+
+// StaticLease is a static DHCP binding (MAC address to IP reservation).
+type StaticLease struct {
+	MacAddress string
+	IPAddress  string
+}
+
+// BandwidthLimit is a per-device bandwidth limit (QoS), limiting the upload
+// and download rates of the host with the MAC address.
+type BandwidthLimit struct {
+	MacAddress string
+	// Upload and Download are the limits, in kbit/s, with 0 being
+	// unlimited.
+	Upload   uint
+	Download uint
+}
+
+// Route is a static route.
+type Route struct {
+	Destination string
+	Mask        string
+	Gateway     string
+	// Interface is the outgoing interface (ie, "LAN" or "WAN").
+	Interface string
+}
+
+// IPFilter is a LAN IP filter rule.
+type IPFilter struct {
+	Enabled    bool
+	Protocol   Protocol
+	LanIP      string
+	LanPort    int
+	LanEndPort int
+	WanIP      string
+	WanPort    int
+	WanEndPort int
+}
+
+// MacFilter is a MAC filter rule.
+type MacFilter struct {
+	MacAddress string
+	Enabled    bool
+}
+
+// URLFilter is a URL filter rule.
+type URLFilter struct {
+	URL     string
+	Enabled bool
+}
+
+// WifiRadio represents the different Wi-Fi radios available on a hilink
+// device.
+type WifiRadio int
+
+// WifiRadio values.
+const (
+	WifiRadio24GHz WifiRadio = iota
+	WifiRadio5GHz
+)
+
+// ID returns the configuration ID of the radio as used by the WebUI.
+func (r WifiRadio) ID() string {
+	return fmt.Sprintf("InternetGatewayDevice.X_Config.Wifi.Radio.%d.", int(r)+1)
+}
+
+// MacFilterStatus represents the different Wi-Fi MAC filter modes.
+type MacFilterStatus int
+
+// MacFilterStatus values.
+const (
+	MacFilterStatusDisabled MacFilterStatus = iota
+	MacFilterStatusAllow
+	MacFilterStatusDeny
+)
+
+// WifiSchedule is a Wi-Fi on/off (time switch) schedule. When enabled, the
+// Wi-Fi is turned off from Start until Stop on each of the Weekdays.
+type WifiSchedule struct {
+	Enabled  bool
+	Weekdays []time.Weekday
+	// Start and Stop are offsets from midnight, with minute precision.
+	Start time.Duration
+	Stop  time.Duration
+}
+
+// RemoteAccess holds the WAN-side (remote) management access settings.
+type RemoteAccess struct {
+	// Enabled toggles access to the web UI from the WAN.
+	Enabled      bool
+	HTTPPort     int
+	HTTPSEnabled bool
+	HTTPSPort    int
+	// AllowedIPs restricts remote access to the listed addresses or CIDR
+	// ranges, when not empty.
+	AllowedIPs []string
+}
+
+// TimeRule is a parental control rule, restricting internet access for the
+// listed MAC addresses outside of a schedule.
+type TimeRule struct {
+	Name         string
+	Enabled      bool
+	MacAddresses []string
+	Weekdays     []time.Weekday
+	// Start and Stop are offsets from midnight, with minute precision, during
+	// which internet access is allowed.
+	Start time.Duration
+	Stop  time.Duration
+}
+
+// WifiHost is a host connected to the Wi-Fi, including the per-station
+// statistics reported by the device. Statistics not reported by the firmware
+// are left as zero values.
+type WifiHost struct {
+	MacAddress     string
+	IPAddress      string
+	HostName       string
+	AssociatedSsid string
+	AssociatedTime time.Duration
+	Frequency      string
+	TxBytes        uint64
+	RxBytes        uint64
+	TxRate         uint64
+	RxRate         uint64
+	Signal         int
+}
+
+// HostInterface is the interface a host is connected to.
+type HostInterface string
+
+// HostInterface values.
+const (
+	HostInterfaceEthernet HostInterface = "Ethernet"
+	HostInterfaceWifi     HostInterface = "Wireless"
+)
+
+// Host is a host connected to the device, either by Ethernet or Wi-Fi.
+type Host struct {
+	MacAddress string
+	IPAddress  string
+	HostName   string
+	Interface  HostInterface
+	// AssociatedSsid and Frequency are only set for Wi-Fi hosts.
+	AssociatedSsid string
+	Frequency      string
+	AssociatedTime time.Duration
+}
+
+// PhonebookEntry is a phonebook entry.
+type PhonebookEntry struct {
+	Index     uint
+	GroupID   uint
+	SIM       bool
+	Name      string
+	Phone     string
+	HomePhone string
+	WorkPhone string
+	Email     string
+}
+
 // XMLData is a map of XML data to encode/decode.
-type XMLData mxj.Map
+type XMLData map[string]interface{}
 
-// SimpleRequestXML creates an XML string from value pairs.
-//
-// Unfortunately the XML parser (or whatever underyling code) included with the
-// WebUI on Hilink devices expects parameters in a specific order. This makes
-// packages like mxj or other map based solutions not feasible for use, as Go
-// has random key ordering for maps.
+// SimpleRequestXML creates an XML request from value pairs.
 //
-// On another note, XML sucks.
+// Deprecated: use NewRequest.
 func SimpleRequestXML(vals ...string) []byte {
-	var buf bytes.Buffer
-	// write header
-	buf.WriteString(`<?xml version="1.0" encoding="UTF-8"?>`)
-	buf.WriteString("\n<request>\n")
-	// add pairs
-	buf.Write(xmlPairs("  ", vals...))
-	// end string
-	buf.WriteString("</request>\n")
-	return buf.Bytes()
+	return NewRequest(vals...).Bytes()
 }
 
 // ErrorCodeMap contains the known message strings for Hilink devices.
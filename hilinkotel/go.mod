@@ -0,0 +1,16 @@
+module github.com/kenshaw/hilink/hilinkotel
+
+go 1.20
+
+require (
+	github.com/kenshaw/hilink v0.0.0
+	go.opentelemetry.io/otel v1.24.0
+	go.opentelemetry.io/otel/trace v1.24.0
+)
+
+require (
+	github.com/clbanning/mxj/v2 v2.5.5 // indirect
+	github.com/kenshaw/httplog v0.4.0 // indirect
+)
+
+replace github.com/kenshaw/hilink => ../
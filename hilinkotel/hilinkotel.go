@@ -0,0 +1,86 @@
+// Package hilinkotel adapts a OpenTelemetry trace.TracerProvider for use
+// with hilink.WithTracerProvider.
+//
+// The adapter is a separate module, so that the hilink package does not
+// depend on OpenTelemetry:
+//
+//	cl := hilink.NewClient(
+//		hilinkotel.WithTracerProvider(otel.GetTracerProvider()),
+//	)
+package hilinkotel
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kenshaw/hilink"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// WithTracerProvider is a hilink client option that traces API calls using
+// the OpenTelemetry tracer provider.
+func WithTracerProvider(tp trace.TracerProvider) hilink.ClientOption {
+	return hilink.WithTracerProvider(NewTracerProvider(tp))
+}
+
+// NewTracerProvider wraps the OpenTelemetry tracer provider as a
+// hilink.TracerProvider.
+func NewTracerProvider(tp trace.TracerProvider) hilink.TracerProvider {
+	return tracerProvider{tp}
+}
+
+// tracerProvider wraps a OpenTelemetry tracer provider.
+type tracerProvider struct {
+	tp trace.TracerProvider
+}
+
+// Tracer satisfies the hilink.TracerProvider interface.
+func (p tracerProvider) Tracer(name string) hilink.Tracer {
+	return tracer{p.tp.Tracer(name)}
+}
+
+// tracer wraps a OpenTelemetry tracer.
+type tracer struct {
+	t trace.Tracer
+}
+
+// Start satisfies the hilink.Tracer interface, starting client spans.
+func (t tracer) Start(ctx context.Context, spanName string) (context.Context, hilink.Span) {
+	ctx, s := t.t.Start(ctx, spanName, trace.WithSpanKind(trace.SpanKindClient))
+	return ctx, span{s}
+}
+
+// span wraps a OpenTelemetry span.
+type span struct {
+	s trace.Span
+}
+
+// SetAttribute satisfies the hilink.Span interface.
+func (s span) SetAttribute(key string, value interface{}) {
+	switch v := value.(type) {
+	case string:
+		s.s.SetAttributes(attribute.String(key, v))
+	case int:
+		s.s.SetAttributes(attribute.Int(key, v))
+	case int64:
+		s.s.SetAttributes(attribute.Int64(key, v))
+	case bool:
+		s.s.SetAttributes(attribute.Bool(key, v))
+	default:
+		s.s.SetAttributes(attribute.String(key, fmt.Sprint(v)))
+	}
+}
+
+// RecordError satisfies the hilink.Span interface, recording the error and
+// setting the span status.
+func (s span) RecordError(err error) {
+	s.s.RecordError(err)
+	s.s.SetStatus(codes.Error, err.Error())
+}
+
+// End satisfies the hilink.Span interface.
+func (s span) End() {
+	s.s.End()
+}
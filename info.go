@@ -0,0 +1,108 @@
+package hilink
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// DeviceInformation is the general device information.
+type DeviceInformation struct {
+	DeviceName      string
+	SerialNumber    string
+	Imei            string
+	Imsi            string
+	Iccid           string
+	Msisdn          string
+	HardwareVersion string
+	SoftwareVersion string
+	WebUIVersion    string
+	MacAddress1     string
+	MacAddress2     string
+	ProductFamily   string
+	Classify        string
+	SupportMode     string
+	WorkMode        string
+}
+
+// Info is a combined snapshot of the device information, status, signal,
+// traffic and network operator, as retrieved by InfoAll.
+type Info struct {
+	Time    time.Time
+	Device  *DeviceInformation
+	Status  *Status
+	Signal  *ServingCell
+	Traffic *Traffic
+	Network *NetworkOperator
+}
+
+// InfoAll concurrently retrieves the device information, status, signal,
+// traffic statistics and network operator, returning a combined snapshot. An
+// error is returned if any of the requests fail.
+func (cl *Client) InfoAll(ctx context.Context) (*Info, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	info := &Info{
+		Time: time.Now(),
+	}
+	var wg sync.WaitGroup
+	var once sync.Once
+	var err error
+	do := func(f func() error) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if e := f(); e != nil {
+				once.Do(func() {
+					err = e
+					cancel()
+				})
+			}
+		}()
+	}
+	do(func() error {
+		res, err := cl.DeviceInfo(ctx)
+		if err != nil {
+			return err
+		}
+		info.Device = &DeviceInformation{
+			DeviceName:      xmlStr(res, "DeviceName"),
+			SerialNumber:    xmlStr(res, "SerialNumber"),
+			Imei:            xmlStr(res, "Imei"),
+			Imsi:            xmlStr(res, "Imsi"),
+			Iccid:           xmlStr(res, "Iccid"),
+			Msisdn:          xmlStr(res, "Msisdn"),
+			HardwareVersion: xmlStr(res, "HardwareVersion"),
+			SoftwareVersion: xmlStr(res, "SoftwareVersion"),
+			WebUIVersion:    xmlStr(res, "WebUIVersion"),
+			MacAddress1:     xmlStr(res, "MacAddress1"),
+			MacAddress2:     xmlStr(res, "MacAddress2"),
+			ProductFamily:   xmlStr(res, "ProductFamily"),
+			Classify:        xmlStr(res, "Classify"),
+			SupportMode:     xmlStr(res, "supportmode"),
+			WorkMode:        xmlStr(res, "workmode"),
+		}
+		return nil
+	})
+	do(func() (err error) {
+		info.Status, err = cl.Status(ctx)
+		return err
+	})
+	do(func() (err error) {
+		info.Signal, err = cl.ServingCell(ctx)
+		return err
+	})
+	do(func() (err error) {
+		info.Traffic, err = cl.Traffic(ctx)
+		return err
+	})
+	do(func() (err error) {
+		info.Network, err = cl.NetworkInfo(ctx)
+		return err
+	})
+	wg.Wait()
+	if err != nil {
+		return nil, err
+	}
+	return info, nil
+}
@@ -0,0 +1,315 @@
+// Command endpointgen generates typed client methods, request structs, and
+// service wrappers from a declarative endpoint spec.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"io/ioutil"
+	"log"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+func main() {
+	spec := flag.String("spec", "internal/endpointgen/endpoints.yaml", "endpoint spec")
+	out := flag.String("o", "endpoints.go", "out file")
+	flag.Parse()
+	if err := run(*spec, *out); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// Spec is an endpoint spec.
+type Spec struct {
+	Types     []Type     `yaml:"types"`
+	Endpoints []Endpoint `yaml:"endpoints"`
+}
+
+// Type is a response type.
+type Type struct {
+	Name   string  `yaml:"name"`
+	Doc    string  `yaml:"doc"`
+	Fields []Field `yaml:"fields"`
+}
+
+// Field is a response type field.
+type Field struct {
+	Name string `yaml:"name"`
+	El   string `yaml:"el"`
+	Type string `yaml:"type"`
+}
+
+// Endpoint is an endpoint client method.
+type Endpoint struct {
+	Method   string    `yaml:"method"`
+	Service  string    `yaml:"service"`
+	Path     string    `yaml:"path"`
+	Doc      string    `yaml:"doc"`
+	Response *Response `yaml:"response"`
+	Request  *Request  `yaml:"request"`
+}
+
+// Response is an endpoint response, either a type or a single element.
+type Response struct {
+	Type   string `yaml:"type"`
+	El     string `yaml:"el"`
+	ElType string `yaml:"elType"`
+}
+
+// UnmarshalYAML satisfies the yaml.Unmarshaler interface, allowing the
+// element type to be specified as type when el is set.
+func (r *Response) UnmarshalYAML(n *yaml.Node) error {
+	var v struct {
+		Type string `yaml:"type"`
+		El   string `yaml:"el"`
+	}
+	if err := n.Decode(&v); err != nil {
+		return err
+	}
+	if v.El != "" {
+		r.El, r.ElType = v.El, v.Type
+	} else {
+		r.Type = v.Type
+	}
+	return nil
+}
+
+// Request is an endpoint request.
+type Request struct {
+	Params []Param        `yaml:"params"`
+	Fields []RequestField `yaml:"fields"`
+}
+
+// Param is a method param.
+type Param struct {
+	Name string `yaml:"name"`
+	Type string `yaml:"type"`
+}
+
+// RequestField is a request element, set from a param or a constant value.
+type RequestField struct {
+	El    string  `yaml:"el"`
+	Param string  `yaml:"param"`
+	Value *string `yaml:"value"`
+}
+
+// services are the service types, and the prefixes removed from the service
+// method names.
+var services = map[string][]string{
+	"SMS":      {"SMSService", "Sms"},
+	"Net":      {"NetService", "Network"},
+	"Wlan":     {"WlanService", "Wifi", "Wlan"},
+	"Device":   {"DeviceService", "Device"},
+	"Security": {"SecurityService", "Firewall"},
+}
+
+func run(specFile, out string) error {
+	buf, err := ioutil.ReadFile(specFile)
+	if err != nil {
+		return err
+	}
+	var spec Spec
+	if err := yaml.Unmarshal(buf, &spec); err != nil {
+		return err
+	}
+	src, err := generate(spec)
+	if err != nil {
+		return err
+	}
+	formatted, err := format.Source(src)
+	if err != nil {
+		return fmt.Errorf("unable to format generated source: %v", err)
+	}
+	return ioutil.WriteFile(out, formatted, 0o644)
+}
+
+// generate generates the client source for the spec.
+func generate(spec Spec) ([]byte, error) {
+	types := make(map[string]Type)
+	buf := new(bytes.Buffer)
+	buf.WriteString(hdr)
+	for _, typ := range spec.Types {
+		if typ.Name == "" || typ.Doc == "" {
+			return nil, fmt.Errorf("type missing name or doc")
+		}
+		types[typ.Name] = typ
+		fmt.Fprintf(buf, "// %s %s\ntype %s struct {\n", typ.Name, typ.Doc, typ.Name)
+		for _, f := range typ.Fields {
+			if err := checkType(f.Type); err != nil {
+				return nil, fmt.Errorf("type %s field %s: %w", typ.Name, f.Name, err)
+			}
+			fmt.Fprintf(buf, "%s %s\n", f.Name, f.Type)
+		}
+		buf.WriteString("}\n\n")
+	}
+	for _, e := range spec.Endpoints {
+		var err error
+		switch {
+		case e.Method == "" || e.Path == "" || e.Doc == "":
+			err = fmt.Errorf("endpoint missing method, path or doc")
+		case e.Response != nil && e.Request == nil:
+			err = genGetter(buf, e, types)
+		case e.Request != nil && e.Response == nil:
+			err = genSetter(buf, e)
+		default:
+			err = fmt.Errorf("endpoint %s must have either a response or request", e.Method)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if err := genService(buf, e); err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// genGetter generates a getter method.
+func genGetter(buf *bytes.Buffer, e Endpoint, types map[string]Type) error {
+	fmt.Fprintf(buf, "// %s %s\n", e.Method, e.Doc)
+	if e.Response.El != "" {
+		if err := checkType(e.Response.ElType); err != nil {
+			return fmt.Errorf("endpoint %s: %w", e.Method, err)
+		}
+		fmt.Fprintf(buf, "func (cl *Client) %s(ctx context.Context) (%s, error) {\n", e.Method, e.Response.ElType)
+		fmt.Fprintf(buf, "res, err := cl.Do(ctx, %q, nil)\n", e.Path)
+		fmt.Fprintf(buf, "if err != nil {\nreturn %s, err\n}\n", zero(e.Response.ElType))
+		fmt.Fprintf(buf, "return %s, nil\n}\n\n", decode(e.Response.ElType, e.Response.El))
+		return nil
+	}
+	typ, ok := types[e.Response.Type]
+	if !ok {
+		return fmt.Errorf("endpoint %s has unknown response type %q", e.Method, e.Response.Type)
+	}
+	fmt.Fprintf(buf, "func (cl *Client) %s(ctx context.Context) (*%s, error) {\n", e.Method, typ.Name)
+	fmt.Fprintf(buf, "res, err := cl.Do(ctx, %q, nil)\n", e.Path)
+	buf.WriteString("if err != nil {\nreturn nil, err\n}\n")
+	fmt.Fprintf(buf, "return &%s{\n", typ.Name)
+	for _, f := range typ.Fields {
+		fmt.Fprintf(buf, "%s: %s,\n", f.Name, decode(f.Type, f.El))
+	}
+	buf.WriteString("}, nil\n}\n\n")
+	return nil
+}
+
+// genSetter generates a setter method and its request struct.
+func genSetter(buf *bytes.Buffer, e Endpoint) error {
+	params := make(map[string]string)
+	var decl []string
+	for _, p := range e.Request.Params {
+		if err := checkType(p.Type); err != nil {
+			return fmt.Errorf("endpoint %s param %s: %w", e.Method, p.Name, err)
+		}
+		params[p.Name] = p.Type
+		decl = append(decl, p.Name+" "+p.Type)
+	}
+	name := strings.ToLower(e.Method[:1]) + e.Method[1:] + "Request"
+	fmt.Fprintf(buf, "// %s is the %s request (order matters below!).\ntype %s struct {\n", name, e.Method, name)
+	var vals []string
+	for _, f := range e.Request.Fields {
+		typ := "string"
+		switch {
+		case f.Value != nil && f.Param == "":
+			vals = append(vals, fmt.Sprintf("%s: %q", exported(f.El), *f.Value))
+		case f.Value == nil && params[f.Param] != "":
+			typ = params[f.Param]
+			vals = append(vals, fmt.Sprintf("%s: %s", exported(f.El), f.Param))
+		default:
+			return fmt.Errorf("endpoint %s field %s must have either a known param or a value", e.Method, f.El)
+		}
+		fmt.Fprintf(buf, "%s %s `hilink:%q`\n", exported(f.El), typ, f.El)
+	}
+	buf.WriteString("}\n\n")
+	fmt.Fprintf(buf, "// %s %s\n", e.Method, e.Doc)
+	fmt.Fprintf(buf, "func (cl *Client) %s(%s) (bool, error) {\n", e.Method, strings.Join(append([]string{"ctx context.Context"}, decl...), ", "))
+	fmt.Fprintf(buf, "return cl.doReqCheckOK(ctx, %q, %s{\n", e.Path, name)
+	for _, v := range vals {
+		buf.WriteString(v + ",\n")
+	}
+	buf.WriteString("})\n}\n\n")
+	return nil
+}
+
+// genService generates the service wrapper method.
+func genService(buf *bytes.Buffer, e Endpoint) error {
+	if e.Service == "" {
+		return nil
+	}
+	svc, ok := services[e.Service]
+	if !ok {
+		return fmt.Errorf("endpoint %s has unknown service %q", e.Method, e.Service)
+	}
+	method := e.Method
+	for _, prefix := range svc[1:] {
+		if s := strings.TrimPrefix(method, prefix); s != method && s != "" && strings.ToUpper(s[:1]) == s[:1] {
+			method = s
+			break
+		}
+	}
+	decl, args, res := []string{"ctx context.Context"}, []string{"ctx"}, "(bool, error)"
+	if e.Request != nil {
+		for _, p := range e.Request.Params {
+			decl, args = append(decl, p.Name+" "+p.Type), append(args, p.Name)
+		}
+	} else if e.Response.El != "" {
+		res = "(" + e.Response.ElType + ", error)"
+	} else {
+		res = "(*" + e.Response.Type + ", error)"
+	}
+	fmt.Fprintf(buf, "// %s %s\n", method, e.Doc)
+	fmt.Fprintf(buf, "func (svc *%s) %s(%s) %s {\n", svc[0], method, strings.Join(decl, ", "), res)
+	fmt.Fprintf(buf, "return svc.cl.%s(%s)\n}\n\n", e.Method, strings.Join(args, ", "))
+	return nil
+}
+
+// checkType checks that typ is a supported type.
+func checkType(typ string) error {
+	switch typ {
+	case "string", "bool", "int", "uint":
+		return nil
+	}
+	return fmt.Errorf("unsupported type %q", typ)
+}
+
+// zero returns the zero value for the type.
+func zero(typ string) string {
+	switch typ {
+	case "string":
+		return `""`
+	case "bool":
+		return "false"
+	}
+	return "0"
+}
+
+// decode returns the expression decoding the element from res.
+func decode(typ, el string) string {
+	switch typ {
+	case "bool":
+		return fmt.Sprintf("xmlStr(res, %q) == \"1\"", el)
+	case "int":
+		return fmt.Sprintf("xmlInt(res, %q)", el)
+	case "uint":
+		return fmt.Sprintf("uint(xmlUint(res, %q))", el)
+	}
+	return fmt.Sprintf("xmlStr(res, %q)", el)
+}
+
+// exported returns the element name as an exported Go identifier.
+func exported(el string) string {
+	return strings.ToUpper(el[:1]) + el[1:]
+}
+
+const hdr = `// Code generated by internal/endpointgen. DO NOT EDIT.
+
+package hilink
+
+import (
+	"context"
+)
+
+`
@@ -0,0 +1,78 @@
+//go:build go1.23
+// +build go1.23
+
+package hilink
+
+import (
+	"context"
+	"iter"
+)
+
+// SmsAll returns an iterator over all SMS messages in an inbox, transparently
+// fetching pages of SmsPageSize messages as the iteration progresses. A
+// failed request is yielded as an error, ending the iteration.
+func (cl *Client) SmsAll(ctx context.Context, boxType SmsBoxType) iter.Seq2[SmsMessage, error] {
+	return pages(func(page uint) ([]SmsMessage, error) {
+		return cl.SmsMessages(ctx, boxType, page, SmsPageSize)
+	}, SmsPageSize)
+}
+
+// PhonebookAll returns an iterator over all phonebook entries in a group,
+// transparently fetching pages of SmsPageSize entries as the iteration
+// progresses. A failed request is yielded as an error, ending the iteration.
+func (cl *Client) PhonebookAll(ctx context.Context, group uint, sim bool) iter.Seq2[PhonebookEntry, error] {
+	return pages(func(page uint) ([]PhonebookEntry, error) {
+		return cl.PhonebookEntries(ctx, group, page, SmsPageSize, sim)
+	}, SmsPageSize)
+}
+
+// WifiHostsAll returns an iterator over the Wi-Fi hosts. A failed request is
+// yielded as an error, ending the iteration.
+func (cl *Client) WifiHostsAll(ctx context.Context) iter.Seq2[WifiHost, error] {
+	return func(yield func(WifiHost, error) bool) {
+		hosts, err := cl.WifiHosts(ctx)
+		if err != nil {
+			yield(WifiHost{}, err)
+			return
+		}
+		for _, h := range hosts {
+			if !yield(h, nil) {
+				return
+			}
+		}
+	}
+}
+
+// All returns an iterator over all SMS messages in an inbox. See
+// Client.SmsAll.
+func (svc *SMSService) All(ctx context.Context, boxType SmsBoxType) iter.Seq2[SmsMessage, error] {
+	return svc.cl.SmsAll(ctx, boxType)
+}
+
+// HostsAll returns an iterator over the Wi-Fi hosts. See Client.WifiHostsAll.
+func (svc *WlanService) HostsAll(ctx context.Context) iter.Seq2[WifiHost, error] {
+	return svc.cl.WifiHostsAll(ctx)
+}
+
+// pages returns an iterator over the items returned by f for each page
+// (starting at 1), until f returns a page with less than size items.
+func pages[T any](f func(uint) ([]T, error), size int) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for page := uint(1); ; page++ {
+			items, err := f(page)
+			if err != nil {
+				var zero T
+				yield(zero, err)
+				return
+			}
+			for _, item := range items {
+				if !yield(item, nil) {
+					return
+				}
+			}
+			if len(items) < size {
+				return
+			}
+		}
+	}
+}
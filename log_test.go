@@ -0,0 +1,57 @@
+package hilink
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestWithLogfRedact(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		_, _ = w.Write([]byte(`<response><SesInfo>SessionID=secret-session</SesInfo><TokInfo>secret-token</TokInfo></response>`))
+	}))
+	defer s.Close()
+	tests := []struct {
+		name     string
+		noRedact bool
+		derived  ClientOption
+		exp      bool
+	}{
+		{"redacted", false, nil, false},
+		{"not redacted", true, nil, true},
+		{"derived not redacted", false, WithNoRedact(true), true},
+		{"derived redacted", true, WithNoRedact(false), false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var mu sync.Mutex
+			var buf bytes.Buffer
+			logf := func(s string, v ...interface{}) {
+				mu.Lock()
+				defer mu.Unlock()
+				fmt.Fprintf(&buf, s+"\n", v...)
+			}
+			cl := NewClient(WithURL(s.URL), WithNoRedact(test.noRedact), WithLogf(logf))
+			if test.derived != nil {
+				cl = cl.With(test.derived)
+			}
+			if _, _, err := cl.NewSessionAndTokenID(context.Background()); err != nil {
+				t.Fatalf("expected no error, got: %v", err)
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			logged := buf.String()
+			if !strings.Contains(logged, "TokInfo") {
+				t.Fatalf("expected response to be logged, got: %s", logged)
+			}
+			if strings.Contains(logged, "secret-token") != test.exp {
+				t.Errorf("expected token logged %t, got: %s", test.exp, logged)
+			}
+		})
+	}
+}
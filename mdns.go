@@ -0,0 +1,138 @@
+package hilink
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+// mdnsAddr is the mDNS multicast address.
+const mdnsAddr = "224.0.0.251:5353"
+
+// searchMDNS sends mDNS queries for the services, probing each host that
+// responds for the HiLink API.
+func (d *discoverer) searchMDNS(ctx context.Context, wait time.Duration) error {
+	req := mdnsQuery(d.mdnsServices...)
+	seen := make(map[string]bool)
+	return search(ctx, wait, mdnsAddr, req, func(wg *sync.WaitGroup, buf []byte, from net.Addr) {
+		names, ok := mdnsInstances(buf)
+		addr, _ := from.(*net.UDPAddr)
+		if !ok || addr == nil || seen[addr.IP.String()] {
+			return
+		}
+		seen[addr.IP.String()] = true
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			dev, err := probeDevice(ctx, "http://"+addr.IP.String()+"/", DefaultTimeout)
+			if err != nil {
+				return
+			}
+			if len(names) != 0 {
+				dev.Name = names[0]
+			}
+			d.add(*dev, DiscoverMDNS)
+		}()
+	})
+}
+
+// mdnsQuery builds a mDNS query message with a PTR question for each of the
+// services, requesting unicast responses.
+func mdnsQuery(services ...string) []byte {
+	buf := make([]byte, 12)
+	binary.BigEndian.PutUint16(buf[4:], uint16(len(services)))
+	for _, service := range services {
+		for _, label := range strings.Split(strings.TrimSuffix(service, "."), ".") {
+			buf = append(buf, byte(len(label)))
+			buf = append(buf, label...)
+		}
+		// root, type PTR (12), class IN (1) with the unicast response bit
+		buf = append(buf, 0, 0, 12, 0x80, 1)
+	}
+	return buf
+}
+
+// mdnsInstances returns the service instance names of the PTR records in a
+// mDNS response message, returning false when buf is not a response.
+func mdnsInstances(buf []byte) ([]string, bool) {
+	if len(buf) < 12 || buf[2]&0x80 == 0 {
+		return nil, false
+	}
+	qd := int(binary.BigEndian.Uint16(buf[4:]))
+	rr := int(binary.BigEndian.Uint16(buf[6:])) +
+		int(binary.BigEndian.Uint16(buf[8:])) +
+		int(binary.BigEndian.Uint16(buf[10:]))
+	off := 12
+	// skip questions
+	for i := 0; i < qd; i++ {
+		_, n, err := mdnsName(buf, off)
+		if err != nil || n+4 > len(buf) {
+			return nil, false
+		}
+		off = n + 4
+	}
+	// read records
+	var names []string
+	for i := 0; i < rr; i++ {
+		_, n, err := mdnsName(buf, off)
+		if err != nil || n+10 > len(buf) {
+			return names, true
+		}
+		typ := binary.BigEndian.Uint16(buf[n:])
+		l := int(binary.BigEndian.Uint16(buf[n+8:]))
+		off = n + 10
+		if off+l > len(buf) {
+			return names, true
+		}
+		if typ == 12 {
+			if name, _, err := mdnsName(buf, off); err == nil {
+				// instance name is the first label
+				if i := strings.Index(name, "."); i != -1 {
+					name = name[:i]
+				}
+				names = append(names, name)
+			}
+		}
+		off += l
+	}
+	return names, true
+}
+
+// mdnsName reads the (possibly compressed) DNS name at off, returning the
+// name and the offset after the name.
+func mdnsName(buf []byte, off int) (string, int, error) {
+	var labels []string
+	end := -1
+	for jumps := 0; ; {
+		if off >= len(buf) {
+			return "", 0, errors.New("invalid name")
+		}
+		l := int(buf[off])
+		switch {
+		case l == 0:
+			if end == -1 {
+				end = off + 1
+			}
+			return strings.Join(labels, "."), end, nil
+		case l&0xc0 == 0xc0:
+			// compression pointer
+			if off+1 >= len(buf) || jumps > 10 {
+				return "", 0, errors.New("invalid name")
+			}
+			if end == -1 {
+				end = off + 2
+			}
+			off, jumps = int(binary.BigEndian.Uint16(buf[off:])&0x3fff), jumps+1
+		default:
+			if off+1+l > len(buf) {
+				return "", 0, errors.New("invalid name")
+			}
+			labels = append(labels, string(buf[off+1:off+1+l]))
+			off += 1 + l
+		}
+	}
+}
@@ -0,0 +1,234 @@
+package hilink
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MonitorSource is a bitmask of the endpoints polled by a Monitor.
+type MonitorSource uint
+
+// MonitorSource values.
+const (
+	MonitorSignal MonitorSource = 1 << iota
+	MonitorStatus
+	MonitorTraffic
+	MonitorMonth
+	MonitorSms
+
+	// MonitorAll polls all endpoints.
+	MonitorAll = MonitorSignal | MonitorStatus | MonitorTraffic | MonitorMonth | MonitorSms
+)
+
+// Snapshot is a typed snapshot of the device state, as polled by a Monitor.
+// Values for sources not polled by the Monitor are nil.
+type Snapshot struct {
+	Time    time.Time
+	Signal  *ServingCell
+	Status  *Status
+	Traffic *Traffic
+	Month   *MonthTraffic
+	Sms     *SmsCounts
+}
+
+// MonitorOption is a monitor option.
+type MonitorOption func(*Monitor)
+
+// WithSources is a monitor option to set the polled endpoints.
+func WithSources(sources MonitorSource) MonitorOption {
+	return func(m *Monitor) {
+		m.sources = sources
+	}
+}
+
+// WithBackoff is a monitor option to set the minimum and maximum delay
+// between polls after consecutive poll failures. The delay starts at min, and
+// doubles after each failure, up to max.
+func WithBackoff(min, max time.Duration) MonitorOption {
+	return func(m *Monitor) {
+		m.minBackoff, m.maxBackoff = min, max
+	}
+}
+
+// WithUsageAlert is a monitor option to add a monthly data usage alert, that
+// calls f with the month traffic statistics when the total month traffic
+// (upload and download) reaches threshold bytes. The alert fires once, and is
+// re-armed after the month traffic drops back below the threshold (ie, at the
+// start of a new billing cycle). Adding an alert enables polling of the month
+// traffic statistics.
+//
+// The alert is checked from the goroutine calling Run, and f should not block.
+func WithUsageAlert(threshold uint64, f func(MonthTraffic)) MonitorOption {
+	return func(m *Monitor) {
+		m.alerts = append(m.alerts, &usageAlert{
+			threshold: threshold,
+			f:         f,
+		})
+	}
+}
+
+// usageAlert is a monthly data usage alert.
+type usageAlert struct {
+	threshold uint64
+	f         func(MonthTraffic)
+	fired     bool
+}
+
+// check checks the alert against the month traffic statistics, calling the
+// alert func when the threshold is crossed.
+func (a *usageAlert) check(month MonthTraffic) {
+	switch total := month.Total(); {
+	case total < a.threshold:
+		a.fired = false
+	case !a.fired:
+		a.fired = true
+		a.f(month)
+	}
+}
+
+// Monitor periodically polls a device, delivering typed snapshots of the
+// device state.
+type Monitor struct {
+	cl         *Client
+	interval   time.Duration
+	sources    MonitorSource
+	minBackoff time.Duration
+	maxBackoff time.Duration
+	alerts     []*usageAlert
+	snapshots  chan Snapshot
+	errs       chan error
+	subs       map[*subscription]bool
+	sync.Mutex
+}
+
+// NewMonitor creates a new monitor for the client, polling every interval.
+// By default, all sources are polled.
+func NewMonitor(cl *Client, interval time.Duration, opts ...MonitorOption) *Monitor {
+	m := &Monitor{
+		cl:         cl,
+		interval:   interval,
+		sources:    MonitorAll,
+		minBackoff: time.Second,
+		maxBackoff: 5 * time.Minute,
+		snapshots:  make(chan Snapshot, 1),
+		errs:       make(chan error, 1),
+		subs:       make(map[*subscription]bool),
+	}
+	for _, o := range opts {
+		o(m)
+	}
+	if len(m.alerts) != 0 {
+		m.sources |= MonitorMonth
+	}
+	return m
+}
+
+// Snapshots returns the snapshot channel. Only the latest snapshot is
+// buffered, and older snapshots not yet received are discarded. The channel is
+// closed when Run returns.
+func (m *Monitor) Snapshots() <-chan Snapshot {
+	return m.snapshots
+}
+
+// Errors returns the error channel. Poll errors are delivered on a best
+// effort basis, and do not stop the monitor. The channel is closed when Run
+// returns.
+func (m *Monitor) Errors() <-chan error {
+	return m.errs
+}
+
+// Run polls the device until ctx is done, delivering snapshots on the
+// snapshot channel. After a failed poll, a new session is started (logging in
+// again when the client has credentials), and polling backs off until a poll
+// succeeds. Run may only be called once.
+func (m *Monitor) Run(ctx context.Context) error {
+	defer close(m.snapshots)
+	defer close(m.errs)
+	defer m.closeSubs()
+	backoff := time.Duration(0)
+	var prev *Snapshot
+	for {
+		delay := m.interval
+		snap, err := m.Poll(ctx)
+		switch {
+		case err != nil && ctx.Err() != nil:
+			return ctx.Err()
+		case err != nil:
+			select {
+			case m.errs <- err:
+			default:
+			}
+			// back off and start a new session
+			if backoff = 2 * backoff; backoff < m.minBackoff {
+				backoff = m.minBackoff
+			}
+			if backoff > m.maxBackoff {
+				backoff = m.maxBackoff
+			}
+			delay = backoff
+			if err := m.cl.Relogin(ctx); err != nil {
+				select {
+				case m.errs <- err:
+				default:
+				}
+			}
+		default:
+			backoff = 0
+			for _, a := range m.alerts {
+				a.check(*snap.Month)
+			}
+			if prev != nil {
+				m.publish(diffSnapshots(prev, snap))
+			}
+			prev = snap
+			// replace any snapshot not yet received
+			select {
+			case <-m.snapshots:
+			default:
+			}
+			m.snapshots <- *snap
+		}
+		t := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return ctx.Err()
+		case <-t.C:
+		}
+	}
+}
+
+// Poll polls the device once, returning a snapshot of the monitored sources.
+func (m *Monitor) Poll(ctx context.Context) (*Snapshot, error) {
+	snap := &Snapshot{
+		Time: time.Now(),
+	}
+	var err error
+	if m.sources&MonitorSignal != 0 {
+		if snap.Signal, err = m.cl.ServingCell(ctx); err != nil {
+			return nil, err
+		}
+	}
+	if m.sources&MonitorStatus != 0 {
+		if snap.Status, err = m.cl.Status(ctx); err != nil {
+			return nil, err
+		}
+	}
+	if m.sources&MonitorTraffic != 0 {
+		if snap.Traffic, err = m.cl.Traffic(ctx); err != nil {
+			return nil, err
+		}
+	}
+	if m.sources&MonitorMonth != 0 {
+		if snap.Month, err = m.cl.MonthTraffic(ctx); err != nil {
+			return nil, err
+		}
+	}
+	if m.sources&MonitorSms != 0 {
+		if snap.Sms, err = m.cl.SmsCounts(ctx); err != nil {
+			return nil, err
+		}
+	}
+	return snap, nil
+}
@@ -0,0 +1,144 @@
+package pdu
+
+// gsm7Basic is the GSM 03.38 basic character set, in septet order.
+var gsm7Basic = []rune("@£$¥èéùìòÇ\nØø\rÅåΔ_ΦΓΛΩΠΨΣΘΞ\x1bÆæßÉ !\"#¤%&'()*+,-./0123456789:;<=>?" +
+	"¡ABCDEFGHIJKLMNOPQRSTUVWXYZÄÖÑÜ§¿abcdefghijklmnopqrstuvwxyzäöñüà")
+
+// gsm7Ext is the GSM 03.38 extension character set, keyed by septet.
+var gsm7Ext = map[byte]rune{
+	0x0a: '\f',
+	0x14: '^',
+	0x28: '{',
+	0x29: '}',
+	0x2f: '\\',
+	0x3c: '[',
+	0x3d: '~',
+	0x3e: ']',
+	0x40: '|',
+	0x65: '€',
+}
+
+// gsm7Escape is the escape to the extension character set.
+const gsm7Escape = 0x1b
+
+// gsm7Septets maps runes to their septet(s).
+var gsm7Septets = func() map[rune][]byte {
+	m := make(map[rune][]byte)
+	for i, r := range gsm7Basic {
+		if i != gsm7Escape {
+			m[r] = []byte{byte(i)}
+		}
+	}
+	for c, r := range gsm7Ext {
+		m[r] = []byte{gsm7Escape, c}
+	}
+	return m
+}()
+
+// IsGSM7 returns true when s can be encoded with the GSM-7 alphabet.
+func IsGSM7(s string) bool {
+	for _, r := range s {
+		if _, ok := gsm7Septets[r]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// SeptetLen returns the number of GSM-7 septets used by r (2 for characters
+// in the extension character set), or 0 when r can not be encoded with the
+// GSM-7 alphabet.
+func SeptetLen(r rune) int {
+	return len(gsm7Septets[r])
+}
+
+// encodeGSM7 encodes s as unpacked GSM-7 septets.
+func encodeGSM7(s string) ([]byte, error) {
+	var b []byte
+	for _, r := range s {
+		septets, ok := gsm7Septets[r]
+		if !ok {
+			return nil, ErrInvalidCharacter
+		}
+		b = append(b, septets...)
+	}
+	return b, nil
+}
+
+// decodeGSM7 decodes unpacked GSM-7 septets.
+func decodeGSM7(b []byte) string {
+	var s []rune
+	for i := 0; i < len(b); i++ {
+		c := b[i] & 0x7f
+		if c == gsm7Escape && i+1 < len(b) {
+			i++
+			if r, ok := gsm7Ext[b[i]&0x7f]; ok {
+				s = append(s, r)
+				continue
+			}
+			c = b[i] & 0x7f
+		}
+		s = append(s, gsm7Basic[c])
+	}
+	return string(s)
+}
+
+// pack7 packs septets into octets, starting after fill bits.
+func pack7(septets []byte, fill int) []byte {
+	n := fill + 7*len(septets)
+	b := make([]byte, (n+7)/8)
+	for i, c := range septets {
+		p := fill + 7*i
+		b[p/8] |= c << uint(p%8)
+		if p%8 > 1 {
+			b[p/8+1] |= c >> uint(8-p%8)
+		}
+	}
+	return b
+}
+
+// unpack7 unpacks n septets from octets, starting after fill bits.
+func unpack7(b []byte, n, fill int) []byte {
+	septets := make([]byte, 0, n)
+	for i := 0; i < n; i++ {
+		p := fill + 7*i
+		if p/8 >= len(b) {
+			break
+		}
+		c := b[p/8] >> uint(p%8)
+		if p%8 > 1 && p/8+1 < len(b) {
+			c |= b[p/8+1] << uint(8-p%8)
+		}
+		septets = append(septets, c&0x7f)
+	}
+	return septets
+}
+
+// EncodePackedGSM7 encodes s as packed GSM-7 septets (ie, for raw USSD
+// strings). When the final octet has 7 spare bits, they are filled with a
+// CR, as per GSM 03.38.
+func EncodePackedGSM7(s string) ([]byte, error) {
+	septets, err := encodeGSM7(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(septets)%8 == 7 {
+		septets = append(septets, '\r')
+	}
+	return pack7(septets, 0), nil
+}
+
+// DecodePackedGSM7 decodes packed GSM-7 septets (ie, raw USSD strings),
+// removing any CR padding.
+func DecodePackedGSM7(b []byte) string {
+	septets := unpack7(b, len(b)*8/7, 0)
+	if n := len(septets); n != 0 && n%8 == 0 && (septets[n-1] == '\r' || septets[n-1] == 0) {
+		septets = septets[:n-1]
+	}
+	return decodeGSM7(septets)
+}
+
+// DecodeUCS2 decodes big endian UCS-2 (UTF-16) text.
+func DecodeUCS2(b []byte) string {
+	return decodeUCS2(b)
+}
@@ -0,0 +1,420 @@
+// Package pdu provides a small SMS PDU (GSM 03.40) encoder and decoder, for
+// sending and receiving raw SMS PDUs with a Hilink device.
+package pdu
+
+import (
+	"encoding/hex"
+	"strings"
+	"time"
+	"unicode/utf16"
+)
+
+// Error is the error type.
+type Error string
+
+// Error values.
+const (
+	// ErrInvalidPDU is the invalid pdu error.
+	ErrInvalidPDU Error = "invalid pdu"
+	// ErrInvalidAddress is the invalid address error.
+	ErrInvalidAddress Error = "invalid address"
+	// ErrInvalidCharacter is the invalid character error.
+	ErrInvalidCharacter Error = "invalid character"
+	// ErrUserDataTooLong is the user data too long error.
+	ErrUserDataTooLong Error = "user data too long"
+)
+
+// Error satisfies the error interface.
+func (err Error) Error() string {
+	return string(err)
+}
+
+// Alphabet represents the different user data alphabets.
+type Alphabet int
+
+// Alphabet values.
+const (
+	AlphabetGSM7 Alphabet = iota
+	Alphabet8Bit
+	AlphabetUCS2
+)
+
+// DCS values.
+const (
+	// DCSGSM7 is the data coding scheme for GSM-7 text.
+	DCSGSM7 byte = 0x00
+	// DCS8Bit is the data coding scheme for binary (8-bit) data.
+	DCS8Bit byte = 0x04
+	// DCSUCS2 is the data coding scheme for UCS-2 text.
+	DCSUCS2 byte = 0x08
+	// DCSFlash is the data coding scheme flag for class 0 (flash) messages.
+	DCSFlash byte = 0x10
+)
+
+// AlphabetOf returns the alphabet of a data coding scheme.
+func AlphabetOf(dcs byte) Alphabet {
+	switch {
+	case dcs&0xf0 == 0xf0:
+		if dcs&0x04 != 0 {
+			return Alphabet8Bit
+		}
+		return AlphabetGSM7
+	case dcs&0xc0 == 0x00, dcs&0xc0 == 0x40:
+		switch dcs & 0x0c {
+		case 0x04:
+			return Alphabet8Bit
+		case 0x08:
+			return AlphabetUCS2
+		}
+	}
+	return AlphabetGSM7
+}
+
+// Submit is a SMS-SUBMIT (mobile originated) message.
+type Submit struct {
+	// SMSC is the service center address. When empty, the device's
+	// configured service center is used.
+	SMSC string
+	// To is the destination address.
+	To string
+	// Text is the message text. Ignored when Data is set.
+	Text string
+	// Data is binary user data, sent with the 8-bit alphabet.
+	Data []byte
+	// UDH is the optional user data header (without the length octet).
+	UDH []byte
+	// DCS overrides the data coding scheme, when non-zero. Otherwise the
+	// data coding scheme is determined from Text or Data.
+	DCS byte
+	// Flash sends the message as a class 0 (flash) message.
+	Flash bool
+	// StatusReport requests a delivery status report.
+	StatusReport bool
+}
+
+// Encode encodes the message as a PDU, returning the PDU and the TPDU length
+// (ie, the length of the PDU excluding the service center address), as
+// required by AT+CMGS and most device APIs.
+func (s Submit) Encode() ([]byte, int, error) {
+	smsc, err := encodeSMSC(s.SMSC)
+	if err != nil {
+		return nil, 0, err
+	}
+	da, err := encodeAddress(s.To)
+	if err != nil {
+		return nil, 0, err
+	}
+	// determine dcs
+	dcs := s.DCS
+	if dcs == 0 {
+		switch {
+		case s.Data != nil:
+			dcs = DCS8Bit
+		case !IsGSM7(s.Text):
+			dcs = DCSUCS2
+		}
+		if s.Flash {
+			dcs |= DCSFlash
+		}
+	}
+	ud, udl, err := encodeUserData(AlphabetOf(dcs), s.Text, s.Data, s.UDH)
+	if err != nil {
+		return nil, 0, err
+	}
+	// first octet: SMS-SUBMIT, no validity period
+	first := byte(0x01)
+	if len(s.UDH) != 0 {
+		first |= 0x40
+	}
+	if s.StatusReport {
+		first |= 0x20
+	}
+	tpdu := []byte{first, 0x00}
+	tpdu = append(tpdu, da...)
+	tpdu = append(tpdu, 0x00, dcs, byte(udl))
+	tpdu = append(tpdu, ud...)
+	return append(smsc, tpdu...), len(tpdu), nil
+}
+
+// EncodeHex encodes the message as a hex PDU string, returning the PDU and the
+// TPDU length.
+func (s Submit) EncodeHex() (string, int, error) {
+	buf, n, err := s.Encode()
+	if err != nil {
+		return "", 0, err
+	}
+	return strings.ToUpper(hex.EncodeToString(buf)), n, nil
+}
+
+// Deliver is a SMS-DELIVER (mobile terminated) message.
+type Deliver struct {
+	SMSC      string
+	From      string
+	Timestamp time.Time
+	PID       byte
+	DCS       byte
+	UDH       []byte
+	// Text is the decoded message text, for GSM-7 and UCS-2 messages.
+	Text string
+	// Data is the raw user data (without the header), for 8-bit messages.
+	Data []byte
+	// StatusReport indicates a status report was requested by the sender.
+	StatusReport bool
+}
+
+// Flash returns true when the message is a class 0 (flash) message.
+func (d *Deliver) Flash() bool {
+	return d.DCS&0xd3 == 0x10 || d.DCS&0xf3 == 0xf0
+}
+
+// DecodeHex decodes a hex SMS-DELIVER PDU, including the service center
+// address.
+func DecodeHex(s string) (*Deliver, error) {
+	buf, err := hex.DecodeString(strings.TrimSpace(s))
+	if err != nil {
+		return nil, ErrInvalidPDU
+	}
+	return Decode(buf)
+}
+
+// Decode decodes a SMS-DELIVER PDU, including the service center address.
+func Decode(buf []byte) (*Deliver, error) {
+	r := &reader{buf: buf}
+	d := new(Deliver)
+	// smsc
+	n := int(r.byte())
+	if n != 0 {
+		toa := r.byte()
+		d.SMSC = decodeSemiOctets(r.bytes(n-1), toa)
+	}
+	// first octet
+	first := r.byte()
+	if first&0x03 != 0x00 {
+		return nil, ErrInvalidPDU
+	}
+	d.StatusReport = first&0x20 != 0
+	// originating address
+	digits := int(r.byte())
+	toa := r.byte()
+	oa := r.bytes((digits + 1) / 2)
+	if toa&0x70 == 0x50 {
+		d.From = decodeGSM7(unpack7(oa, digits*4/7, 0))
+	} else {
+		d.From = decodeSemiOctets(oa, toa)
+	}
+	d.PID, d.DCS = r.byte(), r.byte()
+	d.Timestamp = decodeTimestamp(r.bytes(7))
+	udl := int(r.byte())
+	ud := r.rest()
+	if r.err != nil {
+		return nil, r.err
+	}
+	// user data header
+	var udhLen int
+	if first&0x40 != 0 && len(ud) != 0 {
+		udhLen = int(ud[0]) + 1
+		if udhLen > len(ud) {
+			return nil, ErrInvalidPDU
+		}
+		d.UDH = ud[1:udhLen]
+	}
+	// user data
+	switch AlphabetOf(d.DCS) {
+	case AlphabetGSM7:
+		skip := (udhLen*8 + 6) / 7
+		fill := skip*7 - udhLen*8
+		if udl < skip {
+			return nil, ErrInvalidPDU
+		}
+		d.Text = decodeGSM7(unpack7(ud[udhLen:], udl-skip, fill))
+	case AlphabetUCS2:
+		d.Text = decodeUCS2(ud[udhLen:])
+	default:
+		d.Data = ud[udhLen:]
+	}
+	return d, nil
+}
+
+// encodeUserData encodes text or data with the alphabet, returning the user
+// data and the user data length (in septets for GSM-7, octets otherwise).
+func encodeUserData(alphabet Alphabet, text string, data, udh []byte) ([]byte, int, error) {
+	var hdr []byte
+	if len(udh) != 0 {
+		hdr = append([]byte{byte(len(udh))}, udh...)
+	}
+	switch alphabet {
+	case AlphabetGSM7:
+		septets, err := encodeGSM7(text)
+		if err != nil {
+			return nil, 0, err
+		}
+		skip := (len(hdr)*8 + 6) / 7
+		fill := skip*7 - len(hdr)*8
+		udl := skip + len(septets)
+		if udl > 160 {
+			return nil, 0, ErrUserDataTooLong
+		}
+		return append(hdr, pack7(septets, fill)...), udl, nil
+	case AlphabetUCS2:
+		data = encodeUCS2(text)
+	case Alphabet8Bit:
+		if data == nil {
+			data = []byte(text)
+		}
+	}
+	ud := append(hdr, data...)
+	if len(ud) > 140 {
+		return nil, 0, ErrUserDataTooLong
+	}
+	return ud, len(ud), nil
+}
+
+// encodeSMSC encodes a service center address, including its length octet.
+func encodeSMSC(addr string) ([]byte, error) {
+	if addr == "" {
+		return []byte{0x00}, nil
+	}
+	toa, digits, err := parseAddress(addr)
+	if err != nil {
+		return nil, err
+	}
+	b := encodeSemiOctets(digits)
+	return append([]byte{byte(len(b) + 1), toa}, b...), nil
+}
+
+// encodeAddress encodes a destination address, including its length octet.
+func encodeAddress(addr string) ([]byte, error) {
+	toa, digits, err := parseAddress(addr)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte{byte(len(digits)), toa}, encodeSemiOctets(digits)...), nil
+}
+
+// parseAddress parses a phone number, returning its type of address and
+// digits.
+func parseAddress(addr string) (byte, string, error) {
+	toa := byte(0x81)
+	addr = strings.TrimSpace(addr)
+	if strings.HasPrefix(addr, "+") {
+		toa, addr = 0x91, addr[1:]
+	}
+	var b strings.Builder
+	for _, c := range addr {
+		switch {
+		case c >= '0' && c <= '9', c == '*', c == '#':
+			b.WriteRune(c)
+		case c == ' ', c == '-', c == '(', c == ')':
+		default:
+			return 0, "", ErrInvalidAddress
+		}
+	}
+	if b.Len() == 0 || b.Len() > 20 {
+		return 0, "", ErrInvalidAddress
+	}
+	return toa, b.String(), nil
+}
+
+// semiOctets are the semi-octet digit values.
+const semiOctets = "0123456789*#abc"
+
+// encodeSemiOctets encodes digits as swapped semi-octets.
+func encodeSemiOctets(digits string) []byte {
+	if len(digits)%2 != 0 {
+		digits += "F"
+	}
+	b := make([]byte, len(digits)/2)
+	for i := 0; i < len(digits); i += 2 {
+		b[i/2] = semiOctet(digits[i+1])<<4 | semiOctet(digits[i])
+	}
+	return b
+}
+
+// semiOctet returns the semi-octet value of c.
+func semiOctet(c byte) byte {
+	if i := strings.IndexByte(semiOctets, c); i != -1 {
+		return byte(i)
+	}
+	return 0x0f
+}
+
+// decodeSemiOctets decodes swapped semi-octets, prefixing international
+// numbers with a +.
+func decodeSemiOctets(b []byte, toa byte) string {
+	var s strings.Builder
+	if toa&0x70 == 0x10 {
+		s.WriteByte('+')
+	}
+	for _, c := range b {
+		for _, n := range []byte{c & 0x0f, c >> 4} {
+			if n != 0x0f {
+				s.WriteByte(semiOctets[n])
+			}
+		}
+	}
+	return s.String()
+}
+
+// decodeTimestamp decodes a service center timestamp.
+func decodeTimestamp(b []byte) time.Time {
+	if len(b) != 7 {
+		return time.Time{}
+	}
+	v := make([]int, 7)
+	for i, c := range b {
+		v[i] = int(c&0x0f)*10 + int(c>>4)
+	}
+	// timezone is in quarters of an hour, with the sign in bit 3
+	tz := int(b[6]&0x07)*10 + int(b[6]>>4)
+	if b[6]&0x08 != 0 {
+		tz = -tz
+	}
+	loc := time.FixedZone("", tz*15*60)
+	return time.Date(2000+v[0], time.Month(v[1]), v[2], v[3], v[4], v[5], 0, loc)
+}
+
+// encodeUCS2 encodes s as big endian UTF-16.
+func encodeUCS2(s string) []byte {
+	u := utf16.Encode([]rune(s))
+	b := make([]byte, 2*len(u))
+	for i, c := range u {
+		b[2*i], b[2*i+1] = byte(c>>8), byte(c)
+	}
+	return b
+}
+
+// decodeUCS2 decodes big endian UTF-16.
+func decodeUCS2(b []byte) string {
+	u := make([]uint16, len(b)/2)
+	for i := range u {
+		u[i] = uint16(b[2*i])<<8 | uint16(b[2*i+1])
+	}
+	return string(utf16.Decode(u))
+}
+
+// reader reads a PDU, recording the first error encountered.
+type reader struct {
+	buf []byte
+	err error
+}
+
+// bytes reads n bytes.
+func (r *reader) bytes(n int) []byte {
+	if r.err != nil || n < 0 || n > len(r.buf) {
+		r.err = ErrInvalidPDU
+		return make([]byte, n&0xff)
+	}
+	b := r.buf[:n]
+	r.buf = r.buf[n:]
+	return b
+}
+
+// byte reads a byte.
+func (r *reader) byte() byte {
+	return r.bytes(1)[0]
+}
+
+// rest returns the remaining bytes.
+func (r *reader) rest() []byte {
+	return r.buf
+}
@@ -0,0 +1,178 @@
+package pdu
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestPackedGSM7(t *testing.T) {
+	tests := []string{
+		"",
+		"a",
+		"hellohello",
+		// 7 septets, padded with a CR
+		"1234567",
+		"12345678",
+		"*100#",
+		"Balance: 12.50€ {ok} [x] ~^|\\",
+		"@£$¥èéùìòÇØøÅåΔ_ΦΓΛΩΠΨΣΘΞÆæßÉ¡ÄÖÑÜ§¿äöñüà",
+	}
+	for _, s := range tests {
+		b, err := EncodePackedGSM7(s)
+		if err != nil {
+			t.Fatalf("%q: expected no error, got: %v", s, err)
+		}
+		if d := DecodePackedGSM7(b); d != s {
+			t.Errorf("%q: expected round trip, got: %q", s, d)
+		}
+	}
+	b, err := EncodePackedGSM7("hellohello")
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if exp := []byte{0xe8, 0x32, 0x9b, 0xfd, 0x46, 0x97, 0xd9, 0xec, 0x37}; !bytes.Equal(b, exp) {
+		t.Errorf("expected % x, got: % x", exp, b)
+	}
+	if _, err := EncodePackedGSM7("日本"); err != ErrInvalidCharacter {
+		t.Errorf("expected ErrInvalidCharacter, got: %v", err)
+	}
+}
+
+func TestGSM7(t *testing.T) {
+	tests := []struct {
+		s     string
+		gsm7  bool
+		septs []int
+	}{
+		{"abc", true, []int{1, 1, 1}},
+		{"€[", true, []int{2, 2}},
+		{"\x1b", false, []int{0}},
+		{"日", false, []int{0}},
+	}
+	for _, test := range tests {
+		if ok := IsGSM7(test.s); ok != test.gsm7 {
+			t.Errorf("%q: expected IsGSM7 %t, got: %t", test.s, test.gsm7, ok)
+		}
+		for i, r := range []rune(test.s) {
+			if n := SeptetLen(r); n != test.septs[i] {
+				t.Errorf("%q: expected SeptetLen(%q) %d, got: %d", test.s, r, test.septs[i], n)
+			}
+		}
+	}
+}
+
+func TestUCS2(t *testing.T) {
+	for _, s := range []string{"", "hello", "Привет", "日本語", "emoji 😀"} {
+		if d := DecodeUCS2(encodeUCS2(s)); d != s {
+			t.Errorf("%q: expected round trip, got: %q", s, d)
+		}
+	}
+	if b, exp := encodeUCS2("A😀"), []byte{0x00, 0x41, 0xd8, 0x3d, 0xde, 0x00}; !bytes.Equal(b, exp) {
+		t.Errorf("expected % x, got: % x", exp, b)
+	}
+}
+
+func TestSubmitEncodeHex(t *testing.T) {
+	s, n, err := Submit{To: "+46708251358", Text: "hellohello"}.EncodeHex()
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if exp := "0001000B916407281553F800000AE8329BFD4697D9EC37"; s != exp {
+		t.Errorf("expected %s, got: %s", exp, s)
+	}
+	if n != 22 {
+		t.Errorf("expected tpdu length 22, got: %d", n)
+	}
+}
+
+func TestAddress(t *testing.T) {
+	tests := []struct {
+		addr string
+		exp  string
+		enc  []byte
+	}{
+		{"+46708251358", "+46708251358", []byte{0x0b, 0x91, 0x64, 0x07, 0x28, 0x15, 0x53, 0xf8}},
+		{"1234", "1234", []byte{0x04, 0x81, 0x21, 0x43}},
+		{"+1 (555) 010-0999", "+15550100999", []byte{0x0b, 0x91, 0x51, 0x55, 0x10, 0x00, 0x99, 0xf9}},
+		{"*100#", "*100#", []byte{0x05, 0x81, 0x1a, 0x00, 0xfb}},
+	}
+	for _, test := range tests {
+		b, err := encodeAddress(test.addr)
+		if err != nil {
+			t.Fatalf("%q: expected no error, got: %v", test.addr, err)
+		}
+		if !bytes.Equal(b, test.enc) {
+			t.Errorf("%q: expected % x, got: % x", test.addr, test.enc, b)
+		}
+		if s := decodeSemiOctets(b[2:], b[1]); s != test.exp {
+			t.Errorf("%q: expected %q, got: %q", test.addr, test.exp, s)
+		}
+	}
+	for _, addr := range []string{"", "+", "abc", "123456789012345678901"} {
+		if _, err := encodeAddress(addr); err != ErrInvalidAddress {
+			t.Errorf("%q: expected ErrInvalidAddress, got: %v", addr, err)
+		}
+	}
+}
+
+func TestDeliverRoundTrip(t *testing.T) {
+	// concatenated sms header: reference 0x42, part 2 of 3
+	concat := []byte{0x00, 0x03, 0x42, 0x03, 0x02}
+	tests := []struct {
+		name string
+		s    Submit
+	}{
+		{"gsm7", Submit{SMSC: "+15550100000", To: "+15550100999", Text: "hello [world] €5"}},
+		{"gsm7 udh", Submit{To: "+15550100999", Text: "part two of the message", UDH: concat}},
+		{"ucs2", Submit{To: "12345", Text: "Привет 😀"}},
+		{"ucs2 udh", Submit{To: "12345", Text: "日本語", UDH: concat}},
+		{"8bit", Submit{To: "12345", Data: []byte{0x00, 0x01, 0xff}}},
+		{"flash", Submit{To: "12345", Text: "flash", Flash: true}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			d, err := Decode(deliver(t, test.s))
+			if err != nil {
+				t.Fatalf("expected no error, got: %v", err)
+			}
+			if d.SMSC != test.s.SMSC || d.From != test.s.To {
+				t.Errorf("expected %q from %q, got: %q from %q", test.s.SMSC, test.s.To, d.SMSC, d.From)
+			}
+			if d.Text != test.s.Text || !bytes.Equal(d.Data, test.s.Data) {
+				t.Errorf("expected %q/% x, got: %q/% x", test.s.Text, test.s.Data, d.Text, d.Data)
+			}
+			if !bytes.Equal(d.UDH, test.s.UDH) {
+				t.Errorf("expected udh % x, got: % x", test.s.UDH, d.UDH)
+			}
+			if d.Flash() != test.s.Flash {
+				t.Errorf("expected flash %t, got: %t", test.s.Flash, d.Flash())
+			}
+			if exp := time.Date(2024, 1, 2, 3, 4, 5, 0, time.FixedZone("", 2*3600)); !d.Timestamp.Equal(exp) {
+				t.Errorf("expected timestamp %v, got: %v", exp, d.Timestamp)
+			}
+		})
+	}
+}
+
+// deliver encodes the submit message as a SMS-DELIVER PDU, as received from
+// the submit's destination address.
+func deliver(t *testing.T, s Submit) []byte {
+	t.Helper()
+	buf, n, err := s.Encode()
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	smsc, tpdu := buf[:len(buf)-n], buf[len(buf)-n:]
+	// first octet, keeping the udh indicator
+	b := append(append([]byte{}, smsc...), tpdu[0]&0x40)
+	// originating address (destination address of the submit)
+	l := 2 + (int(tpdu[2])+1)/2
+	b = append(b, tpdu[2:2+l]...)
+	// pid and dcs
+	b = append(b, tpdu[2+l], tpdu[3+l])
+	// timestamp: 2024-01-02 03:04:05 +02:00 (8 quarter hours)
+	b = append(b, 0x42, 0x10, 0x20, 0x30, 0x40, 0x50, 0x80)
+	// user data length and user data
+	return append(b, tpdu[4+l:]...)
+}
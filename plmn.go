@@ -0,0 +1,87 @@
+package hilink
+
+import "strings"
+
+// Plmn is a public land mobile network identifier (ie, a MCC and MNC pair).
+type Plmn struct {
+	MCC string
+	MNC string
+}
+
+// ParsePlmn parses a numeric PLMN (ie, "25001") into its MCC and MNC.
+func ParsePlmn(numeric string) (Plmn, error) {
+	numeric = strings.TrimSpace(numeric)
+	if len(numeric) < 5 || len(numeric) > 6 {
+		return Plmn{}, ErrInvalidValue
+	}
+	for _, c := range numeric {
+		if c < '0' || c > '9' {
+			return Plmn{}, ErrInvalidValue
+		}
+	}
+	return Plmn{
+		MCC: numeric[:3],
+		MNC: numeric[3:],
+	}, nil
+}
+
+// String satisfies the fmt.Stringer interface.
+func (p Plmn) String() string {
+	return p.MCC + p.MNC
+}
+
+// OperatorName returns the operator name for the PLMN from the built-in
+// fallback table, or an empty string if the PLMN is not known.
+func (p Plmn) OperatorName() string {
+	return plmnOperators[p.String()]
+}
+
+// plmnOperators is a small fallback table of operator names, used for
+// devices that only report the numeric PLMN.
+//
+// see: https://en.wikipedia.org/wiki/Mobile_country_code
+var plmnOperators = map[string]string{
+	// Germany
+	"26201": "Telekom.de",
+	"26202": "Vodafone.de",
+	"26203": "o2 - de",
+	// France
+	"20801": "Orange F",
+	"20810": "SFR",
+	"20815": "Free",
+	"20820": "Bouygues Telecom",
+	// Italy
+	"22201": "TIM",
+	"22210": "Vodafone IT",
+	"22288": "WINDTRE",
+	// Spain
+	"21401": "Vodafone ES",
+	"21403": "Orange",
+	"21407": "Movistar",
+	// United Kingdom
+	"23410": "O2 - UK",
+	"23415": "Vodafone UK",
+	"23420": "3 UK",
+	"23430": "EE",
+	// Poland
+	"26001": "Plus",
+	"26002": "T-Mobile.pl",
+	"26003": "Orange",
+	"26006": "Play",
+	// Russia
+	"25001": "MTS RUS",
+	"25002": "MegaFon",
+	"25099": "Beeline",
+	// United States
+	"310260": "T-Mobile",
+	"310410": "AT&T",
+	"311480": "Verizon",
+	// Indonesia
+	"51001": "Indosat Ooredoo",
+	"51010": "Telkomsel",
+	"51011": "XL Axiata",
+	// China
+	"46000": "China Mobile",
+	"46001": "China Unicom",
+	"46011": "China Telecom",
+}
@@ -0,0 +1,180 @@
+package hilink
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestProfilePaths(t *testing.T) {
+	var mu sync.Mutex
+	var paths []string
+	var posted string
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		paths = append(paths, req.Method+" "+req.URL.Path)
+		switch req.Method + " " + req.URL.Path {
+		case "GET /api/device/basic_information":
+			_, _ = w.Write([]byte(`<response><devicename>E3131</devicename><SoftwareVersion>22.521.23.00.00</SoftwareVersion></response>`))
+		case "GET /api/net/network":
+			_, _ = w.Write([]byte(`<response><NetworkMode>00</NetworkMode><NetworkBand>3FFFFFFF</NetworkBand></response>`))
+		case "POST /api/net/network":
+			buf, _ := ioutil.ReadAll(req.Body)
+			posted = string(buf)
+			_, _ = w.Write([]byte(`<response>OK</response>`))
+		default:
+			_, _ = w.Write([]byte(`<error><code>100002</code><message></message></error>`))
+		}
+	}))
+	defer s.Close()
+	cl := NewClient(WithURL(s.URL), WithAutoProfile())
+	ctx := context.Background()
+	p, err := cl.DeviceProfile(ctx)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if p.Name != "stick-3g" {
+		t.Fatalf("expected profile stick-3g, got: %s", p.Name)
+	}
+	res, err := cl.ModeInfo(ctx)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if s := xmlStr(res, "NetworkBand"); s != "3FFFFFFF" {
+		t.Errorf("expected NetworkBand 3FFFFFFF, got: %q", s)
+	}
+	ok, err := cl.NetworkModeSet(ctx, NetworkMode("02"))
+	if err != nil || !ok {
+		t.Fatalf("expected ok, got: %t %v", ok, err)
+	}
+	if !strings.Contains(posted, "<NetworkMode>02</NetworkMode>") || strings.Contains(posted, "LTEBand") {
+		t.Errorf("unexpected request: %s", posted)
+	}
+	if _, err := cl.ModeList(ctx); !errors.Is(err, ErrNotSupported) {
+		t.Errorf("expected ErrNotSupported, got: %v", err)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	exp := []string{
+		"GET /api/device/basic_information",
+		"GET /api/net/network",
+		"GET /api/net/network",
+		"POST /api/net/network",
+	}
+	if strings.Join(paths, "\n") != strings.Join(exp, "\n") {
+		t.Errorf("expected requests:\n%s\ngot:\n%s", strings.Join(exp, "\n"), strings.Join(paths, "\n"))
+	}
+}
+
+func TestLookupDeviceProfile(t *testing.T) {
+	tests := []struct {
+		model string
+		exp   string
+	}{
+		{"E3131", "stick-3g"},
+		{"e3372h-153", "stick"},
+		{"E5577Cs-321", "hotspot"},
+		{"B310s-22", "legacy-router"},
+		{"B818-263", "default"},
+		{"", "default"},
+	}
+	for _, test := range tests {
+		if p := LookupDeviceProfile(test.model); p.Name != test.exp {
+			t.Errorf("%q expected %s, got: %s", test.model, test.exp, p.Name)
+		}
+	}
+}
+
+func TestProfileDetectNotSupported(t *testing.T) {
+	var mu sync.Mutex
+	var paths []string
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		paths = append(paths, req.Method+" "+req.URL.Path)
+		switch req.Method + " " + req.URL.Path {
+		case "GET /api/device/basic_information":
+			_, _ = w.Write([]byte(`<response><devicename>E3131</devicename></response>`))
+		case "GET /api/net/network":
+			_, _ = w.Write([]byte(`<response><NetworkMode>00</NetworkMode><NetworkBand>3FFFFFFF</NetworkBand></response>`))
+		default:
+			_, _ = w.Write([]byte(`<error><code>100002</code><message></message></error>`))
+		}
+	}))
+	defer s.Close()
+	cl := NewClient(WithURL(s.URL))
+	ctx := context.Background()
+	res, err := cl.ModeInfo(ctx)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if s := xmlStr(res, "NetworkBand"); s != "3FFFFFFF" {
+		t.Errorf("expected NetworkBand 3FFFFFFF, got: %q", s)
+	}
+	if _, err := cl.ModeInfo(ctx); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if _, err := cl.ModeList(ctx); !errors.Is(err, ErrNotSupported) {
+		t.Errorf("expected ErrNotSupported, got: %v", err)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	exp := []string{
+		"GET /api/net/net-mode",
+		"GET /api/device/basic_information",
+		"GET /api/net/network",
+		"GET /api/net/network",
+	}
+	if strings.Join(paths, "\n") != strings.Join(exp, "\n") {
+		t.Errorf("expected requests:\n%s\ngot:\n%s", strings.Join(exp, "\n"), strings.Join(paths, "\n"))
+	}
+}
+
+func TestProfileDetectFailed(t *testing.T) {
+	var mu sync.Mutex
+	var paths []string
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		paths = append(paths, req.Method+" "+req.URL.Path)
+		switch req.Method + " " + req.URL.Path {
+		case "GET /api/device/basic_information", "GET /api/device/information":
+			// login required
+			_, _ = w.Write([]byte(`<error><code>125002</code><message></message></error>`))
+		default:
+			_, _ = w.Write([]byte(`<response><ConnectionStatus>901</ConnectionStatus></response>`))
+		}
+	}))
+	defer s.Close()
+	cl := NewClient(WithURL(s.URL), WithAutoProfile())
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		if _, err := cl.StatusInfo(ctx); err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+	}
+	p, err := cl.DeviceProfile(ctx)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if p.Name != "default" {
+		t.Errorf("expected profile default, got: %s", p.Name)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	exp := []string{
+		"GET /api/device/basic_information",
+		"GET /api/device/information",
+		"GET /api/monitoring/status",
+		"GET /api/monitoring/status",
+	}
+	if strings.Join(paths, "\n") != strings.Join(exp, "\n") {
+		t.Errorf("expected requests:\n%s\ngot:\n%s", strings.Join(exp, "\n"), strings.Join(paths, "\n"))
+	}
+}
@@ -0,0 +1,61 @@
+package hilink
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// limiter is a token bucket rate limiter.
+type limiter struct {
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+	sync.Mutex
+}
+
+// newLimiter creates a rate limiter allowing r requests per second, with
+// bursts of up to burst requests.
+func newLimiter(r float64, burst int) *limiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &limiter{
+		rate:   r,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// wait waits until a request is allowed, or the context is closed.
+func (l *limiter) wait(ctx context.Context) error {
+	l.Lock()
+	now := time.Now()
+	l.tokens += now.Sub(l.last).Seconds() * l.rate
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+	l.last = now
+	// reserve a token, waiting for it when not available
+	l.tokens--
+	if l.tokens >= 0 {
+		l.Unlock()
+		return nil
+	}
+	d := time.Duration(-l.tokens / l.rate * float64(time.Second))
+	l.Unlock()
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		// return the reserved token
+		l.Lock()
+		l.tokens++
+		l.Unlock()
+		return ctx.Err()
+	case <-t.C:
+	}
+	return nil
+}
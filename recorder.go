@@ -0,0 +1,202 @@
+package hilink
+
+import (
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// TrafficSample is a traffic sample recorded by a TrafficRecorder.
+type TrafficSample struct {
+	Time time.Time
+	// Upload and Download are the bytes transferred since the previous
+	// sample.
+	Upload   uint64
+	Download uint64
+	// TotalUpload and TotalDownload are the device's traffic counters.
+	TotalUpload   uint64
+	TotalDownload uint64
+	// Reset is true when the device's counters were reset since the previous
+	// sample (ie, after a reboot or TrafficClear).
+	Reset bool
+}
+
+// TrafficSink is the interface for traffic sample storage.
+type TrafficSink interface {
+	WriteSample(context.Context, TrafficSample) error
+}
+
+// csvTrafficSink writes traffic samples as CSV rows.
+type csvTrafficSink struct {
+	w *csv.Writer
+}
+
+// NewCSVTrafficSink creates a traffic sink writing CSV rows to w, writing a
+// header row first when header is true (ie, when creating a new file).
+func NewCSVTrafficSink(w io.Writer, header bool) (TrafficSink, error) {
+	s := &csvTrafficSink{w: csv.NewWriter(w)}
+	if header {
+		if err := s.write([]string{"time", "upload", "download", "total_upload", "total_download", "reset"}); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// WriteSample satisfies the TrafficSink interface.
+func (s *csvTrafficSink) WriteSample(_ context.Context, sample TrafficSample) error {
+	return s.write([]string{
+		sample.Time.Format(time.RFC3339),
+		strconv.FormatUint(sample.Upload, 10),
+		strconv.FormatUint(sample.Download, 10),
+		strconv.FormatUint(sample.TotalUpload, 10),
+		strconv.FormatUint(sample.TotalDownload, 10),
+		boolToString(sample.Reset),
+	})
+}
+
+// write writes and flushes a row.
+func (s *csvTrafficSink) write(row []string) error {
+	if err := s.w.Write(row); err != nil {
+		return err
+	}
+	s.w.Flush()
+	return s.w.Error()
+}
+
+// sqlTrafficSink writes traffic samples as rows in a SQL table.
+type sqlTrafficSink struct {
+	db    *sql.DB
+	table string
+}
+
+// sqlIdentRE matches valid SQL table names.
+var sqlIdentRE = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// NewSQLTrafficSink creates a traffic sink inserting rows into the table in
+// db, creating the table if it does not exist. The queries use ? placeholders
+// and portable column types, and are intended for use with SQLite, using any
+// database/sql driver.
+func NewSQLTrafficSink(ctx context.Context, db *sql.DB, table string) (TrafficSink, error) {
+	if !sqlIdentRE.MatchString(table) {
+		return nil, ErrInvalidValue
+	}
+	if _, err := db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS `+table+` (
+  time TEXT NOT NULL,
+  upload INTEGER NOT NULL,
+  download INTEGER NOT NULL,
+  total_upload INTEGER NOT NULL,
+  total_download INTEGER NOT NULL,
+  reset INTEGER NOT NULL
+)`); err != nil {
+		return nil, err
+	}
+	return &sqlTrafficSink{db: db, table: table}, nil
+}
+
+// WriteSample satisfies the TrafficSink interface.
+func (s *sqlTrafficSink) WriteSample(ctx context.Context, sample TrafficSample) error {
+	reset := 0
+	if sample.Reset {
+		reset = 1
+	}
+	_, err := s.db.ExecContext(
+		ctx,
+		`INSERT INTO `+s.table+` (time, upload, download, total_upload, total_download, reset) VALUES (?, ?, ?, ?, ?, ?)`,
+		sample.Time.UTC().Format(time.RFC3339),
+		int64(sample.Upload),
+		int64(sample.Download),
+		int64(sample.TotalUpload),
+		int64(sample.TotalDownload),
+		reset,
+	)
+	return err
+}
+
+// TrafficRecorder periodically samples the device's traffic statistics,
+// writing the traffic transferred between samples to a sink.
+//
+// Because the per sample values are calculated from the difference between
+// consecutive samples, with counter resets detected and accounted for, the
+// recorded values can be summed to reconstruct usage over any period (ie,
+// daily), even across device reboots.
+type TrafficRecorder struct {
+	cl       *Client
+	sink     TrafficSink
+	interval time.Duration
+	last     *Traffic
+}
+
+// NewTrafficRecorder creates a new traffic recorder.
+func NewTrafficRecorder(cl *Client, sink TrafficSink, interval time.Duration) *TrafficRecorder {
+	return &TrafficRecorder{
+		cl:       cl,
+		sink:     sink,
+		interval: interval,
+	}
+}
+
+// Sample samples the device's traffic statistics, writing the sample to the
+// sink. The first sample recorded has zero Upload and Download values.
+func (r *TrafficRecorder) Sample(ctx context.Context) (*TrafficSample, error) {
+	sample, _, err := r.sample(ctx)
+	return sample, err
+}
+
+// sample samples the device's traffic statistics, writing the sample to the
+// sink. Returns true when the error was writing to the sink.
+func (r *TrafficRecorder) sample(ctx context.Context) (*TrafficSample, bool, error) {
+	t, err := r.cl.Traffic(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+	sample := TrafficSample{
+		Time:          time.Now(),
+		TotalUpload:   t.TotalUpload,
+		TotalDownload: t.TotalDownload,
+	}
+	if r.last != nil {
+		// counters going backwards means they were reset, in which case the
+		// traffic since the reset is the counter value
+		sample.Reset = t.TotalUpload < r.last.TotalUpload || t.TotalDownload < r.last.TotalDownload
+		sample.Upload, sample.Download = t.TotalUpload, t.TotalDownload
+		if !sample.Reset {
+			sample.Upload -= r.last.TotalUpload
+			sample.Download -= r.last.TotalDownload
+		}
+	}
+	if err := r.sink.WriteSample(ctx, sample); err != nil {
+		return nil, true, fmt.Errorf("unable to write sample: %w", err)
+	}
+	r.last = t
+	return &sample, false, nil
+}
+
+// Run samples the device's traffic statistics every interval until ctx is
+// done, or until a sample cannot be written to the sink. Failures retrieving
+// the statistics (ie, while the device is rebooting) start a new session, and
+// are otherwise ignored.
+func (r *TrafficRecorder) Run(ctx context.Context) error {
+	t := time.NewTicker(r.interval)
+	defer t.Stop()
+	for {
+		switch _, sinkErr, err := r.sample(ctx); {
+		case err != nil && ctx.Err() != nil:
+			return ctx.Err()
+		case err != nil && sinkErr:
+			return err
+		case err != nil:
+			_ = r.cl.Relogin(ctx)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-t.C:
+		}
+	}
+}
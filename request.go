@@ -0,0 +1,333 @@
+package hilink
+
+import (
+	"bytes"
+	"encoding"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Request is an ordered XML request builder.
+//
+// WebUI on Hilink devices expects parameters in a specific order. This makes
+// packages like mxj or other map based solutions not feasible for use, as Go
+// has random key ordering for maps. Request preserves the order in which
+// elements are added, supports nested elements, and escapes element values.
+//
+// A Request can be passed as the request value to Do, for example:
+//
+//	req := hilink.NewRequest().
+//		Add("Index", "-1").
+//		AddNested("Phones", hilink.NewRequest("Phone", "+10005550100"))
+//	res, err := cl.Do(ctx, "api/sms/send-sms", req)
+type Request struct {
+	elems []requestElem
+}
+
+// requestElem is a request element.
+type requestElem struct {
+	name   string
+	value  string
+	nested *Request
+}
+
+// NewRequest creates a new request, adding the provided name/value pairs.
+func NewRequest(vals ...string) *Request {
+	// make sure we have pairs
+	if len(vals)%2 != 0 {
+		panic(fmt.Errorf("NewRequest can only accept pairs of strings, length: %d", len(vals)))
+	}
+	r := new(Request)
+	for i := 0; i < len(vals); i += 2 {
+		r.Add(vals[i], vals[i+1])
+	}
+	return r
+}
+
+// Add adds an element with the value.
+func (r *Request) Add(name, value string) *Request {
+	r.elems = append(r.elems, requestElem{
+		name:  name,
+		value: value,
+	})
+	return r
+}
+
+// AddNested adds an element containing the elements of the nested request.
+func (r *Request) AddNested(name string, nested *Request) *Request {
+	if nested == nil {
+		nested = new(Request)
+	}
+	r.elems = append(r.elems, requestElem{
+		name:   name,
+		nested: nested,
+	})
+	return r
+}
+
+// Bytes returns the encoded XML request.
+func (r *Request) Bytes() []byte {
+	var buf bytes.Buffer
+	// write header
+	buf.WriteString(`<?xml version="1.0" encoding="UTF-8"?>`)
+	buf.WriteString("\n<request>\n")
+	// add elements
+	r.write(&buf, "  ")
+	// end string
+	buf.WriteString("</request>\n")
+	return buf.Bytes()
+}
+
+// String satisfies the fmt.Stringer interface.
+func (r *Request) String() string {
+	return string(r.Bytes())
+}
+
+// write writes the request elements to buf.
+func (r *Request) write(buf *bytes.Buffer, indent string) {
+	for _, e := range r.elems {
+		switch {
+		case e.nested == nil:
+			buf.WriteString(indent + "<" + e.name + ">")
+			buf.WriteString(requestEscaper.Replace(e.value))
+			buf.WriteString("</" + e.name + ">\n")
+		case len(e.nested.elems) == 0:
+			buf.WriteString(indent + "<" + e.name + "></" + e.name + ">\n")
+		default:
+			buf.WriteString(indent + "<" + e.name + ">\n")
+			e.nested.write(buf, indent+"  ")
+			buf.WriteString(indent + "</" + e.name + ">\n")
+		}
+	}
+}
+
+// requestEscaper escapes request values, the same as the WebUI.
+var requestEscaper = strings.NewReplacer(
+	"&", "&amp;",
+	"<", "&lt;",
+	">", "&gt;",
+	`"`, "&quot;",
+	"'", "&apos;",
+)
+
+// requestFromMap builds a request from a map of XML data, with the elements
+// sorted by name. Nested maps are added as nested elements, and lists are
+// added as repeated elements.
+func requestFromMap(m map[string]interface{}) *Request {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	r := NewRequest()
+	for _, k := range keys {
+		r.addMapValue(k, m[k])
+	}
+	return r
+}
+
+// addMapValue adds a map of XML data value as one or more elements named
+// name.
+func (r *Request) addMapValue(name string, v interface{}) {
+	switch x := v.(type) {
+	case map[string]interface{}:
+		r.AddNested(name, requestFromMap(x))
+	case XMLData:
+		r.AddNested(name, requestFromMap(x))
+	case []interface{}:
+		for _, z := range x {
+			r.addMapValue(name, z)
+		}
+	case nil:
+		r.Add(name, "")
+	default:
+		r.Add(name, fmt.Sprintf("%v", x))
+	}
+}
+
+// requestItems builds a request of list items (ie, <Server>...</Server>),
+// where each item is a list of ordered name/value pairs, for use as the value
+// of a nested list element in a request.
+func requestItems(itemEl string, items ...[]string) *Request {
+	r := NewRequest()
+	for _, vals := range items {
+		r.AddNested(itemEl, NewRequest(vals...))
+	}
+	return r
+}
+
+// MarshalRequest encodes a struct (or pointer to a struct) as an ordered
+// request, so that requests can be built from typed request structs instead
+// of name/value pairs. Structs passed to Do are encoded using MarshalRequest.
+//
+// Each exported field is encoded as an element, in field order. The element
+// name and options can be changed with the hilink struct tag:
+//
+//	// element named Name, encoded first
+//	Name string `hilink:"Name,order=1"`
+//	// list of <Phone> elements, wrapped in a <Phones> element
+//	To []string `hilink:"Phones>Phone"`
+//	// omitted when the zero value
+//	SaveType int `hilink:",omitempty"`
+//	// element named Index, encoded second
+//	Index int `hilink:"order=2"`
+//	// skipped
+//	Internal string `hilink:"-"`
+//
+// Options can be given without a name, in which case the field name is used
+// as the element name. When an explicit order is given, fields are sorted by order, with fields
+// without an order using their field index as their order.
+//
+// Values implementing encoding.TextMarshaler are encoded as text, bools are
+// encoded as 1 or 0, nested structs are encoded as nested elements, and
+// slices are encoded as repeated elements.
+func MarshalRequest(v interface{}) (*Request, error) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return nil, ErrInvalidValue
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("cannot marshal %T as a request", v)
+	}
+	r := NewRequest()
+	if err := r.addStruct(rv); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// requestField is a marshaled request struct field.
+type requestField struct {
+	index     int
+	name      string
+	item      string
+	order     int
+	omitEmpty bool
+}
+
+// requestFields returns the ordered request fields for the struct type.
+func requestFields(typ reflect.Type) ([]requestField, error) {
+	var fields []requestField
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		tag := f.Tag.Get("hilink")
+		if f.PkgPath != "" || tag == "-" {
+			continue
+		}
+		field := requestField{
+			index: i,
+			name:  f.Name,
+			order: i,
+		}
+		// the name is the first option, when not a key=value option
+		opts := strings.Split(tag, ",")
+		if !strings.Contains(opts[0], "=") && opts[0] != "omitempty" {
+			if opts[0] != "" {
+				field.name = opts[0]
+			}
+			opts = opts[1:]
+		}
+		if j := strings.Index(field.name, ">"); j != -1 {
+			field.name, field.item = field.name[:j], field.name[j+1:]
+		}
+		for _, opt := range opts {
+			switch {
+			case opt == "omitempty":
+				field.omitEmpty = true
+			case strings.HasPrefix(opt, "order="):
+				n, err := strconv.Atoi(strings.TrimPrefix(opt, "order="))
+				if err != nil {
+					return nil, fmt.Errorf("field %s has invalid order %q", f.Name, opt)
+				}
+				field.order = n
+			default:
+				return nil, fmt.Errorf("field %s has unknown option %q", f.Name, opt)
+			}
+		}
+		fields = append(fields, field)
+	}
+	sort.SliceStable(fields, func(i, j int) bool {
+		return fields[i].order < fields[j].order
+	})
+	return fields, nil
+}
+
+// addStruct adds the fields of the struct value.
+func (r *Request) addStruct(rv reflect.Value) error {
+	fields, err := requestFields(rv.Type())
+	if err != nil {
+		return err
+	}
+	for _, f := range fields {
+		fv := rv.Field(f.index)
+		if f.omitEmpty && fv.IsZero() {
+			continue
+		}
+		if f.item == "" {
+			if err := r.addValue(f.name, fv); err != nil {
+				return err
+			}
+			continue
+		}
+		// wrapped list
+		nested := NewRequest()
+		if err := nested.addValue(f.item, fv); err != nil {
+			return err
+		}
+		r.AddNested(f.name, nested)
+	}
+	return nil
+}
+
+// addValue adds the value as one or more elements named name.
+func (r *Request) addValue(name string, rv reflect.Value) error {
+	if m, ok := rv.Interface().(encoding.TextMarshaler); ok {
+		if rv.Kind() == reflect.Ptr && rv.IsNil() {
+			r.Add(name, "")
+			return nil
+		}
+		buf, err := m.MarshalText()
+		if err != nil {
+			return err
+		}
+		r.Add(name, string(buf))
+		return nil
+	}
+	switch rv.Kind() {
+	case reflect.String:
+		r.Add(name, rv.String())
+	case reflect.Bool:
+		r.Add(name, boolToString(rv.Bool()))
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		r.Add(name, strconv.FormatInt(rv.Int(), 10))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		r.Add(name, strconv.FormatUint(rv.Uint(), 10))
+	case reflect.Ptr, reflect.Interface:
+		if rv.IsNil() {
+			r.Add(name, "")
+			return nil
+		}
+		return r.addValue(name, rv.Elem())
+	case reflect.Struct:
+		nested := NewRequest()
+		if err := nested.addStruct(rv); err != nil {
+			return err
+		}
+		r.AddNested(name, nested)
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			if err := r.addValue(name, rv.Index(i)); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("cannot marshal %s as a request value", rv.Type())
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package hilink
+
+import (
+	"testing"
+)
+
+func TestMarshalRequest(t *testing.T) {
+	tests := []struct {
+		name string
+		v    interface{}
+		exp  string
+	}{
+		{
+			"order only",
+			struct {
+				Content string `hilink:"order=3"`
+				Index   int    `hilink:"order=1"`
+				Phone   string `hilink:"order=2"`
+			}{"hello", -1, "+10005550100"},
+			"  <Index>-1</Index>\n  <Phone>+10005550100</Phone>\n  <Content>hello</Content>\n",
+		},
+		{
+			"options in any position",
+			struct {
+				A string `hilink:"omitempty,order=2"`
+				B string `hilink:"order=1,omitempty"`
+				C string `hilink:",order=0"`
+			}{"a", "", "c"},
+			"  <C>c</C>\n  <A>a</A>\n",
+		},
+		{
+			"name and order",
+			struct {
+				Content string   `hilink:"Content,order=2"`
+				To      []string `hilink:"Phones>Phone,order=1"`
+				Skip    string   `hilink:"-"`
+			}{"hello", []string{"1", "2"}, "skip"},
+			"  <Phones>\n    <Phone>1</Phone>\n    <Phone>2</Phone>\n  </Phones>\n  <Content>hello</Content>\n",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r, err := MarshalRequest(test.v)
+			if err != nil {
+				t.Fatalf("expected no error, got: %v", err)
+			}
+			exp := "<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n<request>\n" + test.exp + "</request>\n"
+			if s := r.String(); s != exp {
+				t.Errorf("expected:\n%s\ngot:\n%s", exp, s)
+			}
+		})
+	}
+}
+
+func TestMarshalRequestInvalidOption(t *testing.T) {
+	for _, v := range []interface{}{
+		struct {
+			A string `hilink:"order=x"`
+		}{},
+		struct {
+			A string `hilink:"A,bogus=1"`
+		}{},
+	} {
+		if _, err := MarshalRequest(v); err == nil {
+			t.Errorf("expected error for %T", v)
+		}
+	}
+}
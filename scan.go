@@ -0,0 +1,65 @@
+package hilink
+
+import (
+	"context"
+	"encoding/binary"
+	"net"
+	"sync"
+	"time"
+)
+
+// ScanTimeout is the timeout for probing each address when scanning a
+// network with WithScan.
+const ScanTimeout = 2 * time.Second
+
+// scanConcurrency is the number of addresses probed concurrently.
+const scanConcurrency = 64
+
+// searchScan probes each address of the scanned networks for the HiLink
+// API. Unlike the multicast searches, the scan runs until all addresses are
+// probed or ctx is closed.
+func (d *discoverer) searchScan(ctx context.Context, _ time.Duration) error {
+	addrs := make(chan net.IP)
+	var wg sync.WaitGroup
+	for i := 0; i < scanConcurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for ip := range addrs {
+				if dev, err := probeDevice(ctx, "http://"+ip.String()+"/", ScanTimeout); err == nil {
+					d.add(*dev, DiscoverScan)
+				}
+			}
+		}()
+	}
+	defer wg.Wait()
+	defer close(addrs)
+	for _, ipnet := range d.scan {
+		for _, ip := range hosts(ipnet) {
+			select {
+			case <-ctx.Done():
+				return nil
+			case addrs <- ip:
+			}
+		}
+	}
+	return nil
+}
+
+// hosts returns the host addresses of the IPv4 network, excluding the
+// network and broadcast addresses of networks larger than /31.
+func hosts(ipnet *net.IPNet) []net.IP {
+	ones, bits := ipnet.Mask.Size()
+	start := binary.BigEndian.Uint32(ipnet.IP.To4())
+	n := uint32(1) << uint(bits-ones)
+	if n > 2 {
+		start, n = start+1, n-2
+	}
+	l := make([]net.IP, 0, n)
+	for i := uint32(0); i < n; i++ {
+		ip := make(net.IP, 4)
+		binary.BigEndian.PutUint32(ip, start+i)
+		l = append(l, ip)
+	}
+	return l
+}
@@ -0,0 +1,1079 @@
+package hilink
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/kenshaw/hilink/pdu"
+)
+
+// SMSService groups the SMS (and USSD) methods of a Client.
+type SMSService struct {
+	cl *Client
+}
+
+// SMS returns the SMS (and USSD) methods of the client.
+func (cl *Client) SMS() *SMSService {
+	return &SMSService{cl: cl}
+}
+
+// Send sends an SMS. Messages too long for a single SMS are sent by the
+// device as a concatenated SMS of up to SmsMaxParts parts.
+func (svc *SMSService) Send(ctx context.Context, msg string, to ...string) (bool, error) {
+	return svc.cl.SmsSend(ctx, msg, to...)
+}
+
+// SendOpts sends an SMS using the specified service center address, save
+// location, and priority options.
+func (svc *SMSService) SendOpts(ctx context.Context, opts SmsOptions, msg string, to ...string) (bool, error) {
+	return svc.cl.SmsSendOpts(ctx, opts, msg, to...)
+}
+
+// SendPdu sends a raw (hex encoded) SMS PDU with the specified TPDU
+// length, on devices supporting raw PDU mode.
+func (svc *SMSService) SendPdu(ctx context.Context, pdu string, length uint) (bool, error) {
+	return svc.cl.SmsSendPdu(ctx, pdu, length)
+}
+
+// SendSubmit encodes and sends a SMS-SUBMIT message as a raw PDU, allowing
+// flash messages, custom data coding schemes, and binary payloads to be sent
+// on devices supporting raw PDU mode.
+func (svc *SMSService) SendSubmit(ctx context.Context, s pdu.Submit) (bool, error) {
+	return svc.cl.SmsSendSubmit(ctx, s)
+}
+
+// SendStatus retrieves SMS send status information.
+func (svc *SMSService) SendStatus(ctx context.Context) (XMLData, error) {
+	return svc.cl.SmsSendStatus(ctx)
+}
+
+// List retrieves list of SMS in an inbox.
+func (svc *SMSService) List(ctx context.Context, boxType, page, count uint, sortByName, ascending, unreadPreferred bool) (XMLData, error) {
+	return svc.cl.SmsList(ctx, boxType, page, count, sortByName, ascending, unreadPreferred)
+}
+
+// ListAll retrieves all SMS messages in an inbox, fetching pages of
+// SmsPageSize messages until the count reported by SmsCount is exhausted.
+func (svc *SMSService) ListAll(ctx context.Context, boxType SmsBoxType) ([]SmsMessage, error) {
+	return svc.cl.SmsListAll(ctx, boxType)
+}
+
+// Messages retrieves a page of SMS messages in an inbox.
+func (svc *SMSService) Messages(ctx context.Context, boxType SmsBoxType, page, count uint) ([]SmsMessage, error) {
+	return svc.cl.SmsMessages(ctx, boxType, page, count)
+}
+
+// PduList retrieves list of SMS in an inbox as raw PDUs, on devices
+// supporting raw PDU mode.
+func (svc *SMSService) PduList(ctx context.Context, boxType, page, count uint) (XMLData, error) {
+	return svc.cl.SmsPduList(ctx, boxType, page, count)
+}
+
+// PduMessages retrieves and decodes a page of raw PDU SMS messages in an
+// inbox, on devices supporting raw PDU mode.
+func (svc *SMSService) PduMessages(ctx context.Context, boxType SmsBoxType, page, count uint) ([]*pdu.Deliver, error) {
+	return svc.cl.SmsPduMessages(ctx, boxType, page, count)
+}
+
+// Delete deletes a specified SMS.
+func (svc *SMSService) Delete(ctx context.Context, id uint) (bool, error) {
+	return svc.cl.SmsDelete(ctx, id)
+}
+
+// ReadSet sets the read status of one or more SMS.
+func (svc *SMSService) ReadSet(ctx context.Context, id ...string) (bool, error) {
+	return svc.cl.SmsReadSet(ctx, id...)
+}
+
+// MarkAllRead sets the read status of all unread SMS in an inbox, in
+// batches of SmsPageSize.
+func (svc *SMSService) MarkAllRead(ctx context.Context, boxType SmsBoxType) (bool, error) {
+	return svc.cl.SmsMarkAllRead(ctx, boxType)
+}
+
+// Count retrieves count of SMS per inbox type.
+func (svc *SMSService) Count(ctx context.Context) (XMLData, error) {
+	return svc.cl.SmsCount(ctx)
+}
+
+// Counts retrieves the SMS message counts.
+func (svc *SMSService) Counts(ctx context.Context) (*SmsCounts, error) {
+	return svc.cl.SmsCounts(ctx)
+}
+
+// Config retrieves device SMS configuration.
+func (svc *SMSService) Config(ctx context.Context) (XMLData, error) {
+	return svc.cl.SmsConfig(ctx)
+}
+
+// Features retrieves SMS feature information.
+func (svc *SMSService) Features(ctx context.Context) (XMLData, error) {
+	return svc.cl.SmsFeatures(ctx)
+}
+
+// DeliveryReportSet enables or disables SMS delivery (status) reports.
+func (svc *SMSService) DeliveryReportSet(ctx context.Context, enabled bool) (bool, error) {
+	return svc.cl.SmsDeliveryReportSet(ctx, enabled)
+}
+
+// DeliveryReports retrieves the delivery (status) reports in the first
+// count messages of the inbox, matching each to the most recent outbox
+// message sent to the same phone number prior to the report.
+func (svc *SMSService) DeliveryReports(ctx context.Context, count uint) ([]DeliveryReport, error) {
+	return svc.cl.SmsDeliveryReports(ctx, count)
+}
+
+// Export writes all SMS messages in an inbox to w in the specified format.
+func (svc *SMSService) Export(ctx context.Context, w io.Writer, boxType SmsBoxType, format ExportFormat) error {
+	return svc.cl.SmsExport(ctx, w, boxType, format)
+}
+
+// Watch watches the inbox for new SMS messages, polling the device's unread
+// count every interval. New unread messages are delivered on the returned
+// message channel, and marked as read once received. Errors encountered while
+// polling are delivered on the returned error channel, and do not stop the
+// watch. Both channels are closed when ctx is done.
+func (svc *SMSService) Watch(ctx context.Context, interval time.Duration) (<-chan SmsMessage, <-chan error) {
+	return svc.cl.WatchSms(ctx, interval)
+}
+
+// WatchFunc watches the inbox for new SMS messages, calling f with each new
+// unread message, and only marking it as read after f returns nil (see
+// Client.WatchSmsFunc).
+func (svc *SMSService) WatchFunc(ctx context.Context, interval time.Duration, f func(context.Context, SmsMessage) error, errf func(error)) error {
+	return svc.cl.WatchSmsFunc(ctx, interval, f, errf)
+}
+
+// UssdStatus retrieves current USSD session status information.
+func (svc *SMSService) UssdStatus(ctx context.Context) (UssdState, error) {
+	return svc.cl.UssdStatus(ctx)
+}
+
+// UssdCode sends a USSD code to the Hilink device.
+func (svc *SMSService) UssdCode(ctx context.Context, code string) (bool, error) {
+	return svc.cl.UssdCode(ctx, code)
+}
+
+// UssdCodeOpts sends a USSD code to the Hilink device using the specified
+// code type and timeout. When the code type is raw, the code is sent as hex
+// encoded, packed GSM-7. A zero timeout uses the device's default timeout.
+func (svc *SMSService) UssdCodeOpts(ctx context.Context, code string, codeType UssdCodeType, timeout time.Duration) (bool, error) {
+	return svc.cl.UssdCodeOpts(ctx, code, codeType, timeout)
+}
+
+// UssdContent retrieves content buffer of the active USSD session.
+func (svc *SMSService) UssdContent(ctx context.Context) (string, error) {
+	return svc.cl.UssdContent(ctx)
+}
+
+// UssdContentOpts retrieves content buffer of the active USSD session, for a
+// code sent using the specified code type. When the code type is raw, the
+// content returned hex encoded (as by some operators) is decoded as packed
+// GSM-7 or UCS-2.
+func (svc *SMSService) UssdContentOpts(ctx context.Context, codeType UssdCodeType) (string, error) {
+	return svc.cl.UssdContentOpts(ctx, codeType)
+}
+
+// UssdRelease releases the active USSD session.
+func (svc *SMSService) UssdRelease(ctx context.Context) (bool, error) {
+	return svc.cl.UssdRelease(ctx)
+}
+
+// UssdReply sends a reply to a prompt of the active USSD session (ie, a menu
+// selection).
+func (svc *SMSService) UssdReply(ctx context.Context, text string) (bool, error) {
+	return svc.cl.UssdReply(ctx, text)
+}
+
+// UssdRun runs a USSD script, returning the content returned for each of the
+// executed steps. The USSD session is released after the script completes or
+// fails.
+func (svc *SMSService) UssdRun(ctx context.Context, script UssdScript) ([]string, error) {
+	return svc.cl.UssdRun(ctx, script)
+}
+
+// UssdSendAndWait sends a USSD code and waits until the USSD status is no
+// longer waiting (or ctx is done), returning the USSD content.
+func (svc *SMSService) UssdSendAndWait(ctx context.Context, code string) (string, error) {
+	return svc.cl.UssdSendAndWait(ctx, code)
+}
+
+// UssdSendAndWaitOpts sends a USSD code using the specified code type, and
+// waits until the USSD status is no longer waiting (or ctx is done),
+// returning the USSD content (see UssdContentOpts).
+func (svc *SMSService) UssdSendAndWaitOpts(ctx context.Context, code string, codeType UssdCodeType) (string, error) {
+	return svc.cl.UssdSendAndWaitOpts(ctx, code, codeType)
+}
+
+// UssdStart starts an interactive USSD session by sending the USSD code,
+// returning the session and the content (ie, menu) sent by the network.
+func (svc *SMSService) UssdStart(ctx context.Context, code string) (*UssdSession, string, error) {
+	return svc.cl.UssdStart(ctx, code)
+}
+
+// UssdStartOpts starts an interactive USSD session by sending the USSD code
+// using the specified code type, which is also used for the session's
+// replies, returning the session and the content (ie, menu) sent by the
+// network.
+func (svc *SMSService) UssdStartOpts(ctx context.Context, code string, codeType UssdCodeType) (*UssdSession, string, error) {
+	return svc.cl.UssdStartOpts(ctx, code, codeType)
+}
+
+// NetService groups the mobile network, connection, and LAN methods of a Client.
+type NetService struct {
+	cl *Client
+}
+
+// Net returns the mobile network, connection, and LAN methods of the client.
+func (cl *Client) Net() *NetService {
+	return &NetService{cl: cl}
+}
+
+// Connect connects the Hilink device to the network provider.
+func (svc *NetService) Connect(ctx context.Context) (bool, error) {
+	return svc.cl.Connect(ctx)
+}
+
+// Disconnect disconnects the Hilink device from the network provider.
+func (svc *NetService) Disconnect(ctx context.Context) (bool, error) {
+	return svc.cl.Disconnect(ctx)
+}
+
+// ConnectionInfo retrieves connection (dialup) information.
+func (svc *NetService) ConnectionInfo(ctx context.Context) (XMLData, error) {
+	return svc.cl.ConnectionInfo(ctx)
+}
+
+// ConnectionSettings retrieves the dialup connection settings.
+func (svc *NetService) ConnectionSettings(ctx context.Context) (*ConnectionSettings, error) {
+	return svc.cl.ConnectionSettings(ctx)
+}
+
+// ConnectionSettingsSet sets the dialup connection settings.
+func (svc *NetService) ConnectionSettingsSet(ctx context.Context, s ConnectionSettings) (bool, error) {
+	return svc.cl.ConnectionSettingsSet(ctx, s)
+}
+
+// MobileDataEnabled retrieves whether mobile data is enabled.
+func (svc *NetService) MobileDataEnabled(ctx context.Context) (bool, error) {
+	return svc.cl.MobileDataEnabled(ctx)
+}
+
+// MobileDataSet enables or disables mobile data. On many newer devices, this
+// should be used instead of Connect/Disconnect.
+func (svc *NetService) MobileDataSet(ctx context.Context, enabled bool) (bool, error) {
+	return svc.cl.MobileDataSet(ctx, enabled)
+}
+
+// Status retrieves the general device status.
+func (svc *NetService) Status(ctx context.Context) (*Status, error) {
+	return svc.cl.Status(ctx)
+}
+
+// StatusInfo retrieves general device status information.
+func (svc *NetService) StatusInfo(ctx context.Context) (XMLData, error) {
+	return svc.cl.StatusInfo(ctx)
+}
+
+// ServingCell retrieves the extended serving cell information, including any
+// carrier aggregation secondary cells reported by the device (as scc1_band,
+// scc1_pci, ...).
+func (svc *NetService) ServingCell(ctx context.Context) (*ServingCell, error) {
+	return svc.cl.ServingCell(ctx)
+}
+
+// NeighborCells retrieves the list of neighbor cells, where supported by the
+// device.
+func (svc *NetService) NeighborCells(ctx context.Context) ([]NeighborCell, error) {
+	return svc.cl.NeighborCells(ctx)
+}
+
+// SignalInfo retrieves network signal information.
+func (svc *NetService) SignalInfo(ctx context.Context) (XMLData, error) {
+	return svc.cl.SignalInfo(ctx)
+}
+
+// Traffic retrieves the traffic statistics.
+func (svc *NetService) Traffic(ctx context.Context) (*Traffic, error) {
+	return svc.cl.Traffic(ctx)
+}
+
+// TrafficInfo retrieves traffic statistic information.
+func (svc *NetService) TrafficInfo(ctx context.Context) (XMLData, error) {
+	return svc.cl.TrafficInfo(ctx)
+}
+
+// TrafficClear clears the current traffic statistics.
+func (svc *NetService) TrafficClear(ctx context.Context) (bool, error) {
+	return svc.cl.TrafficClear(ctx)
+}
+
+// SampleThroughput measures the current upload and download rates, by taking
+// two traffic statistics readings window apart (default 1 second). Handles
+// devices with 32-bit counters that roll over.
+func (svc *NetService) SampleThroughput(ctx context.Context, window time.Duration) (*Throughput, error) {
+	return svc.cl.SampleThroughput(ctx, window)
+}
+
+// LanHostInfo retrieves the list of hosts connected to the LAN (ie, by
+// Ethernet), as reported by B-series routers. Depending on the firmware, Wi-Fi
+// hosts are also included.
+func (svc *NetService) LanHostInfo(ctx context.Context) (XMLData, error) {
+	return svc.cl.LanHostInfo(ctx)
+}
+
+// LanHosts retrieves the active hosts connected to the LAN.
+func (svc *NetService) LanHosts(ctx context.Context) ([]Host, error) {
+	return svc.cl.LanHosts(ctx)
+}
+
+// AllHosts retrieves the hosts connected to the LAN and the Wi-Fi, merging
+// hosts reported by both by MAC address. See Client.AllHosts.
+func (svc *NetService) AllHosts(ctx context.Context) ([]Host, error) {
+	return svc.cl.AllHosts(ctx)
+}
+
+// WakeOnLan sends a Wake-on-LAN magic packet to the LAN host with the MAC
+// address, using the Wake-on-LAN function of routers whose firmware supports
+// it (ie, to wake a host behind the router remotely).
+func (svc *NetService) WakeOnLan(ctx context.Context, mac string) (bool, error) {
+	return svc.cl.WakeOnLan(ctx, mac)
+}
+
+// BandwidthLimits retrieves the per-device bandwidth limits, on routers whose
+// firmware supports bandwidth control.
+func (svc *NetService) BandwidthLimits(ctx context.Context) ([]BandwidthLimit, error) {
+	return svc.cl.BandwidthLimits(ctx)
+}
+
+// BandwidthLimitSet sets the upload and download limits (in kbit/s, 0 being
+// unlimited) of the host with the MAC address, replacing any existing limit
+// for the MAC address. Setting both limits to 0 removes the limit.
+func (svc *NetService) BandwidthLimitSet(ctx context.Context, mac string, upload, download uint) (bool, error) {
+	return svc.cl.BandwidthLimitSet(ctx, mac, upload, download)
+}
+
+// BandwidthLimitDelete removes the bandwidth limit of the host with the MAC
+// address.
+func (svc *NetService) BandwidthLimitDelete(ctx context.Context, mac string) (bool, error) {
+	return svc.cl.BandwidthLimitDelete(ctx, mac)
+}
+
+// MonthTraffic retrieves the current month traffic statistics.
+func (svc *NetService) MonthTraffic(ctx context.Context) (*MonthTraffic, error) {
+	return svc.cl.MonthTraffic(ctx)
+}
+
+// MonthInfo retrieves the month download statistic information.
+func (svc *NetService) MonthInfo(ctx context.Context) (XMLData, error) {
+	return svc.cl.MonthInfo(ctx)
+}
+
+// DataPlan retrieves the monthly data plan settings.
+func (svc *NetService) DataPlan(ctx context.Context) (*DataPlan, error) {
+	return svc.cl.DataPlan(ctx)
+}
+
+// DataPlanSet sets the monthly data plan settings.
+func (svc *NetService) DataPlanSet(ctx context.Context, plan DataPlan) (bool, error) {
+	return svc.cl.DataPlanSet(ctx, plan)
+}
+
+// Info retrieves network provider information. When the device only
+// reports the numeric PLMN, the operator name is looked up from a built-in
+// table.
+func (svc *NetService) Info(ctx context.Context) (*NetworkOperator, error) {
+	return svc.cl.NetworkInfo(ctx)
+}
+
+// Types retrieves available network types.
+func (svc *NetService) Types(ctx context.Context) (XMLData, error) {
+	return svc.cl.NetworkTypes(ctx)
+}
+
+// Scan scans for available networks (PLMNs). Note that scanning can
+// take a long time, and uses NetworkScanTimeout as the request timeout,
+// unless overridden with WithCallTimeout.
+func (svc *NetService) Scan(ctx context.Context) ([]Network, error) {
+	return svc.cl.NetworkScan(ctx)
+}
+
+// Register manually registers the device on the network with the
+// specified PLMN (ie, MCC and MNC) and radio access technology. When plmn is
+// empty, automatic network selection is restored.
+func (svc *NetService) Register(ctx context.Context, plmn string, rat Rat) (bool, error) {
+	return svc.cl.NetworkRegister(ctx, plmn, rat)
+}
+
+// NetworkModeSet sets the network mode, retaining the current band settings.
+func (svc *NetService) NetworkModeSet(ctx context.Context, mode NetworkMode) (bool, error) {
+	return svc.cl.NetworkModeSet(ctx, mode)
+}
+
+// ModeInfo retrieves network mode settings information.
+func (svc *NetService) ModeInfo(ctx context.Context) (XMLData, error) {
+	return svc.cl.ModeInfo(ctx)
+}
+
+// ModeList retrieves available network modes.
+func (svc *NetService) ModeList(ctx context.Context) (XMLData, error) {
+	return svc.cl.ModeList(ctx)
+}
+
+// ModeNetworkInfo retrieves current network mode information.
+func (svc *NetService) ModeNetworkInfo(ctx context.Context) (XMLData, error) {
+	return svc.cl.ModeNetworkInfo(ctx)
+}
+
+// ModeSet sets the network mode.
+func (svc *NetService) ModeSet(ctx context.Context, netMode, netBand, lteBand string) (bool, error) {
+	return svc.cl.ModeSet(ctx, netMode, netBand, lteBand)
+}
+
+// NRMode retrieves the NR (5G) SA/NSA mode preference of NR capable devices.
+func (svc *NetService) NRMode(ctx context.Context) (NRMode, error) {
+	return svc.cl.NRMode(ctx)
+}
+
+// NRModeSet sets the NR (5G) SA/NSA mode preference of NR capable devices.
+func (svc *NetService) NRModeSet(ctx context.Context, mode NRMode) (bool, error) {
+	return svc.cl.NRModeSet(ctx, mode)
+}
+
+// LTEBandLock locks the device to the specified LTE bands, retaining the
+// current network mode and network band. When no bands are specified, the
+// lock is cleared (ie, all bands are allowed).
+func (svc *NetService) LTEBandLock(ctx context.Context, bands ...Band) (bool, error) {
+	return svc.cl.LTEBandLock(ctx, bands...)
+}
+
+// NRBandLock locks NR capable devices to the specified NR (5G) bands,
+// retaining the current network mode and other band settings. When no bands
+// are specified, the lock is cleared (ie, all bands are allowed).
+func (svc *NetService) NRBandLock(ctx context.Context, bands ...NRBand) (bool, error) {
+	return svc.cl.NRBandLock(ctx, bands...)
+}
+
+// Profiles retrieves the dialup (APN) profiles.
+func (svc *NetService) Profiles(ctx context.Context) ([]Profile, error) {
+	return svc.cl.Profiles(ctx)
+}
+
+// ProfileInfo retrieves profile information (ie, APN).
+func (svc *NetService) ProfileInfo(ctx context.Context) (XMLData, error) {
+	return svc.cl.ProfileInfo(ctx)
+}
+
+// ProfileCreate creates a dialup (APN) profile. The profile is made the
+// default profile when p.Default is true.
+func (svc *NetService) ProfileCreate(ctx context.Context, p Profile) (bool, error) {
+	return svc.cl.ProfileCreate(ctx, p)
+}
+
+// ProfileUpdate updates the dialup (APN) profile with index p.Index. The
+// profile is made the default profile when p.Default is true.
+func (svc *NetService) ProfileUpdate(ctx context.Context, p Profile) (bool, error) {
+	return svc.cl.ProfileUpdate(ctx, p)
+}
+
+// ProfileDelete deletes the dialup (APN) profile with the specified index.
+func (svc *NetService) ProfileDelete(ctx context.Context, index uint) (bool, error) {
+	return svc.cl.ProfileDelete(ctx, index)
+}
+
+// ProfileSetDefault sets the default dialup (APN) profile.
+func (svc *NetService) ProfileSetDefault(ctx context.Context, index uint) (bool, error) {
+	return svc.cl.ProfileSetDefault(ctx, index)
+}
+
+// IPv6Settings retrieves the IPv6 configuration.
+func (svc *NetService) IPv6Settings(ctx context.Context) (*IPv6Settings, error) {
+	return svc.cl.IPv6Settings(ctx)
+}
+
+// IPv6SettingsSet sets the IPv6 configuration.
+func (svc *NetService) IPv6SettingsSet(ctx context.Context, settings IPv6Settings) (bool, error) {
+	return svc.cl.IPv6SettingsSet(ctx, settings)
+}
+
+// LanSettings retrieves the router LAN (DHCP) settings.
+func (svc *NetService) LanSettings(ctx context.Context) (*LanSettings, error) {
+	return svc.cl.LanSettings(ctx)
+}
+
+// LanSettingsSet sets the router LAN (DHCP) settings, after validating them.
+//
+// Note: changing the router's IP address or subnet will cause the device to
+// restart its LAN interface, and the client will need to be recreated with the
+// new address.
+func (svc *NetService) LanSettingsSet(ctx context.Context, settings LanSettings) (bool, error) {
+	return svc.cl.LanSettingsSet(ctx, settings)
+}
+
+// DhcpConfig retrieves DHCP configuration.
+func (svc *NetService) DhcpConfig(ctx context.Context) (XMLData, error) {
+	return svc.cl.DhcpConfig(ctx)
+}
+
+// StaticLeases retrieves the static DHCP bindings.
+func (svc *NetService) StaticLeases(ctx context.Context) ([]StaticLease, error) {
+	return svc.cl.StaticLeases(ctx)
+}
+
+// StaticLeaseAdd adds a static DHCP binding of the MAC address to the IP
+// address, replacing any existing binding for the MAC address.
+func (svc *NetService) StaticLeaseAdd(ctx context.Context, mac, ip string) (bool, error) {
+	return svc.cl.StaticLeaseAdd(ctx, mac, ip)
+}
+
+// StaticLeaseDelete deletes the static DHCP binding for the MAC address.
+func (svc *NetService) StaticLeaseDelete(ctx context.Context, mac string) (bool, error) {
+	return svc.cl.StaticLeaseDelete(ctx, mac)
+}
+
+// Routes retrieves the static routes.
+func (svc *NetService) Routes(ctx context.Context) ([]Route, error) {
+	return svc.cl.Routes(ctx)
+}
+
+// RouteAdd adds a static route, replacing any existing route with the same
+// destination and mask.
+func (svc *NetService) RouteAdd(ctx context.Context, route Route) (bool, error) {
+	return svc.cl.RouteAdd(ctx, route)
+}
+
+// RouteDelete deletes the static route with the specified destination and
+// mask.
+func (svc *NetService) RouteDelete(ctx context.Context, destination, mask string) (bool, error) {
+	return svc.cl.RouteDelete(ctx, destination, mask)
+}
+
+// DdnsList retrieves list of DDNS providers.
+func (svc *NetService) DdnsList(ctx context.Context) (XMLData, error) {
+	return svc.cl.DdnsList(ctx)
+}
+
+// WlanService groups the Wi-Fi methods of a Client.
+type WlanService struct {
+	cl *Client
+}
+
+// Wlan returns the Wi-Fi methods of the client.
+func (cl *Client) Wlan() *WlanService {
+	return &WlanService{cl: cl}
+}
+
+// Features retrieves wifi feature information.
+func (svc *WlanService) Features(ctx context.Context) (XMLData, error) {
+	return svc.cl.WifiFeatures(ctx)
+}
+
+// Switch turns all of the Wi-Fi radios reported by the device on or off.
+func (svc *WlanService) Switch(ctx context.Context, enabled bool) (bool, error) {
+	return svc.cl.WifiSwitch(ctx, enabled)
+}
+
+// Switch24GHz turns the 2.4 GHz Wi-Fi radio on or off.
+func (svc *WlanService) Switch24GHz(ctx context.Context, enabled bool) (bool, error) {
+	return svc.cl.WifiSwitch24GHz(ctx, enabled)
+}
+
+// Switch5GHz turns the 5 GHz Wi-Fi radio on or off.
+func (svc *WlanService) Switch5GHz(ctx context.Context, enabled bool) (bool, error) {
+	return svc.cl.WifiSwitch5GHz(ctx, enabled)
+}
+
+// SwitchInfo retrieves the on/off status of the Wi-Fi radios.
+func (svc *WlanService) SwitchInfo(ctx context.Context) (XMLData, error) {
+	return svc.cl.WifiSwitchInfo(ctx)
+}
+
+// HostList retrieves the list of hosts connected to the Wi-Fi.
+func (svc *WlanService) HostList(ctx context.Context) (XMLData, error) {
+	return svc.cl.WifiHostList(ctx)
+}
+
+// Hosts retrieves the hosts connected to the Wi-Fi along with their
+// per-station statistics.
+func (svc *WlanService) Hosts(ctx context.Context) ([]WifiHost, error) {
+	return svc.cl.WifiHosts(ctx)
+}
+
+// HostBlock evicts and blocks the Wi-Fi host with the specified MAC
+// address by adding it to the MAC filter deny list (or removing it from the
+// allow list, when the MAC filter is in allow mode).
+func (svc *WlanService) HostBlock(ctx context.Context, mac string) (bool, error) {
+	return svc.cl.WifiHostBlock(ctx, mac)
+}
+
+// HostUnblock removes the Wi-Fi host with the specified MAC address from
+// the MAC filter deny list.
+func (svc *WlanService) HostUnblock(ctx context.Context, mac string) (bool, error) {
+	return svc.cl.WifiHostUnblock(ctx, mac)
+}
+
+// MacFilter retrieves the Wi-Fi MAC filter settings.
+func (svc *WlanService) MacFilter(ctx context.Context) (XMLData, error) {
+	return svc.cl.WifiMacFilter(ctx)
+}
+
+// TimeSwitch retrieves the Wi-Fi on/off schedule.
+func (svc *WlanService) TimeSwitch(ctx context.Context) (*WifiSchedule, error) {
+	return svc.cl.WifiTimeSwitch(ctx)
+}
+
+// TimeSwitchSet sets the Wi-Fi on/off schedule.
+func (svc *WlanService) TimeSwitchSet(ctx context.Context, sched WifiSchedule) (bool, error) {
+	return svc.cl.WifiTimeSwitchSet(ctx, sched)
+}
+
+// Config retrieves basic WLAN settings.
+func (svc *WlanService) Config(ctx context.Context) (XMLData, error) {
+	return svc.cl.WlanConfig(ctx)
+}
+
+// MonthInfo retrieves the WLAN month download statistic information.
+func (svc *WlanService) MonthInfo(ctx context.Context) (XMLData, error) {
+	return svc.cl.WlanMonthInfo(ctx)
+}
+
+// DeviceService groups the device, firmware, and log methods of a Client.
+type DeviceService struct {
+	cl *Client
+}
+
+// Device returns the device, firmware, and log methods of the client.
+func (cl *Client) Device() *DeviceService {
+	return &DeviceService{cl: cl}
+}
+
+// Info retrieves general device information.
+func (svc *DeviceService) Info(ctx context.Context) (XMLData, error) {
+	return svc.cl.DeviceInfo(ctx)
+}
+
+// BasicInfo retrieves basic device information.
+func (svc *DeviceService) BasicInfo(ctx context.Context) (XMLData, error) {
+	return svc.cl.DeviceBasicInfo(ctx)
+}
+
+// Config retrieves device configuration.
+func (svc *DeviceService) Config(ctx context.Context) (XMLData, error) {
+	return svc.cl.DeviceConfig(ctx)
+}
+
+// Features retrieves device feature information.
+func (svc *DeviceService) Features(ctx context.Context) (XMLData, error) {
+	return svc.cl.DeviceFeatures(ctx)
+}
+
+// Control sends a control code to the device.
+func (svc *DeviceService) Control(ctx context.Context, code uint) (bool, error) {
+	return svc.cl.DeviceControl(ctx, code)
+}
+
+// ModeSet sets the device mode (0-project, 1-debug).
+func (svc *DeviceService) ModeSet(ctx context.Context, mode uint) (bool, error) {
+	return svc.cl.DeviceModeSet(ctx, mode)
+}
+
+// Reboot restarts the device.
+func (svc *DeviceService) Reboot(ctx context.Context) (bool, error) {
+	return svc.cl.DeviceReboot(ctx)
+}
+
+// Reset resets the device configuration.
+func (svc *DeviceService) Reset(ctx context.Context) (bool, error) {
+	return svc.cl.DeviceReset(ctx)
+}
+
+// Shutdown shuts down the device.
+func (svc *DeviceService) Shutdown(ctx context.Context) (bool, error) {
+	return svc.cl.DeviceShutdown(ctx)
+}
+
+// Backup backups device configuration and retrieves backed up
+// configuration data as a base64 encoded string.
+func (svc *DeviceService) Backup(ctx context.Context) (string, error) {
+	return svc.cl.DeviceBackup(ctx)
+}
+
+// RebootAndWait restarts the device, and waits until the WebUI answers again,
+// establishing a new session (logging in again when the Auth option was
+// given). The overall deadline is the deadline of ctx, or RebootTimeout when
+// ctx has no deadline.
+func (svc *DeviceService) RebootAndWait(ctx context.Context) (bool, error) {
+	return svc.cl.RebootAndWait(ctx)
+}
+
+// WaitUntilReady waits until the device has finished booting and the SIM is
+// ready (ie, after power-on, or after the SIM PIN has been entered), polling
+// every 2 seconds. Until then, the device may refuse connections, or respond
+// with errors (ie, system not available), which are ignored. Each time the
+// device responds, a new session is started (logging in again when the Auth
+// option was given), and the status is checked. The overall deadline is the
+// deadline of ctx, or RebootTimeout when ctx has no deadline.
+func (svc *DeviceService) WaitUntilReady(ctx context.Context) error {
+	return svc.cl.WaitUntilReady(ctx)
+}
+
+// Capabilities detects the capabilities of the device. Feature information
+// not available on the device (ie, when an endpoint is not supported by the
+// firmware) is treated as the feature not being present.
+func (svc *DeviceService) Capabilities(ctx context.Context) (*Capabilities, error) {
+	return svc.cl.Capabilities(ctx)
+}
+
+// InfoAll concurrently retrieves the device information, status, signal,
+// traffic statistics and network operator, returning a combined snapshot. An
+// error is returned if any of the requests fail.
+func (svc *DeviceService) InfoAll(ctx context.Context) (*Info, error) {
+	return svc.cl.InfoAll(ctx)
+}
+
+// NotificationInfo retrieves notification information.
+func (svc *DeviceService) NotificationInfo(ctx context.Context) (*Notifications, error) {
+	return svc.cl.NotificationInfo(ctx)
+}
+
+// ClearNotifications clears the device notifications.
+func (svc *DeviceService) ClearNotifications(ctx context.Context) (bool, error) {
+	return svc.cl.ClearNotifications(ctx)
+}
+
+// SimInfo retrieves SIM card information.
+func (svc *DeviceService) SimInfo(ctx context.Context) (XMLData, error) {
+	return svc.cl.SimInfo(ctx)
+}
+
+// AutorunVersion retrieves device autorun version.
+func (svc *DeviceService) AutorunVersion(ctx context.Context) (string, error) {
+	return svc.cl.AutorunVersion(ctx)
+}
+
+// FastbootFeatures retrieves fastboot feature information.
+func (svc *DeviceService) FastbootFeatures(ctx context.Context) (XMLData, error) {
+	return svc.cl.FastbootFeatures(ctx)
+}
+
+// FastbootFeaturesSet enables or disables fastboot.
+func (svc *DeviceService) FastbootFeaturesSet(ctx context.Context, enabled bool) (bool, error) {
+	return svc.cl.FastbootFeaturesSet(ctx, enabled)
+}
+
+// PowerFeatures retrieves power feature information.
+func (svc *DeviceService) PowerFeatures(ctx context.Context) (XMLData, error) {
+	return svc.cl.PowerFeatures(ctx)
+}
+
+// PowerFeaturesSet enables or disables power saving.
+func (svc *DeviceService) PowerFeaturesSet(ctx context.Context, enabled bool) (bool, error) {
+	return svc.cl.PowerFeaturesSet(ctx, enabled)
+}
+
+// TetheringFeatures retrieves USB tethering feature information.
+func (svc *DeviceService) TetheringFeatures(ctx context.Context) (XMLData, error) {
+	return svc.cl.TetheringFeatures(ctx)
+}
+
+// TetheringFeaturesSet enables or disables USB tethering.
+func (svc *DeviceService) TetheringFeaturesSet(ctx context.Context, enabled bool) (bool, error) {
+	return svc.cl.TetheringFeaturesSet(ctx, enabled)
+}
+
+// CradleMAC retrieves cradle MAC address.
+func (svc *DeviceService) CradleMAC(ctx context.Context) (string, error) {
+	return svc.cl.CradleMAC(ctx)
+}
+
+// CradleMACSet sets the MAC address for the cradle.
+func (svc *DeviceService) CradleMACSet(ctx context.Context, addr string) (bool, error) {
+	return svc.cl.CradleMACSet(ctx, addr)
+}
+
+// CradleStatusInfo retrieves cradle status information.
+func (svc *DeviceService) CradleStatusInfo(ctx context.Context) (XMLData, error) {
+	return svc.cl.CradleStatusInfo(ctx)
+}
+
+// Language retrieves current language.
+func (svc *DeviceService) Language(ctx context.Context) (string, error) {
+	return svc.cl.Language(ctx)
+}
+
+// LanguageSet sets the language.
+func (svc *DeviceService) LanguageSet(ctx context.Context, lang string) (bool, error) {
+	return svc.cl.LanguageSet(ctx, lang)
+}
+
+// LogInfo retrieves current log setting information.
+func (svc *DeviceService) LogInfo(ctx context.Context) (XMLData, error) {
+	return svc.cl.LogInfo(ctx)
+}
+
+// LogPath retrieves device log path (URL).
+func (svc *DeviceService) LogPath(ctx context.Context) (string, error) {
+	return svc.cl.LogPath(ctx)
+}
+
+// LogSettingSet sets the log level and enables or disables logging.
+func (svc *DeviceService) LogSettingSet(ctx context.Context, level uint, enabled bool) (bool, error) {
+	return svc.cl.LogSettingSet(ctx, level, enabled)
+}
+
+// LogDownload retrieves the compressed device log file using the current
+// session, writing it to w. Returns the number of bytes written.
+func (svc *DeviceService) LogDownload(ctx context.Context, w io.Writer) (int64, error) {
+	return svc.cl.LogDownload(ctx, w)
+}
+
+// FirmwareNewVersion retrieves the result of the last firmware update check.
+func (svc *DeviceService) FirmwareNewVersion(ctx context.Context) (*FirmwareVersion, error) {
+	return svc.cl.FirmwareNewVersion(ctx)
+}
+
+// FirmwareUpdateCheck triggers a check for new firmware versions.
+func (svc *DeviceService) FirmwareUpdateCheck(ctx context.Context) (bool, error) {
+	return svc.cl.FirmwareUpdateCheck(ctx)
+}
+
+// FirmwareUpdateStatus retrieves the status of the firmware update process.
+func (svc *DeviceService) FirmwareUpdateStatus(ctx context.Context) (*FirmwareStatus, error) {
+	return svc.cl.FirmwareUpdateStatus(ctx)
+}
+
+// FirmwareAutoUpdate retrieves whether automatic firmware updates are enabled.
+func (svc *DeviceService) FirmwareAutoUpdate(ctx context.Context) (bool, error) {
+	return svc.cl.FirmwareAutoUpdate(ctx)
+}
+
+// FirmwareAutoUpdateSet enables or disables automatic firmware updates.
+func (svc *DeviceService) FirmwareAutoUpdateSet(ctx context.Context, enabled bool) (bool, error) {
+	return svc.cl.FirmwareAutoUpdateSet(ctx, enabled)
+}
+
+// FirmwareUpgradeStart acknowledges the new firmware version found by the
+// last firmware update check, starting the download and install of the
+// firmware.
+func (svc *DeviceService) FirmwareUpgradeStart(ctx context.Context) (bool, error) {
+	return svc.cl.FirmwareUpgradeStart(ctx)
+}
+
+// FirmwareUpgradeCancel cancels the download of a firmware upgrade.
+func (svc *DeviceService) FirmwareUpgradeCancel(ctx context.Context) (bool, error) {
+	return svc.cl.FirmwareUpgradeCancel(ctx)
+}
+
+// FirmwareUpgradeWait polls the firmware update status every interval,
+// passing each status to progress (if not nil), until the download and
+// install of all components completes or ctx is done.
+//
+// As the device reboots to install the firmware, a failed request after the
+// final component has reached 100% is treated as completion.
+func (svc *DeviceService) FirmwareUpgradeWait(ctx context.Context, interval time.Duration, progress func(FirmwareStatus)) (*FirmwareStatus, error) {
+	return svc.cl.FirmwareUpgradeWait(ctx, interval, progress)
+}
+
+// SecurityService groups the firewall, port forwarding, remote access, and SIM PIN methods of a Client.
+type SecurityService struct {
+	cl *Client
+}
+
+// Security returns the firewall, port forwarding, remote access, and SIM PIN methods of the client.
+func (cl *Client) Security() *SecurityService {
+	return &SecurityService{cl: cl}
+}
+
+// Features retrieves firewall security feature information.
+func (svc *SecurityService) Features(ctx context.Context) (XMLData, error) {
+	return svc.cl.FirewallFeatures(ctx)
+}
+
+// IPFilters retrieves the LAN IP filter rules.
+func (svc *SecurityService) IPFilters(ctx context.Context) ([]IPFilter, error) {
+	return svc.cl.FirewallIPFilters(ctx)
+}
+
+// IPFilterAdd adds a LAN IP filter rule.
+func (svc *SecurityService) IPFilterAdd(ctx context.Context, filter IPFilter) (bool, error) {
+	return svc.cl.FirewallIPFilterAdd(ctx, filter)
+}
+
+// IPFilterRemove removes the LAN IP filter rule with the specified
+// index (ie, its position in the list returned by FirewallIPFilters).
+func (svc *SecurityService) IPFilterRemove(ctx context.Context, index uint) (bool, error) {
+	return svc.cl.FirewallIPFilterRemove(ctx, index)
+}
+
+// MacFilters retrieves the MAC filter rules.
+func (svc *SecurityService) MacFilters(ctx context.Context) ([]MacFilter, error) {
+	return svc.cl.FirewallMacFilters(ctx)
+}
+
+// MacFilterAdd adds an enabled MAC filter rule for the specified MAC
+// address.
+func (svc *SecurityService) MacFilterAdd(ctx context.Context, mac string) (bool, error) {
+	return svc.cl.FirewallMacFilterAdd(ctx, mac)
+}
+
+// MacFilterRemove removes the MAC filter rules for the specified MAC
+// address.
+func (svc *SecurityService) MacFilterRemove(ctx context.Context, mac string) (bool, error) {
+	return svc.cl.FirewallMacFilterRemove(ctx, mac)
+}
+
+// URLFilters retrieves the URL filter rules.
+func (svc *SecurityService) URLFilters(ctx context.Context) ([]URLFilter, error) {
+	return svc.cl.FirewallURLFilters(ctx)
+}
+
+// URLFilterAdd adds an enabled URL filter rule for the specified URL.
+func (svc *SecurityService) URLFilterAdd(ctx context.Context, url string) (bool, error) {
+	return svc.cl.FirewallURLFilterAdd(ctx, url)
+}
+
+// URLFilterRemove removes the URL filter rules for the specified URL.
+func (svc *SecurityService) URLFilterRemove(ctx context.Context, url string) (bool, error) {
+	return svc.cl.FirewallURLFilterRemove(ctx, url)
+}
+
+// DmzConfig retrieves DMZ status and IP address of DMZ host.
+func (svc *SecurityService) DmzConfig(ctx context.Context) (XMLData, error) {
+	return svc.cl.DmzConfig(ctx)
+}
+
+// DmzConfigSet enables or disables the DMZ and the DMZ IP address of the
+// device.
+func (svc *SecurityService) DmzConfigSet(ctx context.Context, enabled bool, dmzIPAddress string) (bool, error) {
+	return svc.cl.DmzConfigSet(ctx, enabled, dmzIPAddress)
+}
+
+// SipAlg retrieves status and port of the SIP application-level gateway.
+func (svc *SecurityService) SipAlg(ctx context.Context) (XMLData, error) {
+	return svc.cl.SipAlg(ctx)
+}
+
+// SipAlgSet enables/disables SIP application-level gateway and sets SIP port.
+func (svc *SecurityService) SipAlgSet(ctx context.Context, port uint, enabled bool) (bool, error) {
+	return svc.cl.SipAlgSet(ctx, port, enabled)
+}
+
+// NatType retrieves NAT type.
+func (svc *SecurityService) NatType(ctx context.Context) (XMLData, error) {
+	return svc.cl.NatType(ctx)
+}
+
+// NatTypeSet sets NAT type (values: 0, 1).
+func (svc *SecurityService) NatTypeSet(ctx context.Context, ntype uint) (bool, error) {
+	return svc.cl.NatTypeSet(ctx, ntype)
+}
+
+// Upnp retrieves the status of UPNP.
+func (svc *SecurityService) Upnp(ctx context.Context) (XMLData, error) {
+	return svc.cl.Upnp(ctx)
+}
+
+// UpnpSet enables/disables UPNP.
+func (svc *SecurityService) UpnpSet(ctx context.Context, enabled bool) (bool, error) {
+	return svc.cl.UpnpSet(ctx, enabled)
+}
+
+// VirtualServers retrieves the port forwarding (virtual server) rules.
+func (svc *SecurityService) VirtualServers(ctx context.Context) ([]VirtualServer, error) {
+	return svc.cl.VirtualServers(ctx)
+}
+
+// VirtualServerAdd adds a port forwarding (virtual server) rule.
+func (svc *SecurityService) VirtualServerAdd(ctx context.Context, server VirtualServer) (bool, error) {
+	return svc.cl.VirtualServerAdd(ctx, server)
+}
+
+// VirtualServerDelete deletes the port forwarding (virtual server) rule with
+// the specified index (ie, its position in the list returned by
+// VirtualServers).
+func (svc *SecurityService) VirtualServerDelete(ctx context.Context, index uint) (bool, error) {
+	return svc.cl.VirtualServerDelete(ctx, index)
+}
+
+// TimeRules retrieves the parental control (internet access time limit)
+// rules.
+func (svc *SecurityService) TimeRules(ctx context.Context) ([]TimeRule, error) {
+	return svc.cl.TimeRules(ctx)
+}
+
+// TimeRuleAdd adds a parental control rule, replacing any existing rule with
+// the same name.
+func (svc *SecurityService) TimeRuleAdd(ctx context.Context, rule TimeRule) (bool, error) {
+	return svc.cl.TimeRuleAdd(ctx, rule)
+}
+
+// TimeRuleDelete deletes the parental control rule with the specified name.
+func (svc *SecurityService) TimeRuleDelete(ctx context.Context, name string) (bool, error) {
+	return svc.cl.TimeRuleDelete(ctx, name)
+}
+
+// TimeRuleEnable enables or disables the parental control rules with the
+// specified names (ie, to switch a homework hours rule set on or off). When
+// no names are specified, all rules are changed.
+func (svc *SecurityService) TimeRuleEnable(ctx context.Context, enabled bool, name ...string) (bool, error) {
+	return svc.cl.TimeRuleEnable(ctx, enabled, name...)
+}
+
+// RemoteAccess retrieves the WAN-side (remote) management access settings.
+func (svc *SecurityService) RemoteAccess(ctx context.Context) (*RemoteAccess, error) {
+	return svc.cl.RemoteAccess(ctx)
+}
+
+// RemoteAccessSet sets the WAN-side (remote) management access settings.
+//
+// Note: when AllowedIPs is empty, remote management is permitted from any
+// address.
+func (svc *SecurityService) RemoteAccessSet(ctx context.Context, access RemoteAccess) (bool, error) {
+	return svc.cl.RemoteAccessSet(ctx, access)
+}
+
+// PinInfo retrieves SIM PIN status information.
+func (svc *SecurityService) PinInfo(ctx context.Context) (XMLData, error) {
+	return svc.cl.PinInfo(ctx)
+}
+
+// PinSaveInfo retrieves SIM PIN save information.
+func (svc *SecurityService) PinSaveInfo(ctx context.Context) (XMLData, error) {
+	return svc.cl.PinSaveInfo(ctx)
+}
+
+// PinSimlockInfo retrieves SIM lock information.
+func (svc *SecurityService) PinSimlockInfo(ctx context.Context) (XMLData, error) {
+	return svc.cl.PinSimlockInfo(ctx)
+}
+
+// PinEnter enters a SIM PIN.
+func (svc *SecurityService) PinEnter(ctx context.Context, pin string) (bool, error) {
+	return svc.cl.PinEnter(ctx, pin)
+}
+
+// PinEnterPuk enters a SIM PIN puk.
+func (svc *SecurityService) PinEnterPuk(ctx context.Context, puk, new string) (bool, error) {
+	return svc.cl.PinEnterPuk(ctx, puk, new)
+}
+
+// PinActivate activates a SIM PIN.
+func (svc *SecurityService) PinActivate(ctx context.Context, pin string) (bool, error) {
+	return svc.cl.PinActivate(ctx, pin)
+}
+
+// PinDeactivate deactivates a SIM PIN.
+func (svc *SecurityService) PinDeactivate(ctx context.Context, pin string) (bool, error) {
+	return svc.cl.PinDeactivate(ctx, pin)
+}
+
+// PinChange changes a SIM PIN.
+func (svc *SecurityService) PinChange(ctx context.Context, pin, new string) (bool, error) {
+	return svc.cl.PinChange(ctx, pin, new)
+}
+
+// PublicKey retrieves webserver public key.
+func (svc *SecurityService) PublicKey(ctx context.Context) (string, error) {
+	return svc.cl.PublicKey(ctx)
+}
@@ -0,0 +1,114 @@
+//go:build go1.21
+// +build go1.21
+
+package hilink
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// WithSlog is a client option that logs each request to the logger, with
+// structured method, path, status, duration and API error code fields.
+// Requests are logged at debug level, while failed requests (transport
+// errors, bad status codes, and API errors) are logged at warn level. Request
+// and response bodies, passwords and session cookies are not logged.
+func WithSlog(logger *slog.Logger) ClientOption {
+	return func(cl *Client) {
+		cl.cl.Transport = &slogTransport{
+			transport: cl.cl.Transport,
+			logger:    logger,
+		}
+	}
+}
+
+// slogTransport is a http transport that logs requests to a slog logger.
+type slogTransport struct {
+	transport http.RoundTripper
+	logger    *slog.Logger
+}
+
+// wrapped satisfies the transportWrapper interface.
+func (t *slogTransport) wrapped() http.RoundTripper {
+	return t.transport
+}
+
+// wrap satisfies the transportWrapper interface.
+func (t *slogTransport) wrap(transport http.RoundTripper) http.RoundTripper {
+	return &slogTransport{
+		transport: transport,
+		logger:    t.logger,
+	}
+}
+
+// RoundTrip satisfies the http.RoundTripper interface.
+func (t *slogTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	transport := t.transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	start := time.Now()
+	res, err := transport.RoundTrip(req)
+	level, attrs := slog.LevelDebug, []slog.Attr{
+		slog.String("method", req.Method),
+		slog.String("path", req.URL.Path),
+	}
+	if err == nil {
+		attrs = append(attrs, slog.Int("status", res.StatusCode))
+		if res.StatusCode != http.StatusOK {
+			level = slog.LevelWarn
+		}
+		// read xml bodies to check for api errors, leaving other bodies (ie,
+		// log downloads) to stream
+		if isXMLResponse(res) {
+			var body []byte
+			body, err = ioutil.ReadAll(res.Body)
+			res.Body.Close()
+			if err != nil {
+				res = nil
+			} else {
+				res.Body = ioutil.NopCloser(bytes.NewReader(body))
+				if code := apiErrorCode(body); code != 0 {
+					level, attrs = slog.LevelWarn, append(attrs, slog.Int("code", code))
+				}
+			}
+		}
+	}
+	attrs = append(attrs, slog.Duration("duration", time.Since(start)))
+	if err != nil {
+		level, attrs = slog.LevelWarn, append(attrs, slog.Any("error", err))
+	}
+	t.logger.LogAttrs(req.Context(), level, "hilink request", attrs...)
+	return res, err
+}
+
+// maxXMLResponse is the maximum size of responses without a xml content type
+// that are read to check for api errors.
+const maxXMLResponse = 64 * 1024
+
+// isXMLResponse returns true when the response has a xml content type, or is
+// small enough to be an API response (as some firmwares send API responses as
+// text/html).
+func isXMLResponse(res *http.Response) bool {
+	if strings.Contains(res.Header.Get("Content-Type"), "xml") {
+		return true
+	}
+	return 0 <= res.ContentLength && res.ContentLength <= maxXMLResponse
+}
+
+// apiErrorCode returns the API error code of an error response body, or 0.
+func apiErrorCode(body []byte) int {
+	if !bytes.Contains(body, []byte("<error>")) {
+		return 0
+	}
+	var apiErr *APIError
+	if _, err := xmlDecode(body, false); errors.As(err, &apiErr) {
+		return apiErr.Code
+	}
+	return 0
+}
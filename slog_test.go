@@ -0,0 +1,90 @@
+//go:build go1.21
+// +build go1.21
+
+package hilink
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSlogTransportReadError(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		// send a truncated body
+		conn, buf, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			t.Errorf("expected no error, got: %v", err)
+			return
+		}
+		defer conn.Close()
+		_, _ = buf.WriteString("HTTP/1.1 200 OK\r\nContent-Type: text/xml\r\nContent-Length: 100\r\n\r\n<response>")
+		_ = buf.Flush()
+	}))
+	defer s.Close()
+	var logs bytes.Buffer
+	transport := &slogTransport{logger: slog.New(slog.NewTextHandler(&logs, nil))}
+	req, err := http.NewRequest("GET", s.URL+"/api/monitoring/status", nil)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	res, err := transport.RoundTrip(req)
+	if err == nil {
+		t.Fatalf("expected error")
+	}
+	if res != nil {
+		t.Errorf("expected nil response, got: %v", res)
+	}
+	if !strings.Contains(logs.String(), "level=WARN") {
+		t.Errorf("expected warning, got: %s", logs.String())
+	}
+}
+
+func TestSlogTransportStream(t *testing.T) {
+	done := make(chan struct{})
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "application/octet-stream")
+		_, _ = w.Write([]byte("first\n"))
+		w.(http.Flusher).Flush()
+		// block until the client has read the first line
+		<-done
+		_, _ = w.Write([]byte("second\n"))
+	}))
+	defer s.Close()
+	cl := NewClient(WithURL(s.URL), WithSlog(slog.New(slog.NewTextHandler(io.Discard, nil))))
+	req, err := http.NewRequest("GET", s.URL+"/log.tar.gz", nil)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	type result struct {
+		res *http.Response
+		err error
+	}
+	ch := make(chan result, 1)
+	go func() {
+		res, err := cl.cl.Transport.RoundTrip(req)
+		ch <- result{res, err}
+	}()
+	var r result
+	select {
+	case r = <-ch:
+	case <-time.After(5 * time.Second):
+		close(done)
+		t.Fatalf("expected response before the body was complete")
+	}
+	if r.err != nil {
+		t.Fatalf("expected no error, got: %v", r.err)
+	}
+	defer r.res.Body.Close()
+	line, err := bufio.NewReader(r.res.Body).ReadString('\n')
+	close(done)
+	if err != nil || line != "first\n" {
+		t.Errorf("expected first line, got: %q %v", line, err)
+	}
+}
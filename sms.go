@@ -0,0 +1,115 @@
+package hilink
+
+import (
+	"strings"
+	"time"
+	"unicode/utf16"
+
+	"github.com/kenshaw/hilink/pdu"
+)
+
+// SmsEncoding represents the different SMS encodings.
+type SmsEncoding int
+
+// SmsEncoding values.
+const (
+	SmsEncodingGSM7 SmsEncoding = iota
+	SmsEncodingUCS2
+)
+
+const (
+	// SmsMaxParts is the maximum number of parts of a concatenated SMS sent
+	// by the device.
+	SmsMaxParts = 10
+	// SmsPageSize is the number of messages retrieved per page when listing
+	// all messages.
+	SmsPageSize = 50
+)
+
+// SmsEncodingOf returns the encoding required to send msg.
+func SmsEncodingOf(msg string) SmsEncoding {
+	if pdu.IsGSM7(msg) {
+		return SmsEncodingGSM7
+	}
+	return SmsEncodingUCS2
+}
+
+// smsRuneLen returns the number of septets (GSM-7) or 16-bit code units
+// (UCS-2) used by r.
+func smsRuneLen(enc SmsEncoding, r rune) int {
+	if enc == SmsEncodingUCS2 {
+		return len(utf16.Encode([]rune{r}))
+	}
+	return pdu.SeptetLen(r)
+}
+
+// smsLimits returns the single and per-part (concatenated) limits for enc.
+func smsLimits(enc SmsEncoding) (int, int) {
+	if enc == SmsEncodingUCS2 {
+		return 70, 67
+	}
+	return 160, 153
+}
+
+// SmsLength returns the length of msg in the units of the encoding required
+// by msg (septets for GSM-7, and 16-bit characters for UCS-2), along with the
+// single SMS limit for the encoding (160 for GSM-7, and 70 for UCS-2).
+func SmsLength(msg string) (int, int, SmsEncoding) {
+	enc := SmsEncodingOf(msg)
+	single, _ := smsLimits(enc)
+	n := 0
+	for _, r := range msg {
+		n += smsRuneLen(enc, r)
+	}
+	return n, single, enc
+}
+
+// SmsParts splits msg into the parts of a concatenated SMS, using the per-part
+// limits of the encoding required by msg (153 septets for GSM-7, and 67
+// characters for UCS-2). A message fitting in a single SMS is returned as a
+// single part. Characters are never split across parts.
+func SmsParts(msg string) []string {
+	n, single, enc := SmsLength(msg)
+	if n <= single {
+		return []string{msg}
+	}
+	_, limit := smsLimits(enc)
+	var parts []string
+	var part strings.Builder
+	n = 0
+	for _, r := range msg {
+		l := smsRuneLen(enc, r)
+		if n+l > limit {
+			parts = append(parts, part.String())
+			part.Reset()
+			n = 0
+		}
+		part.WriteRune(r)
+		n += l
+	}
+	return append(parts, part.String())
+}
+
+// smsMessages converts a decoded SMS list into messages.
+func smsMessages(res XMLData) []SmsMessage {
+	messages, ok := res["Messages"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	var l []SmsMessage
+	for _, m := range xmlList(messages["Message"]) {
+		date, _ := time.ParseInLocation("2006-01-02 15:04:05", xmlStr(m, "Date"), time.Local)
+		l = append(l, SmsMessage{
+			Index:    xmlInt(m, "Index"),
+			Status:   xmlInt(m, "Smstat"),
+			Phone:    xmlStr(m, "Phone"),
+			Content:  xmlStr(m, "Content"),
+			Date:     date,
+			Sca:      xmlStr(m, "Sca"),
+			SaveType: xmlInt(m, "SaveType"),
+			Priority: xmlInt(m, "Priority"),
+			Type:     SmsType(xmlInt(m, "SmsType")),
+		})
+	}
+	return l
+}
@@ -0,0 +1,38 @@
+package hilink
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSmsLength(t *testing.T) {
+	tests := []struct {
+		msg    string
+		n      int
+		single int
+		enc    SmsEncoding
+	}{
+		{"hello", 5, 160, SmsEncodingGSM7},
+		{"€5 [x]", 9, 160, SmsEncodingGSM7},
+		{"\x1b", 1, 70, SmsEncodingUCS2},
+		{"hello 😀", 8, 70, SmsEncodingUCS2},
+	}
+	for _, test := range tests {
+		n, single, enc := SmsLength(test.msg)
+		if n != test.n || single != test.single || enc != test.enc {
+			t.Errorf("%q: expected %d/%d/%d, got: %d/%d/%d", test.msg, test.n, test.single, test.enc, n, single, enc)
+		}
+	}
+}
+
+func TestSmsParts(t *testing.T) {
+	// extension characters are never split across parts
+	msg := strings.Repeat("a", 152) + "€" + strings.Repeat("b", 7)
+	parts := SmsParts(msg)
+	if len(parts) != 2 || parts[0] != strings.Repeat("a", 152) || parts[1] != "€bbbbbbb" {
+		t.Errorf("expected 2 parts, got: %q", parts)
+	}
+	if parts := SmsParts("hello"); len(parts) != 1 || parts[0] != "hello" {
+		t.Errorf("expected single part, got: %q", parts)
+	}
+}
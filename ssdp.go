@@ -0,0 +1,107 @@
+package hilink
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ssdpAddr is the SSDP multicast address.
+const ssdpAddr = "239.255.255.250:1900"
+
+// searchSSDP sends an SSDP search, retrieving the device description of
+// each device that responds.
+func (d *discoverer) searchSSDP(ctx context.Context, wait time.Duration) error {
+	req := []byte("M-SEARCH * HTTP/1.1\r\n" +
+		"HOST: " + ssdpAddr + "\r\n" +
+		"MAN: \"ssdp:discover\"\r\n" +
+		"MX: 2\r\n" +
+		"ST: upnp:rootdevice\r\n\r\n")
+	seen := make(map[string]bool)
+	return search(ctx, wait, ssdpAddr, req, func(wg *sync.WaitGroup, buf []byte, _ net.Addr) {
+		location := ssdpLocation(buf)
+		if location == "" || seen[location] {
+			return
+		}
+		seen[location] = true
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if dev, err := ssdpDevice(ctx, location); err == nil && dev != nil {
+				d.add(*dev, DiscoverSSDP)
+			}
+		}()
+	})
+}
+
+// ssdpLocation returns the device description location of an SSDP search
+// response.
+func ssdpLocation(buf []byte) string {
+	res, err := http.ReadResponse(bufio.NewReader(bytes.NewReader(buf)), nil)
+	if err != nil {
+		return ""
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return ""
+	}
+	return res.Header.Get("Location")
+}
+
+// ssdpDescription is a UPnP device description.
+type ssdpDescription struct {
+	Device struct {
+		FriendlyName    string `xml:"friendlyName"`
+		Manufacturer    string `xml:"manufacturer"`
+		ModelName       string `xml:"modelName"`
+		PresentationURL string `xml:"presentationURL"`
+	} `xml:"device"`
+}
+
+// ssdpDevice retrieves the device description at location, returning nil
+// when the device is not a HiLink (Huawei) device.
+func ssdpDevice(ctx context.Context, location string) (*DiscoveredDevice, error) {
+	u, err := url.Parse(location)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(ctx, DefaultTimeout)
+	defer cancel()
+	req, err := http.NewRequest("GET", location, nil)
+	if err != nil {
+		return nil, err
+	}
+	res, err := http.DefaultClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: %d", ErrBadStatusCode, res.StatusCode)
+	}
+	var desc ssdpDescription
+	if err := xml.NewDecoder(res.Body).Decode(&desc); err != nil {
+		return nil, err
+	}
+	if !strings.Contains(strings.ToLower(desc.Device.Manufacturer), "huawei") {
+		return nil, nil
+	}
+	// the description is served on a separate port from the WebUI
+	endpoint := "http://" + u.Hostname() + "/"
+	if p, err := url.Parse(desc.Device.PresentationURL); err == nil && p.IsAbs() {
+		endpoint = p.Scheme + "://" + p.Host + "/"
+	}
+	return &DiscoveredDevice{
+		URL:   endpoint,
+		Name:  desc.Device.FriendlyName,
+		Model: desc.Device.ModelName,
+	}, nil
+}
@@ -0,0 +1,79 @@
+package hilink
+
+import (
+	"context"
+	"math"
+	"time"
+)
+
+// Throughput is the throughput measured by SampleThroughput.
+type Throughput struct {
+	// Time is the time of the first reading, and Duration is the time
+	// between the readings.
+	Time     time.Time
+	Duration time.Duration
+	// Upload and Download are the rates, in bits per second.
+	Upload   uint64
+	Download uint64
+	// Reset is true when the device's counters were reset between the
+	// readings (ie, by TrafficClear), in which case the rates are calculated
+	// from the counters after the reset.
+	Reset bool
+}
+
+// SampleThroughput measures the current upload and download rates, by taking
+// two traffic statistics readings window apart (default 1 second). Handles
+// devices with 32-bit counters that roll over.
+func (cl *Client) SampleThroughput(ctx context.Context, window time.Duration) (*Throughput, error) {
+	if window <= 0 {
+		window = time.Second
+	}
+	prev, err := cl.Traffic(ctx)
+	if err != nil {
+		return nil, err
+	}
+	start := time.Now()
+	t := time.NewTimer(window)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-t.C:
+	}
+	cur, err := cl.Traffic(ctx)
+	if err != nil {
+		return nil, err
+	}
+	d := time.Since(start)
+	up, upReset := counterDelta(prev.TotalUpload, cur.TotalUpload)
+	down, downReset := counterDelta(prev.TotalDownload, cur.TotalDownload)
+	return &Throughput{
+		Time:     start,
+		Duration: d,
+		Upload:   bitsPerSecond(up, d),
+		Download: bitsPerSecond(down, d),
+		Reset:    upReset || downReset,
+	}, nil
+}
+
+// counterDelta returns the difference between two readings of a traffic
+// counter. A counter in the upper half of the 32-bit range that decreased is
+// treated as having rolled over, otherwise the counter is treated as having
+// been reset, returning true.
+func counterDelta(prev, cur uint64) (uint64, bool) {
+	switch {
+	case cur >= prev:
+		return cur - prev, false
+	case prev > math.MaxUint32/2 && prev <= math.MaxUint32:
+		return math.MaxUint32 - prev + cur + 1, false
+	}
+	return cur, true
+}
+
+// bitsPerSecond returns the rate for n bytes transferred over d.
+func bitsPerSecond(n uint64, d time.Duration) uint64 {
+	if d <= 0 {
+		return 0
+	}
+	return uint64(float64(n) * 8 / d.Seconds())
+}
@@ -0,0 +1,136 @@
+package hilink
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// TracerName is the instrumentation name passed to the TracerProvider given
+// with WithTracerProvider.
+const TracerName = "github.com/kenshaw/hilink"
+
+// TracerProvider provides tracers for a instrumentation name.
+//
+// TracerProvider, Tracer, and Span are a subset of the OpenTelemetry tracing
+// API, so that the package does not depend on OpenTelemetry. The hilinkotel
+// module provides the adapter for a OpenTelemetry trace.TracerProvider.
+type TracerProvider interface {
+	Tracer(name string) Tracer
+}
+
+// Tracer starts spans.
+type Tracer interface {
+	Start(ctx context.Context, spanName string) (context.Context, Span)
+}
+
+// Span is a span started by a Tracer. Attribute values are strings, ints,
+// or bools.
+type Span interface {
+	SetAttribute(key string, value interface{})
+	RecordError(err error)
+	End()
+}
+
+// WithTracerProvider is a client option that traces each API call as a span
+// named hilink followed by the API path.
+//
+// Spans have the OpenTelemetry HTTP client attributes (http.request.method,
+// url.full, server.address, server.port, http.response.status_code, and
+// error.type), and the hilink.path and, for errors returned by the device,
+// hilink.error_code attributes. Spans are started using the context passed
+// to the call, and as such are part of any trace in progress.
+func WithTracerProvider(tp TracerProvider) ClientOption {
+	return func(cl *Client) {
+		cl.tracer = nil
+		if tp != nil {
+			cl.tracer = tp.Tracer(TracerName)
+		}
+	}
+}
+
+// spanKey is the context key for the span of a API call.
+type spanKey struct{}
+
+// startSpan starts a span for the API call, returning the func to end the
+// span with the call's error.
+func (cl *Client) startSpan(ctx context.Context, path string, v interface{}) (context.Context, func(error)) {
+	if cl.tracer == nil {
+		return ctx, func(error) {}
+	}
+	method := "GET"
+	if v != nil {
+		method = "POST"
+	}
+	ctx, span := cl.tracer.Start(ctx, "hilink "+path)
+	span.SetAttribute("http.request.method", method)
+	span.SetAttribute("hilink.path", path)
+	return context.WithValue(ctx, spanKey{}, span), func(err error) {
+		var apiErr *APIError
+		switch {
+		case err == nil:
+		case errors.As(err, &apiErr):
+			span.SetAttribute("hilink.error_code", apiErr.Code)
+			span.SetAttribute("error.type", "hilink."+strconv.Itoa(apiErr.Code))
+		case !errors.Is(err, ErrBadStatusCode):
+			// bad status codes have the status code as the error type
+			span.SetAttribute("error.type", errorType(err))
+		}
+		if err != nil {
+			span.RecordError(err)
+		}
+		span.End()
+	}
+}
+
+// traceRequest adds the request attributes to the span of the API call.
+func traceRequest(ctx context.Context, req *http.Request) {
+	span, ok := ctx.Value(spanKey{}).(Span)
+	if !ok {
+		return
+	}
+	span.SetAttribute("url.full", req.URL.Redacted())
+	span.SetAttribute("server.address", req.URL.Hostname())
+	port, err := strconv.Atoi(req.URL.Port())
+	switch {
+	case err == nil:
+	case req.URL.Scheme == "https":
+		port = 443
+	default:
+		port = 80
+	}
+	span.SetAttribute("server.port", port)
+}
+
+// traceResponse adds the response attributes to the span of the API call.
+func traceResponse(ctx context.Context, res *http.Response) {
+	span, ok := ctx.Value(spanKey{}).(Span)
+	if !ok {
+		return
+	}
+	span.SetAttribute("http.response.status_code", res.StatusCode)
+	// only 200 responses are successful (see ErrBadStatusCode)
+	if res.StatusCode != http.StatusOK {
+		span.SetAttribute("error.type", strconv.Itoa(res.StatusCode))
+	}
+}
+
+// errorType returns the low cardinality error.type attribute value for err.
+func errorType(err error) string {
+	var urlErr *url.Error
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return "timeout"
+	case errors.Is(err, context.Canceled):
+		return "canceled"
+	case errors.As(err, &urlErr):
+		return "url.Error"
+	}
+	var e Error
+	if errors.As(err, &e) {
+		return "hilink.Error"
+	}
+	return "_OTHER"
+}
@@ -0,0 +1,136 @@
+package hilink
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+func TestTracerProvider(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/api/webserver/SesTokInfo":
+			_, _ = w.Write([]byte(`<response><SesInfo>SessionID=trace</SesInfo><TokInfo>trace</TokInfo></response>`))
+		case "/api/monitoring/status":
+			_, _ = w.Write([]byte(`<response><ConnectionStatus>901</ConnectionStatus></response>`))
+		case "/api/device/information":
+			_, _ = w.Write([]byte(`<error><code>100003</code><message></message></error>`))
+		default:
+			http.NotFound(w, req)
+		}
+	}))
+	defer s.Close()
+	u, err := url.Parse(s.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	port, _ := strconv.Atoi(u.Port())
+	tp := new(testTracerProvider)
+	cl := NewClient(WithURL(s.URL), WithTracerProvider(tp))
+	ctx := context.Background()
+	if _, err := cl.Status(ctx); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if _, err := cl.DeviceInfo(ctx); err == nil {
+		t.Fatal("expected error")
+	}
+	if _, err := cl.DoRaw(ctx, "api/missing", nil); err == nil {
+		t.Fatal("expected error")
+	}
+	tests := []struct {
+		name  string
+		attrs map[string]interface{}
+		err   bool
+	}{
+		{"hilink api/monitoring/status", map[string]interface{}{
+			"http.request.method":       "GET",
+			"url.full":                  s.URL + "/api/monitoring/status",
+			"server.address":            "127.0.0.1",
+			"server.port":               port,
+			"http.response.status_code": 200,
+			"hilink.path":               "api/monitoring/status",
+		}, false},
+		{"hilink api/device/information", map[string]interface{}{
+			"http.request.method":       "GET",
+			"http.response.status_code": 200,
+			"hilink.path":               "api/device/information",
+			"hilink.error_code":         100003,
+			"error.type":                "hilink.100003",
+		}, true},
+		{"hilink api/missing", map[string]interface{}{
+			"http.response.status_code": 404,
+			"error.type":                "404",
+		}, true},
+	}
+	for _, test := range tests {
+		sp := tp.span(test.name)
+		if sp == nil {
+			t.Errorf("expected span %q", test.name)
+			continue
+		}
+		if !sp.ended {
+			t.Errorf("expected span %q to be ended", test.name)
+		}
+		if (sp.err != nil) != test.err {
+			t.Errorf("span %q expected error %t, got: %v", test.name, test.err, sp.err)
+		}
+		for k, v := range test.attrs {
+			if sp.attrs[k] != v {
+				t.Errorf("span %q expected %s %v, got: %v", test.name, k, v, sp.attrs[k])
+			}
+		}
+	}
+}
+
+// testTracerProvider records the spans started by its tracer.
+type testTracerProvider struct {
+	spans []*testSpan
+	sync.Mutex
+}
+
+func (tp *testTracerProvider) Tracer(string) Tracer {
+	return tp
+}
+
+func (tp *testTracerProvider) Start(ctx context.Context, name string) (context.Context, Span) {
+	tp.Lock()
+	defer tp.Unlock()
+	sp := &testSpan{name: name, attrs: make(map[string]interface{})}
+	tp.spans = append(tp.spans, sp)
+	return ctx, sp
+}
+
+func (tp *testTracerProvider) span(name string) *testSpan {
+	tp.Lock()
+	defer tp.Unlock()
+	for _, sp := range tp.spans {
+		if sp.name == name {
+			return sp
+		}
+	}
+	return nil
+}
+
+// testSpan is a recorded span.
+type testSpan struct {
+	name  string
+	attrs map[string]interface{}
+	err   error
+	ended bool
+}
+
+func (sp *testSpan) SetAttribute(key string, value interface{}) {
+	sp.attrs[key] = value
+}
+
+func (sp *testSpan) RecordError(err error) {
+	sp.err = err
+}
+
+func (sp *testSpan) End() {
+	sp.ended = true
+}
@@ -0,0 +1,202 @@
+package hilink
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+	"unicode"
+
+	"github.com/kenshaw/hilink/pdu"
+)
+
+// UssdPollInterval is the interval between USSD status checks while waiting
+// for USSD content.
+const UssdPollInterval = 500 * time.Millisecond
+
+// UssdSession is an interactive USSD session, used to navigate multi-step
+// USSD menus (ie, balance transfers, bundle purchases).
+type UssdSession struct {
+	cl       *Client
+	codeType UssdCodeType
+}
+
+// UssdSendAndWait sends a USSD code and waits until the USSD status is no
+// longer waiting (or ctx is done), returning the USSD content.
+func (cl *Client) UssdSendAndWait(ctx context.Context, code string) (string, error) {
+	return cl.UssdSendAndWaitOpts(ctx, code, UssdCodeTypeText)
+}
+
+// UssdSendAndWaitOpts sends a USSD code using the specified code type, and
+// waits until the USSD status is no longer waiting (or ctx is done),
+// returning the USSD content (see UssdContentOpts).
+func (cl *Client) UssdSendAndWaitOpts(ctx context.Context, code string, codeType UssdCodeType) (string, error) {
+	ok, err := cl.UssdCodeOpts(ctx, code, codeType, 0)
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		return "", errors.New("could not send ussd code")
+	}
+	return cl.ussdWait(ctx, codeType)
+}
+
+// UssdStart starts an interactive USSD session by sending the USSD code,
+// returning the session and the content (ie, menu) sent by the network.
+func (cl *Client) UssdStart(ctx context.Context, code string) (*UssdSession, string, error) {
+	return cl.UssdStartOpts(ctx, code, UssdCodeTypeText)
+}
+
+// UssdStartOpts starts an interactive USSD session by sending the USSD code
+// using the specified code type, which is also used for the session's
+// replies, returning the session and the content (ie, menu) sent by the
+// network.
+func (cl *Client) UssdStartOpts(ctx context.Context, code string, codeType UssdCodeType) (*UssdSession, string, error) {
+	s := &UssdSession{cl: cl, codeType: codeType}
+	content, err := s.send(ctx, code)
+	if err != nil {
+		return nil, "", err
+	}
+	return s, content, nil
+}
+
+// Reply sends a reply to the last prompt of the session, returning the next
+// content sent by the network.
+func (s *UssdSession) Reply(ctx context.Context, text string) (string, error) {
+	return s.send(ctx, text)
+}
+
+// Close releases the session.
+func (s *UssdSession) Close(ctx context.Context) error {
+	ok, err := s.cl.UssdRelease(ctx)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errors.New("could not release ussd session")
+	}
+	return nil
+}
+
+// send sends text and waits for the content.
+func (s *UssdSession) send(ctx context.Context, text string) (string, error) {
+	return s.cl.UssdSendAndWaitOpts(ctx, text, s.codeType)
+}
+
+// ussdWait polls the USSD status until it is no longer waiting, returning the
+// USSD content.
+func (cl *Client) ussdWait(ctx context.Context, codeType UssdCodeType) (string, error) {
+	for {
+		state, err := cl.UssdStatus(ctx)
+		if err != nil {
+			return "", err
+		}
+		if state != UssdStateWaiting {
+			return cl.UssdContentOpts(ctx, codeType)
+		}
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-time.After(UssdPollInterval):
+		}
+	}
+}
+
+// UssdStep is a step of a USSD script. Each step sends either a USSD code or
+// a reply to the active session, and optionally checks the returned content.
+type UssdStep struct {
+	// Code is a USSD code to send.
+	Code string `json:"code,omitempty" yaml:"code,omitempty"`
+	// Reply is a reply to send to the active session. Submatches of the
+	// previous step's Expect can be referenced as $1, $2, ... (or ${name} for
+	// named groups).
+	Reply string `json:"reply,omitempty" yaml:"reply,omitempty"`
+	// Expect is a regular expression the returned content must match.
+	Expect string `json:"expect,omitempty" yaml:"expect,omitempty"`
+}
+
+// UssdScript is a declarative USSD flow (ie, an operator's menu driven top-up
+// flow).
+type UssdScript struct {
+	// CodeType is the code type used to send the codes and replies (default
+	// text).
+	CodeType UssdCodeType `json:"code_type,omitempty" yaml:"code_type,omitempty"`
+	Steps    []UssdStep   `json:"steps" yaml:"steps"`
+}
+
+// UssdRun runs a USSD script, returning the content returned for each of the
+// executed steps. The USSD session is released after the script completes or
+// fails.
+func (cl *Client) UssdRun(ctx context.Context, script UssdScript) ([]string, error) {
+	defer cl.UssdRelease(ctx)
+	var contents []string
+	var re *regexp.Regexp
+	var match []int
+	var prev string
+	codeType := script.CodeType
+	if codeType == "" {
+		codeType = UssdCodeTypeText
+	}
+	for i, step := range script.Steps {
+		// determine text to send
+		text := step.Code
+		if step.Reply != "" {
+			text = step.Reply
+			if re != nil {
+				text = string(re.ExpandString(nil, step.Reply, prev, match))
+			}
+		}
+		if text == "" {
+			return contents, fmt.Errorf("ussd script step %d: no code or reply", i+1)
+		}
+		content, err := cl.UssdSendAndWaitOpts(ctx, text, codeType)
+		if err != nil {
+			return contents, fmt.Errorf("ussd script step %d: %w", i+1, err)
+		}
+		contents = append(contents, content)
+		// check content
+		re, match, prev = nil, nil, content
+		if step.Expect != "" {
+			if re, err = regexp.Compile(step.Expect); err != nil {
+				return contents, fmt.Errorf("ussd script step %d: %w", i+1, err)
+			}
+			if match = re.FindStringSubmatchIndex(content); match == nil {
+				return contents, fmt.Errorf("ussd script step %d: %w: %q", i+1, ErrUnexpectedContent, content)
+			}
+		}
+	}
+	return contents, nil
+}
+
+// ussdDecode decodes USSD content returned hex encoded in response to a raw
+// code, as either UCS-2 (when all characters are in the Latin or Cyrillic
+// blocks) or packed GSM-7. Content that is not hex encoded, or that does not
+// decode to printable text, is returned unchanged.
+func ussdDecode(s string) string {
+	if len(s) == 0 || len(s)%2 != 0 {
+		return s
+	}
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return s
+	}
+	// ucs-2
+	ucs2 := len(b)%2 == 0
+	for i := 0; ucs2 && i < len(b); i += 2 {
+		ucs2 = b[i] == 0x00 || b[i] == 0x04
+	}
+	var text string
+	if ucs2 {
+		text = pdu.DecodeUCS2(b)
+	} else {
+		text = pdu.DecodePackedGSM7(b)
+	}
+	for _, r := range text {
+		if !unicode.IsPrint(r) && !unicode.IsSpace(r) {
+			return s
+		}
+	}
+	return text
+}
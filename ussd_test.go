@@ -0,0 +1,53 @@
+package hilink
+
+import (
+	"context"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kenshaw/hilink/pdu"
+)
+
+func TestUssdContent(t *testing.T) {
+	b, err := pdu.EncodePackedGSM7("Balance 10.50")
+	if err != nil {
+		t.Fatal(err)
+	}
+	gsm7 := strings.ToUpper(hex.EncodeToString(b))
+	ucs2 := "04110430043B0430043D0441"
+	tests := []struct {
+		content  string
+		codeType UssdCodeType
+		exp      string
+	}{
+		{"12345678", UssdCodeTypeText, "12345678"},
+		{"20231231", UssdCodeTypeText, "20231231"},
+		{gsm7, UssdCodeTypeText, gsm7},
+		{"12345678", "", "12345678"},
+		{gsm7, UssdCodeTypeRaw, "Balance 10.50"},
+		{ucs2, UssdCodeTypeRaw, "Баланс"},
+		{"Your balance is 10", UssdCodeTypeRaw, "Your balance is 10"},
+	}
+	for i, test := range tests {
+		s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			_, _ = w.Write([]byte(`<response><content>` + test.content + `</content></response>`))
+		}))
+		cl := NewClient(WithURL(s.URL))
+		ctx := context.Background()
+		content, err := cl.UssdContentOpts(ctx, test.codeType)
+		switch {
+		case err != nil:
+			t.Errorf("test %d expected no error, got: %v", i, err)
+		case content != test.exp:
+			t.Errorf("test %d expected %q, got: %q", i, test.exp, content)
+		}
+		// never decoded
+		if content, err := cl.UssdContent(ctx); err != nil || content != test.content {
+			t.Errorf("test %d expected %q, got: %q %v", i, test.content, content, err)
+		}
+		s.Close()
+	}
+}
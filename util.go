@@ -2,35 +2,178 @@ package hilink
 
 import (
 	"bytes"
+	"encoding/xml"
 	"errors"
 	"fmt"
 	"io"
-
-	"github.com/clbanning/mxj/v2"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
 )
 
-// xmlPairs combines xml name/value pairs as a properly formatted XML buffer.
-func xmlPairs(indent string, vals ...string) []byte {
-	// make sure we have pairs
-	if len(vals)%2 != 0 {
-		panic(fmt.Errorf("xmlPairs can only accept pairs of strings, length: %d", len(vals)))
+// xmlList converts a decoded XML value that may be either a single element or
+// a list of elements into a list of elements.
+func xmlList(v interface{}) []map[string]interface{} {
+	switch x := v.(type) {
+	case map[string]interface{}:
+		return []map[string]interface{}{x}
+	case []interface{}:
+		var l []map[string]interface{}
+		for _, z := range x {
+			if m, ok := z.(map[string]interface{}); ok {
+				l = append(l, m)
+			}
+		}
+		return l
+	}
+	return nil
+}
+
+// macFilterRemove clears the MAC filter slots matching mac.
+func macFilterRemove(slots [][2]string, mac string) [][2]string {
+	for i := range slots {
+		if strings.EqualFold(slots[i][0], mac) {
+			slots[i] = [2]string{}
+		}
+	}
+	return slots
+}
+
+// clockToString converts an offset from midnight to a HH:MM string.
+func clockToString(d time.Duration) string {
+	return fmt.Sprintf("%02d:%02d", int(d.Hours())%24, int(d.Minutes())%60)
+}
+
+// parseClock parses a HH:MM string as an offset from midnight.
+func parseClock(s string) (time.Duration, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, ErrInvalidValue
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// weekdaysToString converts weekdays to a comma separated list.
+func weekdaysToString(days []time.Weekday) string {
+	var s []string
+	for _, d := range days {
+		s = append(s, fmt.Sprintf("%d", d))
+	}
+	return strings.Join(s, ",")
+}
+
+// parseWeekdays parses a comma separated list of weekdays.
+func parseWeekdays(s string) ([]time.Weekday, error) {
+	var days []time.Weekday
+	for _, z := range strings.Split(s, ",") {
+		if z = strings.TrimSpace(z); z == "" {
+			continue
+		}
+		i, err := strconv.Atoi(z)
+		if err != nil || i < 0 || i > 6 {
+			return nil, ErrInvalidValue
+		}
+		days = append(days, time.Weekday(i))
+	}
+	return days, nil
+}
+
+// xmlStr returns the first string value in m with one of the provided keys.
+func xmlStr(m map[string]interface{}, keys ...string) string {
+	for _, k := range keys {
+		if s, ok := m[k].(string); ok && s != "" {
+			return s
+		}
+	}
+	return ""
+}
+
+// xmlUint returns the first uint value in m with one of the provided keys.
+func xmlUint(m map[string]interface{}, keys ...string) uint64 {
+	i, _ := strconv.ParseUint(xmlStr(m, keys...), 10, 64)
+	return i
+}
+
+// xmlInt returns the first int value in m with one of the provided keys.
+func xmlInt(m map[string]interface{}, keys ...string) int {
+	i, _ := strconv.Atoi(strings.TrimSuffix(xmlStr(m, keys...), "dBm"))
+	return i
+}
+
+// xmlEarfcn returns the first EARFCN value in m with one of the provided keys,
+// handling the downlink/uplink pair reported by newer firmware (ie, DL:1300
+// UL:19300) by returning the downlink value.
+func xmlEarfcn(m map[string]interface{}, keys ...string) int {
+	s := xmlStr(m, keys...)
+	if i := strings.Index(s, "DL:"); i != -1 {
+		s = s[i+3:]
+		if j := strings.IndexFunc(s, func(r rune) bool { return r < '0' || r > '9' }); j != -1 {
+			s = s[:j]
+		}
+	}
+	i, _ := strconv.Atoi(s)
+	return i
+}
+
+// xmlFloat returns the first float value in m with one of the provided keys,
+// stripping any unit suffix (ie, dBm, dB, MHz) and range prefix (ie, >=).
+func xmlFloat(m map[string]interface{}, keys ...string) float64 {
+	s := strings.TrimLeft(xmlStr(m, keys...), "<>=")
+	s = strings.TrimRightFunc(s, func(r rune) bool {
+		return r != '.' && (r < '0' || r > '9')
+	})
+	f, _ := strconv.ParseFloat(s, 64)
+	return f
+}
+
+// parseDataLimit parses a data limit with units (ie, 500MB, 10GB) as bytes.
+func parseDataLimit(s string) (uint64, error) {
+	s = strings.ToUpper(strings.TrimSpace(s))
+	if s == "" {
+		return 0, nil
+	}
+	mult := uint64(1)
+	switch {
+	case strings.HasSuffix(s, "GB"):
+		s, mult = strings.TrimSuffix(s, "GB"), 1<<30
+	case strings.HasSuffix(s, "MB"):
+		s, mult = strings.TrimSuffix(s, "MB"), 1<<20
+	case strings.HasSuffix(s, "KB"):
+		s, mult = strings.TrimSuffix(s, "KB"), 1<<10
 	}
-	var buf bytes.Buffer
-	// loop over pairs
-	for i := 0; i < len(vals); i += 2 {
-		buf.WriteString(fmt.Sprintf("%s<%s>%s</%s>\n", indent, vals[i], vals[i+1], vals[i]))
+	f, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	if err != nil || f < 0 {
+		return 0, ErrInvalidValue
 	}
-	return buf.Bytes()
+	return uint64(f * float64(mult)), nil
 }
 
-// xmlPairsString builds a string of XML pairs.
-func xmlPairsString(indent string, vals ...string) string {
-	return string(xmlPairs(indent, vals...))
+// dataLimitString formats a data limit in bytes with units, using GB when
+// evenly divisible, and MB otherwise.
+func dataLimitString(limit uint64) string {
+	if limit != 0 && limit%(1<<30) == 0 {
+		return fmt.Sprintf("%dGB", limit>>30)
+	}
+	return fmt.Sprintf("%dMB", limit>>20)
 }
 
-// xmlNvp (ie, name value pair) builds a <Name>name</Name><Value>value</Value> XML pair.
-func xmlNvp(name, value string) string {
-	return xmlPairsString("", "Name", name, "Value", value)
+// samePhone returns true when phone numbers a and b are the same, ignoring
+// formatting and international prefixes.
+func samePhone(a, b string) bool {
+	digits := func(s string) string {
+		return strings.Map(func(r rune) rune {
+			if r < '0' || r > '9' {
+				return -1
+			}
+			return r
+		}, s)
+	}
+	a, b = digits(a), digits(b)
+	if a == "" || b == "" {
+		return false
+	}
+	return strings.HasSuffix(a, b) || strings.HasSuffix(b, a)
 }
 
 // boolToString converts a bool to a "0" or "1".
@@ -43,33 +186,122 @@ func boolToString(b bool) string {
 
 // xmlEncode encodes a map to standard XML values.
 func xmlEncode(v interface{}) (io.Reader, error) {
-	var err error
 	var buf []byte
 	switch x := v.(type) {
 	case []byte:
 		buf = x
+	case *Request:
+		buf = x.Bytes()
 	case XMLData:
-		// wrap in request element
-		m := mxj.Map(map[string]interface{}{
-			"request": map[string]interface{}(x),
-		})
-		// encode xml
-		buf, err = m.XmlIndent("", "  ")
+		buf = requestFromMap(x).Bytes()
+	default:
+		r, err := MarshalRequest(v)
 		if err != nil {
 			return nil, err
 		}
-	default:
-		return nil, errors.New("unsupported type in xmlEncode")
+		buf = r.Bytes()
 	}
 	return bytes.NewReader(buf), nil
 }
 
-// xmlDecode decodes buf into its simple xml values.
+// DecodeXML decodes a device response, returning the child elements of the
+// root element (ie, <response>, or <config> for the static config files).
+// Error responses are returned as an *APIError, while malformed, truncated,
+// or otherwise unexpected responses (ie, HTML pages returned by some
+// firmware) are returned as an error wrapping ErrInvalidXML,
+// ErrMissingRootElement or ErrInvalidError, and never panic.
+func DecodeXML(buf []byte) (XMLData, error) {
+	v, err := xmlDecode(buf, true)
+	if err != nil {
+		return nil, err
+	}
+	return v.(map[string]interface{}), nil
+}
+
+// DecodeXMLRoot decodes a device response or request, returning the root
+// element keyed by its name (ie, <response>, or <request> for requests).
+// Unlike DecodeXML, the root element may be a simple element (ie,
+// <response>OK</response>). Errors are returned the same as DecodeXML.
+func DecodeXMLRoot(buf []byte) (XMLData, error) {
+	v, err := xmlDecode(buf, false)
+	if err != nil {
+		return nil, err
+	}
+	return v.(map[string]interface{}), nil
+}
+
+// UnmarshalXML decodes the root element of a device response into v, a
+// pointer to a struct with encoding/xml field tags, using a streaming
+// encoding/xml decoder. Errors are returned the same as DecodeXML.
+//
+// Unlike DecodeXML, the decoded values have the types of the struct fields,
+// for example:
+//
+//	var res struct {
+//		ConnectionStatus   int    `xml:"ConnectionStatus"`
+//		CurrentNetworkType int    `xml:"CurrentNetworkType"`
+//		PrimaryDns         string `xml:"PrimaryDns"`
+//	}
+//	err := hilink.UnmarshalXML(buf, &res)
+func UnmarshalXML(buf []byte, v interface{}) error {
+	if len(bytes.TrimSpace(buf)) == 0 {
+		return fmt.Errorf("%w: empty response", ErrInvalidXML)
+	}
+	dec := xml.NewDecoder(bytes.NewReader(buf))
+	var start xml.StartElement
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidXML, err)
+		}
+		var ok bool
+		if start, ok = tok.(xml.StartElement); ok {
+			break
+		}
+	}
+	switch {
+	case start.Name.Local == "error":
+		// decode the error the same as DecodeXML
+		_, err := xmlDecode(buf, true)
+		if err == nil {
+			err = ErrInvalidError
+		}
+		return err
+	case strings.EqualFold(start.Name.Local, "html"):
+		return fmt.Errorf("%w: unexpected html content", ErrInvalidXML)
+	}
+	if err := dec.DecodeElement(v, &start); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidXML, err)
+	}
+	return nil
+}
+
+// redactRE matches the passwords, session cookies and tokens in logged
+// request and response data.
+var redactRE = regexp.MustCompile(`(?i)(SessionID=|__RequestVerificationToken[a-z]*:\s*|<(?:\w*password|TokInfo|SesInfo|wifiwpapsk)>)[^;\s<]+`)
+
+// redact redacts passwords, session cookies and tokens in s.
+func redact(s string) string {
+	return redactRE.ReplaceAllString(s, "${1}<redacted>")
+}
+
+// isNotSupported returns true when err is the not supported by firmware
+// (100002) api error, or the path is not supported by the device profile.
+func isNotSupported(err error) bool {
+	return errors.Is(err, ErrNotSupported)
+}
+
+// xmlDecode decodes buf into its simple xml values. When takeFirstEl is true,
+// the child elements of the root element (ie, <response>) are returned as a
+// map, otherwise the decoded root element map is returned.
 func xmlDecode(buf []byte, takeFirstEl bool) (interface{}, error) {
+	if len(bytes.TrimSpace(buf)) == 0 {
+		return nil, fmt.Errorf("%w: empty response", ErrInvalidXML)
+	}
 	// decode xml
-	m, err := mxj.NewMapXml(buf)
+	m, err := xmlDecodeMap(buf)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("%w: %v", ErrInvalidXML, err)
 	}
 	// check if error was returned
 	if e, ok := m["error"]; ok {
@@ -78,12 +310,16 @@ func xmlDecode(buf []byte, takeFirstEl bool) (interface{}, error) {
 			return nil, ErrInvalidError
 		}
 		// grab message if not passed by the api
+		c, _ := z["code"].(string)
 		msg, _ := z["message"].(string)
+		code, err := strconv.Atoi(strings.TrimSpace(c))
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid code %q", ErrInvalidError, c)
+		}
 		if msg == "" {
-			c, _ := z["code"].(string)
 			msg = ErrorMessageFromString(c)
 		}
-		return nil, fmt.Errorf("hilink error %v: %s", z["code"], msg)
+		return nil, &APIError{Code: code, Message: msg}
 	}
 	// check there is only one element
 	if len(m) != 1 {
@@ -93,19 +329,25 @@ func xmlDecode(buf []byte, takeFirstEl bool) (interface{}, error) {
 	if !takeFirstEl {
 		return m, nil
 	}
-	// grab root element
-	rootEl := ""
-	for k := range m {
-		rootEl = k
+	// grab root element (ie, <response> for api endpoints, and <config> for
+	// the static config endpoints)
+	var root string
+	var r interface{}
+	for k, v := range m {
+		root, r = k, v
 	}
-	r, ok := m[rootEl]
-	if !ok {
-		return nil, ErrInvalidResponse
+	// html pages are returned by some firmware instead of errors
+	if strings.EqualFold(root, "html") {
+		return nil, fmt.Errorf("%w: unexpected html content", ErrInvalidXML)
 	}
-	// convert
-	t, ok := r.(map[string]interface{})
-	if !ok {
-		return nil, ErrInvalidXML
+	// convert, treating an empty root element as having no values
+	switch t := r.(type) {
+	case map[string]interface{}:
+		return t, nil
+	case string:
+		if strings.TrimSpace(t) == "" {
+			return map[string]interface{}{}, nil
+		}
 	}
-	return t, nil
+	return nil, fmt.Errorf("%w: unexpected response content", ErrInvalidXML)
 }
@@ -0,0 +1,130 @@
+package hilink
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+)
+
+func TestDecodeXMLConfigRoot(t *testing.T) {
+	buf, err := ioutil.ReadFile(filepath.Join("testdata", "config", "global", "config.xml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path != "/config/global/config.xml" {
+			http.NotFound(w, req)
+			return
+		}
+		_, _ = w.Write(buf)
+	}))
+	defer s.Close()
+	res, err := NewClient(WithURL(s.URL)).GlobalConfig(context.Background())
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if s := xmlStr(res, "sms_enabled"); s != "1" {
+		t.Errorf("expected sms_enabled 1, got: %q", s)
+	}
+	language, ok := res["language"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("expected language map, got: %T", res["language"])
+	}
+	if s := xmlStr(language, "default"); s != "en-us" {
+		t.Errorf("expected default language en-us, got: %q", s)
+	}
+}
+
+func TestDecodeXML(t *testing.T) {
+	tests := []struct {
+		buf string
+		exp error
+	}{
+		{`<response><a>1</a></response>`, nil},
+		{`<config><a>1</a></config>`, nil},
+		{`<response/>`, nil},
+		{`<response>OK</response>`, ErrInvalidXML},
+		{`<html><body><p>login</p></body></html>`, ErrInvalidXML},
+		{`<a>1</a><b>2</b>`, ErrInvalidXML},
+		{``, ErrInvalidXML},
+		{`<error><code>100002</code><message></message></error>`, ErrNotSupported},
+	}
+	for i, test := range tests {
+		_, err := DecodeXML([]byte(test.buf))
+		switch {
+		case test.exp == nil && err != nil:
+			t.Errorf("test %d expected no error, got: %v", i, err)
+		case test.exp != nil && !errors.Is(err, test.exp):
+			t.Errorf("test %d expected %v, got: %v", i, test.exp, err)
+		}
+	}
+}
+
+func TestDecodeXMLRoot(t *testing.T) {
+	m, err := DecodeXMLRoot([]byte(`<response>OK</response>`))
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if s, _ := m["response"].(string); s != "OK" {
+		t.Errorf("expected OK, got: %v", m)
+	}
+	m, err = DecodeXMLRoot([]byte(`<request><Phones><Phone>1</Phone><Phone>2</Phone></Phones></request>`))
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	req, ok := m["request"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("expected request map, got: %T", m["request"])
+	}
+	// XMLData shares the map's underlying type
+	phones, _ := XMLData(req)["Phones"].(map[string]interface{})
+	if l, _ := phones["Phone"].([]interface{}); len(l) != 2 {
+		t.Errorf("expected 2 phones, got: %v", req)
+	}
+	if _, err := DecodeXMLRoot([]byte(`<error><code>100003</code><message></message></error>`)); err == nil {
+		t.Errorf("expected error")
+	}
+}
+
+func TestUnmarshalXML(t *testing.T) {
+	var res struct {
+		ConnectionStatus int    `xml:"ConnectionStatus"`
+		SimStatus        bool   `xml:"SimStatus"`
+		PrimaryDns       string `xml:"PrimaryDns"`
+	}
+	buf := []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<response>
+<ConnectionStatus>901</ConnectionStatus>
+<SimStatus>1</SimStatus>
+<PrimaryDns>10.0.0.1</PrimaryDns>
+</response>`)
+	if err := UnmarshalXML(buf, &res); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if res.ConnectionStatus != 901 || !res.SimStatus || res.PrimaryDns != "10.0.0.1" {
+		t.Errorf("unexpected result: %+v", res)
+	}
+	tests := []struct {
+		buf string
+		exp error
+	}{
+		{`<config><a>1</a></config>`, nil},
+		{`<response><ConnectionStatus>x</ConnectionStatus></response>`, ErrInvalidXML},
+		{`<html><body><p>login</p></body></html>`, ErrInvalidXML},
+		{``, ErrInvalidXML},
+		{`<error><code>100002</code><message></message></error>`, ErrNotSupported},
+	}
+	for i, test := range tests {
+		err := UnmarshalXML([]byte(test.buf), &res)
+		switch {
+		case test.exp == nil && err != nil:
+			t.Errorf("test %d expected no error, got: %v", i, err)
+		case test.exp != nil && !errors.Is(err, test.exp):
+			t.Errorf("test %d expected %v, got: %v", i, test.exp, err)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package hilink
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// WatchSms watches the inbox for new SMS messages, polling the device's unread
+// count every interval. New unread messages are delivered on the returned
+// message channel, and marked as read once received. Errors encountered while
+// polling are delivered on the returned error channel, and do not stop the
+// watch. Both channels are closed when ctx is done.
+//
+// Messages are marked as read before the receiver has processed them. Use
+// WatchSmsFunc when messages must not be lost (ie, when forwarding).
+func (cl *Client) WatchSms(ctx context.Context, interval time.Duration) (<-chan SmsMessage, <-chan error) {
+	msgs, errs := make(chan SmsMessage), make(chan error, 1)
+	go func() {
+		defer close(msgs)
+		defer close(errs)
+		_ = cl.WatchSmsFunc(ctx, interval, func(ctx context.Context, m SmsMessage) error {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case msgs <- m:
+				return nil
+			}
+		}, func(err error) {
+			select {
+			case errs <- err:
+			default:
+			}
+		})
+	}()
+	return msgs, errs
+}
+
+// WatchSmsFunc watches the inbox for new SMS messages, polling the device's
+// unread count every interval, and calling f with each new unread message,
+// oldest first. A message is only marked as read after f returns nil, and is
+// passed to f again on the next poll when f returns an error, providing
+// at-least-once delivery. Errors encountered while polling, and errors
+// returned by f, are passed to errf (when not nil), and do not stop the
+// watch. When polling fails, a new session is started (logging in again when
+// the Auth option was given). Blocks until ctx is done, returning the context
+// error.
+func (cl *Client) WatchSmsFunc(ctx context.Context, interval time.Duration, f func(context.Context, SmsMessage) error, errf func(error)) error {
+	if errf == nil {
+		errf = func(error) {}
+	}
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	// seen tracks handled messages, in case marking them read fails
+	seen := make(map[int]bool)
+	for {
+		l, err := cl.smsUnread(ctx)
+		if err != nil && ctx.Err() == nil {
+			errf(err)
+			// start a new session, as the device may have expired the
+			// session or rebooted
+			if err := cl.Relogin(ctx); err != nil && ctx.Err() == nil {
+				errf(err)
+			}
+		}
+		for _, m := range l {
+			if seen[m.Index] {
+				continue
+			}
+			if err := f(ctx, m); err != nil {
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+				errf(fmt.Errorf("message %d: %w", m.Index, err))
+				continue
+			}
+			seen[m.Index] = true
+			if _, err := cl.SmsReadSet(ctx, fmt.Sprintf("%d", m.Index)); err != nil && ctx.Err() == nil {
+				errf(err)
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-t.C:
+		}
+	}
+}
+
+// smsUnread retrieves the unread messages in the inbox, oldest first.
+func (cl *Client) smsUnread(ctx context.Context) ([]SmsMessage, error) {
+	res, err := cl.SmsCount(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if xmlInt(res, "LocalUnread") == 0 {
+		return nil, nil
+	}
+	list, err := cl.SmsList(ctx, uint(SmsBoxTypeInbox), 1, SmsPageSize, false, true, true)
+	if err != nil {
+		return nil, err
+	}
+	var l []SmsMessage
+	for _, m := range smsMessages(list) {
+		if m.Unread() {
+			l = append(l, m)
+		}
+	}
+	return l, nil
+}
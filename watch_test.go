@@ -0,0 +1,93 @@
+package hilink
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestWatchSmsFunc(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var mu sync.Mutex
+	unread := map[string]bool{"1": true, "2": true}
+	indexRE := regexp.MustCompile(`<Index>(\d+)</Index>`)
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch req.URL.Path {
+		case "/api/sms/sms-count":
+			n := 0
+			for _, v := range unread {
+				if v {
+					n++
+				}
+			}
+			_, _ = w.Write([]byte(`<response><LocalUnread>` + string(rune('0'+n)) + `</LocalUnread></response>`))
+		case "/api/sms/sms-list":
+			var b strings.Builder
+			b.WriteString(`<response><Count>2</Count><Messages>`)
+			for _, i := range []string{"1", "2"} {
+				stat := "1"
+				if unread[i] {
+					stat = "0"
+				}
+				b.WriteString(`<Message><Smstat>` + stat + `</Smstat><Index>` + i + `</Index><Phone>+1555</Phone><Content>msg ` + i + `</Content><Date>2024-01-02 03:04:05</Date></Message>`)
+			}
+			b.WriteString(`</Messages></response>`)
+			_, _ = w.Write([]byte(b.String()))
+		case "/api/sms/set-read":
+			buf, _ := ioutil.ReadAll(req.Body)
+			for _, m := range indexRE.FindAllStringSubmatch(string(buf), -1) {
+				unread[m[1]] = false
+			}
+			if !unread["1"] && !unread["2"] {
+				cancel()
+			}
+			_, _ = w.Write([]byte(`<response>OK</response>`))
+		default:
+			http.NotFound(w, req)
+		}
+	}))
+	defer s.Close()
+	cl := NewClient(WithURL(s.URL))
+	var calls []int
+	var errs []error
+	err := cl.WatchSmsFunc(ctx, 10*time.Millisecond, func(ctx context.Context, m SmsMessage) error {
+		calls = append(calls, m.Index)
+		// fail the first delivery of message 2
+		if m.Index == 2 && len(calls) == 2 {
+			return errors.New("webhook down")
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		// message 2 must not be marked read before it was delivered
+		if m.Index == 2 && !unread["2"] {
+			t.Errorf("expected message 2 to be unread")
+		}
+		return nil
+	}, func(err error) {
+		errs = append(errs, err)
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got: %v", err)
+	}
+	if exp := []int{1, 2, 2}; len(calls) != len(exp) || calls[0] != 1 || calls[1] != 2 || calls[2] != 2 {
+		t.Errorf("expected calls %v, got: %v", exp, calls)
+	}
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "webhook down") {
+		t.Errorf("expected webhook error, got: %v", errs)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if unread["1"] || unread["2"] {
+		t.Errorf("expected all messages to be read, got: %v", unread)
+	}
+}